@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS trigger_revoke_sessions_students ON students;
+DROP TRIGGER IF EXISTS trigger_revoke_sessions_teachers ON teachers;
+DROP FUNCTION IF EXISTS revoke_sessions_on_account_disable;
+DROP TABLE IF EXISTS sessions;
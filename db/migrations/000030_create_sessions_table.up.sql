@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS sessions (
+	id SERIAL PRIMARY KEY,
+	user_id INT NOT NULL,
+	refresh_token_hash VARCHAR(64) UNIQUE NOT NULL,
+	expires_at TIMESTAMP NOT NULL,
+	revoked_at TIMESTAMP,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+
+-- Revoke every session of a student or teacher whose account is disabled
+CREATE OR REPLACE FUNCTION revoke_sessions_on_account_disable()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.status = 'Inactive' AND OLD.status IS DISTINCT FROM NEW.status AND NEW.user_id IS NOT NULL THEN
+        UPDATE sessions
+        SET revoked_at = NOW(), updated_at = NOW()
+        WHERE user_id = NEW.user_id AND revoked_at IS NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_revoke_sessions_students
+AFTER UPDATE OF status ON students
+FOR EACH ROW
+EXECUTE FUNCTION revoke_sessions_on_account_disable();
+
+CREATE TRIGGER trigger_revoke_sessions_teachers
+AFTER UPDATE OF status ON teachers
+FOR EACH ROW
+EXECUTE FUNCTION revoke_sessions_on_account_disable();
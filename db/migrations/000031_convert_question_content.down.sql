@@ -0,0 +1,39 @@
+-- Convert versioned question content back to the legacy key convention
+CREATE OR REPLACE FUNCTION convert_question_content_to_legacy(content JSONB)
+RETURNS JSONB AS $$
+DECLARE
+    question JSONB;
+    option JSONB;
+    num TEXT;
+    legacy JSONB := '{}'::JSONB;
+BEGIN
+    IF content IS NULL OR NOT (content ? 'version') THEN
+        RETURN content;
+    END IF;
+
+    FOR question IN SELECT * FROM jsonb_array_elements(content -> 'questions')
+    LOOP
+        num := question ->> 'number';
+        IF question ->> 'type' = 'essay' THEN
+            legacy := legacy || jsonb_build_object(num || '_essay', question ->> 'prompt');
+            IF COALESCE(question ->> 'explanation', '') <> '' THEN
+                legacy := legacy || jsonb_build_object(num || '_rubric', question ->> 'explanation');
+            END IF;
+        ELSE
+            legacy := legacy || jsonb_build_object(num, question ->> 'prompt');
+            FOR option IN SELECT * FROM jsonb_array_elements(COALESCE(question -> 'options', '[]'::JSONB))
+            LOOP
+                legacy := legacy || jsonb_build_object(num || '_' || (option ->> 'key'), option ->> 'text');
+            END LOOP;
+            legacy := legacy || jsonb_build_object(num || '_answer', COALESCE(question ->> 'correct_answer', ''));
+        END IF;
+    END LOOP;
+
+    RETURN legacy;
+END;
+$$ LANGUAGE plpgsql;
+
+UPDATE exams SET content = convert_question_content_to_legacy(content);
+UPDATE exercises SET content = convert_question_content_to_legacy(content);
+
+DROP FUNCTION convert_question_content_to_legacy(JSONB);
@@ -0,0 +1,74 @@
+-- Convert legacy content ({"1": "...", "1_a": "...", "1_answer": "a", "2_essay": "..."})
+-- into the versioned question schema ({"version": 1, "questions": [...]})
+CREATE OR REPLACE FUNCTION convert_legacy_question_content(legacy JSONB)
+RETURNS JSONB AS $$
+DECLARE
+    num TEXT;
+    question JSONB;
+    options JSONB;
+    answer TEXT;
+    questions JSONB := '[]'::JSONB;
+BEGIN
+    IF legacy IS NULL OR jsonb_typeof(legacy) <> 'object' OR legacy ? 'version' THEN
+        RETURN legacy;
+    END IF;
+
+    FOR num IN
+        SELECT DISTINCT substring(key FROM '^[0-9]+')::INT
+        FROM jsonb_object_keys(legacy) AS key
+        WHERE key ~ '^[0-9]+'
+        ORDER BY 1
+    LOOP
+        IF legacy ? (num || '_essay') THEN
+            question := jsonb_build_object(
+                'number', num::INT,
+                'type', 'essay',
+                'prompt', legacy ->> (num || '_essay'),
+                'weight', 1
+            );
+            -- Rubrik lama berupa teks bebas, disimpan sebagai penjelasan untuk penilai
+            IF legacy ? (num || '_rubric') THEN
+                question := question || jsonb_build_object('explanation', legacy ->> (num || '_rubric'));
+            END IF;
+        ELSE
+            IF legacy ? (num || '_rubric') THEN
+                RAISE EXCEPTION 'question % has a rubric but is not an essay: %', num, legacy;
+            END IF;
+
+            SELECT COALESCE(jsonb_agg(jsonb_build_object(
+                       'key', substring(key FROM length(num) + 2),
+                       'text', value
+                   ) ORDER BY key), '[]'::JSONB)
+            INTO options
+            FROM jsonb_each_text(legacy)
+            WHERE key LIKE num || '\_%'
+              AND key NOT IN (num || '_answer', num || '_essay', num || '_rubric');
+
+            answer := legacy ->> (num || '_answer');
+            -- Jawaban lama kadang berisi teks opsi, bukan kuncinya
+            SELECT COALESCE(
+                       (SELECT opt ->> 'key' FROM jsonb_array_elements(options) AS opt WHERE opt ->> 'key' = answer LIMIT 1),
+                       (SELECT opt ->> 'key' FROM jsonb_array_elements(options) AS opt WHERE opt ->> 'text' = answer LIMIT 1),
+                       answer)
+            INTO answer;
+
+            question := jsonb_build_object(
+                'number', num::INT,
+                'type', 'multiple_choice',
+                'prompt', COALESCE(legacy ->> num, ''),
+                'options', options,
+                'correct_answer', COALESCE(answer, ''),
+                'weight', 1
+            );
+        END IF;
+        questions := questions || jsonb_build_array(question);
+    END LOOP;
+
+    RETURN jsonb_build_object('version', 1, 'questions', questions);
+END;
+$$ LANGUAGE plpgsql;
+
+UPDATE exams SET content = convert_legacy_question_content(content);
+UPDATE exercises SET content = convert_legacy_question_content(content);
+
+DROP FUNCTION convert_legacy_question_content(JSONB);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS question_bank_usages;
+DROP TABLE IF EXISTS question_bank;
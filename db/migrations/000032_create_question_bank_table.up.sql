@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS question_bank (
+	id SERIAL PRIMARY KEY,
+	teacher_id INT NOT NULL,
+	subject VARCHAR(100) NOT NULL,
+	grade INT NOT NULL,
+	topic VARCHAR(255) NOT NULL,
+	difficulty VARCHAR(10) NOT NULL CHECK (difficulty IN ('easy', 'medium', 'hard')),
+	tags TEXT[] NOT NULL DEFAULT '{}',
+	question JSONB NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (teacher_id) REFERENCES teachers(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_question_bank_filters ON question_bank(subject, grade, difficulty);
+CREATE INDEX IF NOT EXISTS idx_question_bank_tags ON question_bank USING GIN (tags);
+
+-- Where each bank question has appeared, once per exam or exercise; kept in sync when an exam or exercise is updated
+CREATE TABLE IF NOT EXISTS question_bank_usages (
+	id SERIAL PRIMARY KEY,
+	question_id INT NOT NULL,
+	exam_id INT,
+	exercise_id INT,
+	question_number INT NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (question_id) REFERENCES question_bank(id) ON DELETE CASCADE,
+	FOREIGN KEY (exam_id) REFERENCES exams(id) ON DELETE CASCADE,
+	FOREIGN KEY (exercise_id) REFERENCES exercises(id) ON DELETE CASCADE,
+	CHECK ((exam_id IS NULL) <> (exercise_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_question_bank_usages_exam ON question_bank_usages(question_id, exam_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_question_bank_usages_exercise ON question_bank_usages(question_id, exercise_id);
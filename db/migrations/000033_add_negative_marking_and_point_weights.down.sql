@@ -0,0 +1,3 @@
+-- Weights keep their point values; the old grading only used them as proportions
+ALTER TABLE exams
+DROP COLUMN IF EXISTS negative_marking;
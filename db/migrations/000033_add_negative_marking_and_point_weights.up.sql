@@ -0,0 +1,36 @@
+ALTER TABLE exams
+ADD COLUMN negative_marking DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (negative_marking >= 0 AND negative_marking <= 1);
+
+-- Question weights become points: rescale them so that they add up to total_marks
+UPDATE exams e
+SET content = jsonb_set(e.content, '{questions}', (
+    SELECT jsonb_agg(jsonb_set(q, '{weight}', to_jsonb((q ->> 'weight')::DOUBLE PRECISION * e.total_marks / w.total)) ORDER BY ord)
+    FROM jsonb_array_elements(e.content -> 'questions') WITH ORDINALITY AS t(q, ord)
+))
+FROM (
+    SELECT id, SUM((q ->> 'weight')::DOUBLE PRECISION) AS total
+    FROM exams, jsonb_array_elements(content -> 'questions') AS q
+    GROUP BY id
+) w
+WHERE w.id = e.id AND e.total_marks > 0 AND w.total > 0;
+
+UPDATE exercises e
+SET content = jsonb_set(e.content, '{questions}', (
+    SELECT jsonb_agg(jsonb_set(q, '{weight}', to_jsonb((q ->> 'weight')::DOUBLE PRECISION * e.total_marks / w.total)) ORDER BY ord)
+    FROM jsonb_array_elements(e.content -> 'questions') WITH ORDINALITY AS t(q, ord)
+))
+FROM (
+    SELECT id, SUM((q ->> 'weight')::DOUBLE PRECISION) AS total
+    FROM exercises, jsonb_array_elements(content -> 'questions') AS q
+    GROUP BY id
+) w
+WHERE w.id = e.id AND e.total_marks > 0 AND w.total > 0;
+
+-- Rows without total_marks take the sum of their weights
+UPDATE exams e
+SET total_marks = (SELECT ROUND(SUM((q ->> 'weight')::DOUBLE PRECISION)) FROM jsonb_array_elements(e.content -> 'questions') AS q)
+WHERE COALESCE(e.total_marks, 0) = 0;
+
+UPDATE exercises e
+SET total_marks = (SELECT ROUND(SUM((q ->> 'weight')::DOUBLE PRECISION)) FROM jsonb_array_elements(e.content -> 'questions') AS q)
+WHERE COALESCE(e.total_marks, 0) = 0;
@@ -0,0 +1,7 @@
+ALTER TABLE exam_answers
+DROP COLUMN IF EXISTS late_seconds,
+DROP COLUMN IF EXISTS is_late,
+DROP COLUMN IF EXISTS submitted_at;
+
+ALTER TABLE exams
+DROP COLUMN IF EXISTS late_grace_minutes;
@@ -0,0 +1,12 @@
+ALTER TABLE exams
+ADD COLUMN late_grace_minutes INT NOT NULL DEFAULT 0 CHECK (late_grace_minutes >= 0);
+
+-- exam_answers never received the student_id and status columns that the code writes
+ALTER TABLE exam_answers
+ADD COLUMN IF NOT EXISTS student_id INT REFERENCES students(id) ON DELETE CASCADE,
+ADD COLUMN IF NOT EXISTS status VARCHAR(10),
+ADD COLUMN submitted_at TIMESTAMP,
+ADD COLUMN is_late BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN late_seconds INT NOT NULL DEFAULT 0;
+
+UPDATE exam_answers SET submitted_at = created_at WHERE submitted_at IS NULL;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS exam_attempts;
+
+ALTER TABLE exams
+DROP COLUMN IF EXISTS duration_minutes;
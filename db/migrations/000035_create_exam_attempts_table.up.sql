@@ -0,0 +1,17 @@
+ALTER TABLE exams
+ADD COLUMN duration_minutes INT NOT NULL DEFAULT 0 CHECK (duration_minutes >= 0);
+
+CREATE TABLE IF NOT EXISTS exam_attempts (
+	id SERIAL PRIMARY KEY,
+	exam_id INT NOT NULL,
+	student_id INT NOT NULL,
+	started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	deadline TIMESTAMP NOT NULL,
+	finalized_at TIMESTAMP,
+	status VARCHAR(20) NOT NULL DEFAULT 'In Progress',
+	FOREIGN KEY (exam_id) REFERENCES exams(id) ON DELETE CASCADE,
+	FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE CASCADE,
+	UNIQUE (exam_id, student_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_exam_attempts_open_deadline ON exam_attempts(deadline) WHERE status = 'In Progress';
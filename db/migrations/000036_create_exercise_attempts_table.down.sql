@@ -0,0 +1,8 @@
+ALTER TABLE exercise_scores
+DROP COLUMN IF EXISTS attempt_id;
+
+DROP TABLE IF EXISTS exercise_attempts;
+
+ALTER TABLE exercises
+DROP COLUMN IF EXISTS score_policy,
+DROP COLUMN IF EXISTS max_attempts;
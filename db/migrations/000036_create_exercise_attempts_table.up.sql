@@ -0,0 +1,38 @@
+ALTER TABLE exercises
+ADD COLUMN max_attempts INT NOT NULL DEFAULT 0 CHECK (max_attempts >= 0),
+ADD COLUMN score_policy VARCHAR(10) NOT NULL DEFAULT 'latest' CHECK (score_policy IN ('highest', 'latest', 'average'));
+
+CREATE TABLE IF NOT EXISTS exercise_attempts (
+	id SERIAL PRIMARY KEY,
+	exercise_id INT NOT NULL,
+	student_id INT NOT NULL,
+	attempt_number INT NOT NULL,
+	answers JSONB,
+	submitted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (exercise_id) REFERENCES exercises(id) ON DELETE CASCADE,
+	FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE CASCADE,
+	UNIQUE (exercise_id, student_id, attempt_number)
+);
+
+ALTER TABLE exercise_scores
+ADD COLUMN attempt_id INT REFERENCES exercise_attempts(id) ON DELETE CASCADE;
+
+-- Every existing score becomes an attempt; the answers of those attempts were not kept
+INSERT INTO exercise_attempts (exercise_id, student_id, attempt_number, submitted_at)
+SELECT exercise_id, student_id,
+       ROW_NUMBER() OVER (PARTITION BY exercise_id, student_id ORDER BY id),
+       created_at
+FROM exercise_scores
+WHERE exercise_id IS NOT NULL AND student_id IS NOT NULL;
+
+UPDATE exercise_scores s
+SET attempt_id = a.id
+FROM (
+    SELECT id, exercise_id, student_id, ROW_NUMBER() OVER (PARTITION BY exercise_id, student_id ORDER BY id) AS attempt_number
+    FROM exercise_scores
+) numbered
+JOIN exercise_attempts a
+  ON a.exercise_id = numbered.exercise_id
+ AND a.student_id = numbered.student_id
+ AND a.attempt_number = numbered.attempt_number
+WHERE s.id = numbered.id;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS grading_job_items;
+DROP TABLE IF EXISTS grading_jobs;
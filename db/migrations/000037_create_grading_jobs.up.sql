@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS grading_jobs (
+	id SERIAL PRIMARY KEY,
+	exercise_id INT NOT NULL,
+	student_id INT NOT NULL,
+	answers JSONB NOT NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'Queued',
+	error TEXT,
+	score_id INT,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	completed_at TIMESTAMP,
+	FOREIGN KEY (exercise_id) REFERENCES exercises(id) ON DELETE CASCADE,
+	FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE CASCADE,
+	FOREIGN KEY (score_id) REFERENCES exercise_scores(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS grading_job_items (
+	id SERIAL PRIMARY KEY,
+	job_id INT NOT NULL,
+	question_key VARCHAR(20) NOT NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'Pending',
+	attempts INT NOT NULL DEFAULT 0,
+	score DOUBLE PRECISION,
+	justification TEXT,
+	error TEXT,
+	locked_at TIMESTAMP,
+	next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (job_id) REFERENCES grading_jobs(id) ON DELETE CASCADE,
+	UNIQUE (job_id, question_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_grading_jobs_open ON grading_jobs(id) WHERE status IN ('Queued', 'Running');
+CREATE INDEX IF NOT EXISTS idx_grading_job_items_pending ON grading_job_items(next_attempt_at) WHERE status = 'Pending';
@@ -0,0 +1,2 @@
+ALTER TABLE grading_job_items
+DROP COLUMN IF EXISTS criteria;
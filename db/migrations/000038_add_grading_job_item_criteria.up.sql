@@ -0,0 +1,2 @@
+ALTER TABLE grading_job_items
+ADD COLUMN criteria JSONB;
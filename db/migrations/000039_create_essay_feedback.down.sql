@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS essay_feedback;
+
+ALTER TABLE grading_job_items
+DROP COLUMN IF EXISTS feedback;
+
+ALTER TABLE exercise_scores
+DROP COLUMN IF EXISTS feedback_released_at;
@@ -0,0 +1,18 @@
+ALTER TABLE exercise_scores
+ADD COLUMN feedback_released_at TIMESTAMP;
+
+ALTER TABLE grading_job_items
+ADD COLUMN feedback TEXT;
+
+CREATE TABLE IF NOT EXISTS essay_feedback (
+	id SERIAL PRIMARY KEY,
+	score_id INT NOT NULL,
+	question_key VARCHAR(20) NOT NULL,
+	generated TEXT NOT NULL DEFAULT '',
+	feedback TEXT NOT NULL DEFAULT '',
+	edited_by INT,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (score_id) REFERENCES exercise_scores(id) ON DELETE CASCADE,
+	FOREIGN KEY (edited_by) REFERENCES users(id) ON DELETE SET NULL,
+	UNIQUE (score_id, question_key)
+);
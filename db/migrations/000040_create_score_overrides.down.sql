@@ -0,0 +1 @@
+DROP TABLE IF EXISTS score_overrides;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS score_overrides (
+	id SERIAL PRIMARY KEY,
+	exam_score_id INT,
+	exercise_score_id INT,
+	question_key VARCHAR(20) NOT NULL,
+	auto_score DOUBLE PRECISION NOT NULL,
+	previous_score DOUBLE PRECISION NOT NULL,
+	new_score DOUBLE PRECISION NOT NULL,
+	comment TEXT NOT NULL DEFAULT '',
+	overridden_by INT,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (exam_score_id) REFERENCES exam_scores(id) ON DELETE CASCADE,
+	FOREIGN KEY (exercise_score_id) REFERENCES exercise_scores(id) ON DELETE CASCADE,
+	FOREIGN KEY (overridden_by) REFERENCES users(id) ON DELETE SET NULL,
+	CHECK ((exam_score_id IS NULL) <> (exercise_score_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_score_overrides_exam_score ON score_overrides(exam_score_id);
+CREATE INDEX IF NOT EXISTS idx_score_overrides_exercise_score ON score_overrides(exercise_score_id);
@@ -0,0 +1,9 @@
+DELETE FROM grading_jobs WHERE exam_score_id IS NOT NULL;
+
+ALTER TABLE grading_jobs
+DROP CONSTRAINT IF EXISTS grading_jobs_target_check,
+DROP COLUMN IF EXISTS exam_score_id,
+ALTER COLUMN exercise_id SET NOT NULL;
+
+ALTER TABLE exams
+DROP COLUMN IF EXISTS essay_grading;
@@ -0,0 +1,8 @@
+ALTER TABLE exams
+ADD COLUMN essay_grading VARCHAR(10) NOT NULL DEFAULT 'auto' CHECK (essay_grading IN ('auto', 'manual', 'queued'));
+
+ALTER TABLE grading_jobs
+ALTER COLUMN exercise_id DROP NOT NULL,
+ADD COLUMN exam_score_id INT,
+ADD FOREIGN KEY (exam_score_id) REFERENCES exam_scores(id) ON DELETE CASCADE,
+ADD CONSTRAINT grading_jobs_target_check CHECK ((exercise_id IS NULL) <> (exam_score_id IS NULL));
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS score_history (
+	id SERIAL PRIMARY KEY,
+	exam_score_id INT,
+	exercise_score_id INT,
+	old_score DOUBLE PRECISION NOT NULL,
+	new_score DOUBLE PRECISION NOT NULL,
+	old_detail JSONB,
+	reason TEXT NOT NULL DEFAULT '',
+	changed_by INT,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (exam_score_id) REFERENCES exam_scores(id) ON DELETE CASCADE,
+	FOREIGN KEY (exercise_score_id) REFERENCES exercise_scores(id) ON DELETE CASCADE,
+	FOREIGN KEY (changed_by) REFERENCES users(id) ON DELETE SET NULL,
+	CHECK ((exam_score_id IS NULL) <> (exercise_score_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_score_history_exam_score ON score_history(exam_score_id);
+CREATE INDEX IF NOT EXISTS idx_score_history_exercise_score ON score_history(exercise_score_id);
@@ -0,0 +1,3 @@
+ALTER TABLE exams
+DROP COLUMN IF EXISTS released_at,
+DROP COLUMN IF EXISTS release_policy;
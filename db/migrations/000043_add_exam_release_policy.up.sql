@@ -0,0 +1,3 @@
+ALTER TABLE exams
+ADD COLUMN release_policy VARCHAR(20) NOT NULL DEFAULT 'immediate' CHECK (release_policy IN ('immediate', 'after_close', 'manual')),
+ADD COLUMN released_at TIMESTAMP;
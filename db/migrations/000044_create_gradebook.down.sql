@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS class_final_grades;
+DROP TABLE IF EXISTS gradebook_settings;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS gradebook_settings (
+	class_id INT PRIMARY KEY,
+	exercise_weight DOUBLE PRECISION NOT NULL DEFAULT 30,
+	exam_weight DOUBLE PRECISION NOT NULL DEFAULT 70,
+	drop_lowest_exercises INT NOT NULL DEFAULT 0 CHECK (drop_lowest_exercises >= 0),
+	drop_lowest_exams INT NOT NULL DEFAULT 0 CHECK (drop_lowest_exams >= 0),
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (class_id) REFERENCES classes(id) ON DELETE CASCADE,
+	CHECK (exercise_weight >= 0 AND exam_weight >= 0 AND exercise_weight + exam_weight = 100)
+);
+
+CREATE TABLE IF NOT EXISTS class_final_grades (
+	class_id INT NOT NULL,
+	student_id INT NOT NULL,
+	exercise_average DOUBLE PRECISION,
+	exam_average DOUBLE PRECISION,
+	final_grade DOUBLE PRECISION,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (class_id, student_id),
+	FOREIGN KEY (class_id) REFERENCES classes(id) ON DELETE CASCADE,
+	FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_class_final_grades_student ON class_final_grades(student_id);
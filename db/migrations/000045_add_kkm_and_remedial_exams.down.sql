@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS exam_participants;
+
+ALTER TABLE exam_scores
+DROP COLUMN IF EXISTS remedial_score_id,
+DROP COLUMN IF EXISTS pre_remedial_score;
+
+DELETE FROM exams WHERE remedial_of IS NOT NULL;
+
+ALTER TABLE exams
+DROP COLUMN IF EXISTS remedial_policy,
+DROP COLUMN IF EXISTS remedial_of,
+DROP COLUMN IF EXISTS kkm;
+
+ALTER TABLE gradebook_settings
+DROP COLUMN IF EXISTS kkm;
@@ -0,0 +1,21 @@
+ALTER TABLE gradebook_settings
+ADD COLUMN kkm DOUBLE PRECISION NOT NULL DEFAULT 75 CHECK (kkm >= 0 AND kkm <= 100);
+
+ALTER TABLE exams
+ADD COLUMN kkm DOUBLE PRECISION CHECK (kkm >= 0 AND kkm <= 100),
+ADD COLUMN remedial_of INT,
+ADD COLUMN remedial_policy VARCHAR(20) NOT NULL DEFAULT 'cap_at_kkm' CHECK (remedial_policy IN ('cap_at_kkm', 'highest', 'average')),
+ADD FOREIGN KEY (remedial_of) REFERENCES exams(id) ON DELETE CASCADE;
+
+ALTER TABLE exam_scores
+ADD COLUMN pre_remedial_score DOUBLE PRECISION,
+ADD COLUMN remedial_score_id INT,
+ADD FOREIGN KEY (remedial_score_id) REFERENCES exam_scores(id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS exam_participants (
+	exam_id INT NOT NULL,
+	student_id INT NOT NULL,
+	PRIMARY KEY (exam_id, student_id),
+	FOREIGN KEY (exam_id) REFERENCES exams(id) ON DELETE CASCADE,
+	FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE CASCADE
+);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS report_card_notes (
+	id SERIAL PRIMARY KEY,
+	student_id INT NOT NULL,
+	semester VARCHAR(20) NOT NULL,
+	remarks TEXT NOT NULL DEFAULT '',
+	sick_days INT CHECK (sick_days >= 0),
+	permitted_days INT CHECK (permitted_days >= 0),
+	absent_days INT CHECK (absent_days >= 0),
+	written_by INT,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (student_id, semester),
+	FOREIGN KEY (student_id) REFERENCES students(id) ON DELETE CASCADE,
+	FOREIGN KEY (written_by) REFERENCES users(id) ON DELETE SET NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE exam_answers DROP CONSTRAINT IF EXISTS unique_exam_answers_exam_student;
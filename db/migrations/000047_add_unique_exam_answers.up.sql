@@ -0,0 +1,14 @@
+-- Satu lembar jawaban per siswa per ujian; yang sudah dikumpulkan atau yang terakhir disimpan dipertahankan
+DELETE FROM exam_answers
+WHERE id IN (
+	SELECT id FROM (
+		SELECT id, ROW_NUMBER() OVER (
+			PARTITION BY exam_id, student_id
+			ORDER BY status IS NOT DISTINCT FROM 'Submitted' DESC, id DESC
+		) AS position
+		FROM exam_answers
+	) ranked
+	WHERE position > 1
+);
+
+ALTER TABLE exam_answers ADD CONSTRAINT unique_exam_answers_exam_student UNIQUE (exam_id, student_id);
@@ -17,7 +17,7 @@ const docTemplate = `{
     "paths": {
         "/api/v1/auth": {
             "post": {
-                "description": "Auth API to differentiate roles",
+                "description": "Auth API to differentiate roles, returns a short-lived access token and a refresh token",
                 "consumes": [
                     "application/json"
                 ],
@@ -42,12 +42,116 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AuthTokens"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/logout": {
+            "post": {
+                "description": "Revoke the session owning the refresh token. Access tokens of that session are rejected right away.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Logout",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new access token. The refresh token is rotated and the old one stops working.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Refresh access token",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AuthTokens"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -437,6 +541,192 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/classes/final-grade": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a student's running final grade in a class",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Gradebook"
+                ],
+                "summary": "Get Final Grade",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Class ID",
+                        "name": "class_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Student ID",
+                        "name": "student_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FinalGrade"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/classes/gradebook": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the category weights of a class with the running final grade of every student",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Gradebook"
+                ],
+                "summary": "Get Gradebook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Class ID",
+                        "name": "class_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Gradebook"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the exercise and exam weights of a class, how many of the lowest scores to drop and its KKM, then recompute its final grades",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Gradebook"
+                ],
+                "summary": "Update Gradebook Settings",
+                "parameters": [
+                    {
+                        "description": "Gradebook settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateGradebookSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GradebookSettings"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/classes/report": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the performance of a class over its exams with the mean, median, standard deviation, histogram and pass rate, compared with the classes of the same grade. Only the class's teacher or an admin can see it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Class Reports"
+                ],
+                "summary": "Get Class Report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Class ID",
+                        "name": "class_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Passing percentage, 75 by default",
+                        "name": "passing_score",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to csv to download the report as CSV",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ClassReport"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/classes/unassign-students": {
             "delete": {
                 "security": [
@@ -712,7 +1002,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Fetch exam from a class",
+                "description": "Fetch exam from a class. Students get no questions of exams that have not started or, when timed, that they have no attempt in progress for.",
                 "consumes": [
                     "application/json"
                 ],
@@ -750,7 +1040,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new exam",
+                "description": "Create a new exam. With remedial_of it becomes a remedial exam given to student_ids, or to every student below the original exam's KKM.",
                 "consumes": [
                     "application/json"
                 ],
@@ -852,6 +1142,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.CreateExamsRequest"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Regrade stored scores when answer keys or weights change",
+                        "name": "confirm_regrade",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -860,6 +1156,13 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.Exams"
                         }
+                    },
+                    "409": {
+                        "description": "Stored scores would change; includes a preview",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
@@ -916,7 +1219,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new exam",
+                "description": "Submit answers for an exam. Answers before start_time or after the late grace period are rejected.",
                 "consumes": [
                     "application/json"
                 ],
@@ -944,8 +1247,26 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.ExamAnswers"
                         }
-                    }
-                }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
             },
             "delete": {
                 "security": [
@@ -953,7 +1274,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete an exam answer by ID",
+                "description": "Delete an exam answer by ID. Submitted answers cannot be deleted.",
                 "consumes": [
                     "application/json"
                 ],
@@ -982,6 +1303,24 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             },
@@ -991,7 +1330,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing exam answer",
+                "description": "Update an existing exam answer. Answers submitted after end_time are flagged as late.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1026,18 +1365,45 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.ExamAnswers"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/api/v1/exams/calculate-grade": {
-            "post": {
+        "/api/v1/exams-answers/autosave": {
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Calculate grade after submitting exam",
+                "description": "Save a single answer into the student's draft without overwriting the other answers. The draft is created on the first autosave.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1047,15 +1413,15 @@ const docTemplate = `{
                 "tags": [
                     "Exam Answers (Student Answers)"
                 ],
-                "summary": "Calculate Grade",
+                "summary": "Autosave Exam Answer",
                 "parameters": [
                     {
-                        "description": "Exam grades data",
-                        "name": "exam",
+                        "description": "Question number and answer",
+                        "name": "answer",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CalculateExamGrades"
+                            "$ref": "#/definitions/models.AutosaveExamAnswerRequest"
                         }
                     }
                 ],
@@ -1063,20 +1429,38 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ExamGrades"
+                            "$ref": "#/definitions/models.ExamAnswers"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/api/v1/exams/get-all-grade": {
-            "get": {
+        "/api/v1/exams-answers/submit": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get calculated grades for all exams",
+                "description": "Freeze the student's draft so it can no longer change, then grade it",
                 "consumes": [
                     "application/json"
                 ],
@@ -1086,14 +1470,16 @@ const docTemplate = `{
                 "tags": [
                     "Exam Answers (Student Answers)"
                 ],
-                "summary": "Get ALl Grades",
+                "summary": "Submit Exam Answers",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Student ID",
-                        "name": "student_id",
-                        "in": "query",
-                        "required": true
+                        "description": "Exam and student",
+                        "name": "submission",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SubmitExamAnswersRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -1102,18 +1488,45 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.ExamGrades"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/api/v1/exams/get-grade": {
+        "/api/v1/exams/attempt": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get calculated grade",
+                "description": "Get the student's timed attempt, including its deadline",
                 "consumes": [
                     "application/json"
                 ],
@@ -1121,9 +1534,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Exam Answers (Student Answers)"
+                    "Exam Attempts"
                 ],
-                "summary": "Get Grade",
+                "summary": "Get Exam Attempt",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1144,20 +1557,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ExamGrades"
+                            "$ref": "#/definitions/models.ExamAttempt"
                         }
                     }
                 }
             }
         },
-        "/api/v1/exams/student": {
+        "/api/v1/exams/below-kkm": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Fetch all exams for a specific class",
+                "description": "List the students whose latest score on an exam is below its KKM, the candidates for a remedial exam. The exam's KKM falls back to the class's.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1165,21 +1578,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Exams"
+                    "Remedial Exams"
                 ],
-                "summary": "Get Exams for Student",
+                "summary": "Get Students Below KKM",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Class ID",
-                        "name": "class_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number",
-                        "name": "number",
+                        "description": "Exam ID",
+                        "name": "exam_id",
                         "in": "query",
                         "required": true
                     }
@@ -1188,23 +1594,29 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Exams"
+                            "$ref": "#/definitions/models.BelowKKM"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
             }
         },
-        "/api/v1/exercises": {
-            "get": {
+        "/api/v1/exams/calculate-grade": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Fetch all exercises for a specific material",
+                "description": "Submit the student's answers and grade them, like /exams-answers/submit. Answers are graded only once.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1212,37 +1624,65 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Exercises"
+                    "Exam Answers (Student Answers)"
                 ],
-                "summary": "Get Exercises by Material ID",
+                "summary": "Calculate Grade",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Material ID",
-                        "name": "material_id",
-                        "in": "query",
-                        "required": true
+                        "description": "Exam grades data",
+                        "name": "exam",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CalculateExamGrades"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Exercises"
+                            "$ref": "#/definitions/models.ExamGrades"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/v1/exams/get-all-grade": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new exercise",
+                "description": "Get calculated grades for all exams. Scores that are not released yet are withheld from students.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1250,36 +1690,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Exercises"
+                    "Exam Answers (Student Answers)"
                 ],
-                "summary": "Create Exercise",
+                "summary": "Get ALl Grades",
                 "parameters": [
                     {
-                        "description": "Exercise data",
-                        "name": "exercise",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateExercisesRequest"
-                        }
+                        "type": "integer",
+                        "description": "Student ID",
+                        "name": "student_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Exercises"
+                            "$ref": "#/definitions/models.ExamGrades"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/exams/get-grade": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete an exercise by ID",
+                "description": "Get calculated grade. Students only see the score once the exam's release policy allows it; until then the status is submitted.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1287,14 +1727,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Exercises"
+                    "Exam Answers (Student Answers)"
                 ],
-                "summary": "Delete Exercise",
+                "summary": "Get Grade",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Exercise ID",
-                        "name": "id",
+                        "description": "Exam ID",
+                        "name": "exam_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Student ID",
+                        "name": "student_id",
                         "in": "query",
                         "required": true
                     }
@@ -1303,21 +1750,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.ExamGrades"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/api/v1/exams/item-analysis": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing exercise",
+                "description": "Get the difficulty, discrimination, distractor frequencies and average score of every question in the exam",
                 "consumes": [
                     "application/json"
                 ],
@@ -1325,45 +1771,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Exercises"
+                    "Exam Analytics"
                 ],
-                "summary": "Update Exercise",
+                "summary": "Get Exam Item Analysis",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Exercise ID",
-                        "name": "id",
+                        "description": "Exam ID",
+                        "name": "exam_id",
                         "in": "query",
                         "required": true
-                    },
-                    {
-                        "description": "Updated data",
-                        "name": "exercise",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateExercisesRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Exercises"
+                            "$ref": "#/definitions/models.ItemAnalysis"
                         }
-                    }
-                }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
             }
         },
-        "/api/v1/exercises-answers": {
-            "get": {
+        "/api/v1/exams/override-score": {
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Fetch all exercises for a specific material",
+                "description": "Replace the score of one question in a graded exam and recompute the total. Also grades pending essays of exams with manual essay grading. The original auto score, the change, who made it and when are kept in the audit trail.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1371,44 +1817,56 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Exercise Answers (Student Answers)"
+                    "Exam Overrides"
                 ],
-                "summary": "Get Exercise Answers by Exercise ID",
+                "summary": "Override Exam Question Score",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Exercise ID",
-                        "name": "exercise_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Student ID",
-                        "name": "student_id",
-                        "in": "query",
-                        "required": true
+                        "description": "Score, question, new score and comment",
+                        "name": "override",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OverrideScoreRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.ExerciseAnswers"
+                            "$ref": "#/definitions/models.ExamGrades"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/exams/release": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new exercise",
+                "description": "Make the scores of an exam and its review visible to students, whatever its release policy",
                 "consumes": [
                     "application/json"
                 ],
@@ -1416,58 +1874,77 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Exercise Answers (Student Answers)"
+                    "Exam Release"
                 ],
-                "summary": "Create Exercise Answers",
+                "summary": "Release Exam Scores",
                 "parameters": [
                     {
-                        "description": "Exercise answers data",
-                        "name": "exercise",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateExerciseAnswersRequest"
-                        }
+                        "type": "integer",
+                        "description": "Exam ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ExerciseAnswers"
+                            "$ref": "#/definitions/models.Exams"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/exams/report": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete an exercise answer by ID",
+                "description": "Get the score distribution of an exam: mean, median, standard deviation, histogram and pass rate, per student as well. Only the class's teacher or an admin can see it.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "tags": [
-                    "Exercise Answers (Student Answers)"
+                    "Exam Analytics"
                 ],
-                "summary": "Delete Exercise Answer",
+                "summary": "Get Exam Report",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Exercise ID",
-                        "name": "id",
+                        "description": "Exam ID",
+                        "name": "exam_id",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Passing percentage, 75 by default",
+                        "name": "passing_score",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to csv to download the report as CSV",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ExamReport"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1476,14 +1953,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/api/v1/exams/review": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing exercise answer",
+                "description": "Get the student's graded answers next to the correct answers and explanations. Students only see it once the scores are released.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1491,45 +1970,61 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Exercise Answers (Student Answers)"
+                    "Exam Release"
                 ],
-                "summary": "Update Exercise Answer",
+                "summary": "Get Exam Review",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Exercise Answer ID (Student's Answer ID)",
-                        "name": "id",
+                        "description": "Exam ID",
+                        "name": "exam_id",
                         "in": "query",
                         "required": true
                     },
                     {
-                        "description": "Updated data",
-                        "name": "exercise",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateExerciseAnswersRequest"
-                        }
+                        "type": "integer",
+                        "description": "Student ID",
+                        "name": "student_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ExerciseAnswers"
+                            "$ref": "#/definitions/models.ExamReview"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/api/v1/exercises/calculate-grade": {
-            "post": {
+        "/api/v1/exams/score-history": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Calculate grade after submitting exercise",
+                "description": "List the previous versions of a graded exam kept when it was regraded, oldest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -1537,38 +2032,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Exercise Answers (Student Answers)"
+                    "Exam Overrides"
                 ],
-                "summary": "Calculate Grade",
+                "summary": "Get Exam Score History",
                 "parameters": [
                     {
-                        "description": "Exercise grades data",
-                        "name": "exercise",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CalculateExerciseGrades"
-                        }
+                        "type": "integer",
+                        "description": "Score ID",
+                        "name": "score_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ExerciseGrades"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ScoreHistory"
+                            }
                         }
                     }
                 }
             }
         },
-        "/api/v1/exercises/get-all-grade": {
+        "/api/v1/exams/score-overrides": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get calculated grades for all materials",
+                "description": "List the audit trail of overrides made to a graded exam, oldest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -1576,14 +2072,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Exercise Answers (Student Answers)"
+                    "Exam Overrides"
                 ],
-                "summary": "Get All Grades",
+                "summary": "Get Exam Score Overrides",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Student ID",
-                        "name": "student_id",
+                        "description": "Score ID",
+                        "name": "score_id",
                         "in": "query",
                         "required": true
                     }
@@ -1592,20 +2088,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ExerciseGrades"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ScoreOverride"
+                            }
                         }
                     }
                 }
             }
         },
-        "/api/v1/exercises/get-grade": {
-            "get": {
+        "/api/v1/exams/start-attempt": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get calculated grade",
+                "description": "Start the student's timed attempt. The deadline is duration_minutes from now, capped at the exam end_time. Starting again returns the existing attempt.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1613,43 +2112,56 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Exercise Answers (Student Answers)"
+                    "Exam Attempts"
                 ],
-                "summary": "Get Grade",
+                "summary": "Start Exam Attempt",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Exercise ID",
-                        "name": "exercise_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Student ID",
-                        "name": "student_id",
-                        "in": "query",
-                        "required": true
+                        "description": "Exam and student",
+                        "name": "attempt",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.StartExamAttemptRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ExerciseGrades"
+                            "$ref": "#/definitions/models.ExamAttempt"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/api/v1/exercises/student": {
+        "/api/v1/exams/student": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Fetch all exercises for a specific material",
+                "description": "Fetch one question of an exam without its answer key. Students only get exams they may take once they are open to them.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1657,14 +2169,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Exercises"
+                    "Exams"
                 ],
-                "summary": "Get Exercises for Student",
+                "summary": "Get Exams for Student",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Material ID",
-                        "name": "material_id",
+                        "description": "Exam ID",
+                        "name": "exam_id",
                         "in": "query",
                         "required": true
                     },
@@ -1682,21 +2194,30 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Exercises"
+                                "$ref": "#/definitions/models.Exams"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
             }
         },
-        "/api/v1/materials": {
+        "/api/v1/exercises": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Fetch all materials from the database with pagination",
+                "description": "Fetch all exercises for a specific material",
                 "consumes": [
                     "application/json"
                 ],
@@ -1704,29 +2225,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Materials"
+                    "Exercises"
                 ],
-                "summary": "Get Materials",
+                "summary": "Get Exercises by Material ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page number (default: 1)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of items per page (default: 15)",
-                        "name": "pageSize",
-                        "in": "query"
+                        "description": "Material ID",
+                        "name": "material_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Exercises"
+                            }
                         }
                     }
                 }
@@ -1737,7 +2255,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new material in the database",
+                "description": "Create a new exercise",
                 "consumes": [
                     "application/json"
                 ],
@@ -1745,17 +2263,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Materials"
+                    "Exercises"
                 ],
-                "summary": "Create Material",
+                "summary": "Create Exercise",
                 "parameters": [
                     {
-                        "description": "Material data",
-                        "name": "user",
+                        "description": "Exercise data",
+                        "name": "exercise",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateMaterialRequest"
+                            "$ref": "#/definitions/models.CreateExercisesRequest"
                         }
                     }
                 ],
@@ -1763,7 +2281,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Material"
+                            "$ref": "#/definitions/models.Exercises"
                         }
                     }
                 }
@@ -1774,7 +2292,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes a material from the database by ID",
+                "description": "Delete an exercise by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1782,13 +2300,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Materials"
+                    "Exercises"
                 ],
-                "summary": "Delete Material",
+                "summary": "Delete Exercise",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Material ID",
+                        "description": "Exercise ID",
                         "name": "id",
                         "in": "query",
                         "required": true
@@ -1812,7 +2330,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates an existing material in the database",
+                "description": "Update an existing exercise",
                 "consumes": [
                     "application/json"
                 ],
@@ -1820,73 +2338,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Materials"
+                    "Exercises"
                 ],
-                "summary": "Update Material",
+                "summary": "Update Exercise",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Material ID",
+                        "description": "Exercise ID",
                         "name": "id",
                         "in": "query",
                         "required": true
                     },
                     {
-                        "description": "Updated Material Data",
-                        "name": "material",
+                        "description": "Updated data",
+                        "name": "exercise",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateMaterialRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.Material"
+                            "$ref": "#/definitions/models.CreateExercisesRequest"
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/materials/from-class": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Fetch materials by class id from the database with pagination",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Materials"
-                ],
-                "summary": "Get Materials by class id",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Class ID",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number (default: 1)",
-                        "name": "page",
-                        "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Number of items per page (default: 15)",
-                        "name": "pageSize",
+                        "type": "boolean",
+                        "description": "Regrade stored scores when answer keys or weights change",
+                        "name": "confirm_regrade",
                         "in": "query"
                     }
                 ],
@@ -1894,44 +2369,27 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.Exercises"
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/ping": {
-            "get": {
-                "description": "A simple ping-pong endpoint",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "HealthCheck"
-                ],
-                "summary": "Ping the server",
-                "responses": {
-                    "200": {
-                        "description": "pong",
+                    },
+                    "409": {
+                        "description": "Stored scores would change; includes a preview",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/api/v1/students": {
+        "/api/v1/exercises-answers": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Fetch all students from the database with pagination, filtering by grade, and sorting by NIS",
+                "description": "Fetch all exercises for a specific material",
                 "consumes": [
                     "application/json"
                 ],
@@ -1939,47 +2397,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Students"
+                    "Exercise Answers (Student Answers)"
                 ],
-                "summary": "Get Students",
+                "summary": "Get Exercise Answers by Exercise ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page number (default: 1)",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Exercise ID",
+                        "name": "exercise_id",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Number of items per page (default: 15)",
-                        "name": "pageSize",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by grade (e.g., '10')",
-                        "name": "grade",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Sort by NIS (true for ascending, false for descending)",
-                        "name": "sortByNIS",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search by Name or NIS",
-                        "name": "search",
-                        "in": "query"
+                        "description": "Student ID",
+                        "name": "student_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ExerciseAnswers"
+                            }
                         }
                     }
                 }
@@ -1990,7 +2434,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new student in the database",
+                "description": "Create a new exercise",
                 "consumes": [
                     "application/json"
                 ],
@@ -1998,17 +2442,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Students"
+                    "Exercise Answers (Student Answers)"
                 ],
-                "summary": "Create Student",
+                "summary": "Create Exercise Answers",
                 "parameters": [
                     {
-                        "description": "Student data",
-                        "name": "user",
+                        "description": "Exercise answers data",
+                        "name": "exercise",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateStudentRequest"
+                            "$ref": "#/definitions/models.CreateExerciseAnswersRequest"
                         }
                     }
                 ],
@@ -2016,7 +2460,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Student"
+                            "$ref": "#/definitions/models.ExerciseAnswers"
                         }
                     }
                 }
@@ -2027,7 +2471,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes a student from the database by ID",
+                "description": "Delete an exercise answer by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -2035,13 +2479,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Students"
+                    "Exercise Answers (Student Answers)"
                 ],
-                "summary": "Delete Student",
+                "summary": "Delete Exercise Answer",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Student ID",
+                        "description": "Exercise ID",
                         "name": "id",
                         "in": "query",
                         "required": true
@@ -2065,7 +2509,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates an existing student in the database",
+                "description": "Update an existing exercise answer",
                 "consumes": [
                     "application/json"
                 ],
@@ -2073,24 +2517,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Students"
+                    "Exercise Answers (Student Answers)"
                 ],
-                "summary": "Update Student",
+                "summary": "Update Exercise Answer",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Student ID",
+                        "description": "Exercise Answer ID (Student's Answer ID)",
                         "name": "id",
                         "in": "query",
                         "required": true
                     },
                     {
-                        "description": "Updated Student Data",
-                        "name": "student",
+                        "description": "Updated data",
+                        "name": "exercise",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateStudentRequest"
+                            "$ref": "#/definitions/models.CreateExerciseAnswersRequest"
                         }
                     }
                 ],
@@ -2098,20 +2542,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Student"
+                            "$ref": "#/definitions/models.ExerciseAnswers"
                         }
                     }
                 }
             }
         },
-        "/api/v1/students/details": {
+        "/api/v1/exercises/attempts": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Fetch a single student from the database by their ID",
+                "description": "Get the student's attempt history with the answers and score of each attempt",
                 "consumes": [
                     "application/json"
                 ],
@@ -2119,14 +2563,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Students"
+                    "Exercise Answers (Student Answers)"
                 ],
-                "summary": "Get Student by ID",
+                "summary": "Get Exercise Attempts",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Exercise ID",
+                        "name": "exercise_id",
+                        "in": "query",
+                        "required": true
+                    },
                     {
                         "type": "integer",
                         "description": "Student ID",
-                        "name": "id",
+                        "name": "student_id",
                         "in": "query",
                         "required": true
                     }
@@ -2135,20 +2586,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Student"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ExerciseAttempt"
+                            }
                         }
                     }
                 }
             }
         },
-        "/api/v1/students/grade-migrate": {
+        "/api/v1/exercises/calculate-grade": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Accepts \"up\" to increase grade, and \"down\" to decrease grade",
+                "description": "Submit the current answers as a new attempt and grade it. Fails when the attempt limit is reached or the answers were already graded.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2156,17 +2610,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Students"
+                    "Exercise Answers (Student Answers)"
                 ],
-                "summary": "Migrates Student Grades by 1.",
+                "summary": "Calculate Grade",
                 "parameters": [
                     {
-                        "description": "Student grade",
-                        "name": "student",
+                        "description": "Exercise grades data",
+                        "name": "exercise",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.MigrateStudentGradeRequest"
+                            "$ref": "#/definitions/models.CalculateExerciseGrades"
                         }
                     }
                 ],
@@ -2174,20 +2628,38 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Student"
+                            "$ref": "#/definitions/models.ExerciseGrades"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/api/v1/teachers": {
-            "get": {
+        "/api/v1/exercises/calculate-grade-async": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Fetch all teachers from the database with pagination, filtering by specialization, and sorting by NIP",
+                "description": "Submit the current answers as a new attempt and queue it for grading. Essays are graded in the background; poll the grading job until it is Completed to get the grade.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2195,58 +2667,104 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Teachers"
+                    "Exercise Answers (Student Answers)"
                 ],
-                "summary": "Get Teachers",
+                "summary": "Queue Grade Calculation",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page number (default: 1)",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Exercise grades data",
+                        "name": "exercise",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CalculateExerciseGrades"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.GradingJob"
+                        }
                     },
-                    {
-                        "type": "integer",
-                        "description": "Number of items per page (default: 15)",
-                        "name": "pageSize",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by specialization (e.g., 'IPA')",
-                        "name": "specialization",
-                        "in": "query"
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/exercises/feedback": {
+            "patch": {
+                "security": [
                     {
-                        "type": "boolean",
-                        "description": "Sort by NIP (true for ascending, false for descending)",
-                        "name": "sortByNIP",
-                        "in": "query"
-                    },
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the feedback on one question of a graded attempt. Students see it once the feedback is released.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exercise Feedback"
+                ],
+                "summary": "Update Essay Feedback",
+                "parameters": [
                     {
-                        "type": "string",
-                        "description": "Search by Name or NIP",
-                        "name": "search",
-                        "in": "query"
+                        "description": "Score, question and feedback",
+                        "name": "feedback",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateEssayFeedbackRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EssayFeedback"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/exercises/feedback/release": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new teacher in the database",
+                "description": "Make the feedback of a graded attempt visible to the student",
                 "consumes": [
                     "application/json"
                 ],
@@ -2254,17 +2772,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Teachers"
+                    "Exercise Feedback"
                 ],
-                "summary": "Create Teacher",
+                "summary": "Release Essay Feedback",
                 "parameters": [
                     {
-                        "description": "Teacher data",
-                        "name": "teacher",
+                        "description": "Score ID",
+                        "name": "release",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateTeacherRequest"
+                            "$ref": "#/definitions/models.ReleaseEssayFeedbackRequest"
                         }
                     }
                 ],
@@ -2272,18 +2790,32 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Teacher"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/exercises/get-all-grade": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes a teacher from the database by ID",
+                "description": "Get calculated grades for all materials",
                 "consumes": [
                     "application/json"
                 ],
@@ -2291,14 +2823,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Teachers"
+                    "Exercise Answers (Student Answers)"
                 ],
-                "summary": "Delete Teacher",
+                "summary": "Get All Grades",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Teacher ID",
-                        "name": "id",
+                        "description": "Student ID",
+                        "name": "student_id",
                         "in": "query",
                         "required": true
                     }
@@ -2307,21 +2839,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.ExerciseGrades"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/api/v1/exercises/get-grade": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates an existing teacher in the database",
+                "description": "Get the calculated grade following the exercise score policy (highest, latest or average attempt)",
                 "consumes": [
                     "application/json"
                 ],
@@ -2329,45 +2860,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Teachers"
+                    "Exercise Answers (Student Answers)"
                 ],
-                "summary": "Update Teacher",
+                "summary": "Get Grade",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Teacher ID",
-                        "name": "id",
+                        "description": "Exercise ID",
+                        "name": "exercise_id",
                         "in": "query",
                         "required": true
                     },
                     {
-                        "description": "Updated Teacher Data",
-                        "name": "teacher",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateTeacherRequest"
-                        }
+                        "type": "integer",
+                        "description": "Student ID",
+                        "name": "student_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Teacher"
+                            "$ref": "#/definitions/models.ExerciseGrades"
                         }
                     }
                 }
             }
         },
-        "/api/v1/users": {
+        "/api/v1/exercises/graded-attempt": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Fetch all users from the database with pagination, filtering by role, and sorting by username",
+                "description": "Get a graded exercise attempt with its per-question detail and essay feedback. Students only see feedback after it is released.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2375,52 +2904,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Exercise Feedback"
                 ],
-                "summary": "Get Users",
+                "summary": "Get Graded Attempt",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page number (default: 1)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of items per page (default: 15)",
-                        "name": "pageSize",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by role (e.g., 'admin', 'student')",
-                        "name": "role",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Sort by username (true for ascending, false for descending)",
-                        "name": "sortByUsername",
-                        "in": "query"
+                        "description": "Attempt ID",
+                        "name": "attempt_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GradedAttempt"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/v1/exercises/grading-job": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new user in the database",
+                "description": "Get the progress of a queued grade calculation. The grade is included once the job is Completed; students only see an exam score once it is released.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2428,36 +2950,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Exercise Answers (Student Answers)"
                 ],
-                "summary": "Create User",
+                "summary": "Get Grading Job",
                 "parameters": [
                     {
-                        "description": "User data",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateUserRequest"
-                        }
+                        "type": "integer",
+                        "description": "Grading job ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.GradingJob"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/api/v1/exercises/item-analysis": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates an existing user in the database",
+                "description": "Get the difficulty, discrimination, distractor frequencies and average score of every question in the exercise",
                 "consumes": [
                     "application/json"
                 ],
@@ -2465,24 +2996,63 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Exercise Analytics"
                 ],
-                "summary": "Update User",
+                "summary": "Get Exercise Item Analysis",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Exercise ID",
+                        "name": "exercise_id",
                         "in": "query",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemAnalysis"
+                        }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/exercises/override-score": {
+            "patch": {
+                "security": [
                     {
-                        "description": "Updated User Data",
-                        "name": "teacher",
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the score of one question in a graded exercise and recompute the total. The original auto score, the change, who made it and when are kept in the audit trail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exercise Overrides"
+                ],
+                "summary": "Override Exercise Question Score",
+                "parameters": [
+                    {
+                        "description": "Score, question, new score and comment",
+                        "name": "override",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateUserRequest"
+                            "$ref": "#/definitions/models.OverrideScoreRequest"
                         }
                     }
                 ],
@@ -2490,374 +3060,3265 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.ExerciseGrades"
                         }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "auth.LoginRequest": {
-            "type": "object",
-            "required": [
-                "password",
-                "username"
-            ],
-            "properties": {
-                "password": {
-                    "type": "string"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/exercises/score-history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the previous versions of a graded exercise kept when it was regraded, oldest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exercise Overrides"
+                ],
+                "summary": "Get Exercise Score History",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Score ID",
+                        "name": "score_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ScoreHistory"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/exercises/score-overrides": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the audit trail of overrides made to a graded exercise, oldest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exercise Overrides"
+                ],
+                "summary": "Get Exercise Score Overrides",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Score ID",
+                        "name": "score_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ScoreOverride"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/exercises/student": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch all exercises for a specific material",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exercises"
+                ],
+                "summary": "Get Exercises for Student",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Material ID",
+                        "name": "material_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number",
+                        "name": "number",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Exercises"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/materials": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch all materials from the database with pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Materials"
+                ],
+                "summary": "Get Materials",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page (default: 15)",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new material in the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Materials"
+                ],
+                "summary": "Create Material",
+                "parameters": [
+                    {
+                        "description": "Material data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateMaterialRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Material"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a material from the database by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Materials"
+                ],
+                "summary": "Delete Material",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Material ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates an existing material in the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Materials"
+                ],
+                "summary": "Update Material",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Material ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Material Data",
+                        "name": "material",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateMaterialRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Material"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/materials/from-class": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch materials by class id from the database with pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Materials"
+                ],
+                "summary": "Get Materials by class id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Class ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page (default: 15)",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/ping": {
+            "get": {
+                "description": "A simple ping-pong endpoint",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "HealthCheck"
+                ],
+                "summary": "Ping the server",
+                "responses": {
+                    "200": {
+                        "description": "pong",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/question-bank": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch questions from the question bank with optional filters and pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Question Bank"
+                ],
+                "summary": "Get Bank Questions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subject",
+                        "name": "subject",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Grade",
+                        "name": "grade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Topic (partial match)",
+                        "name": "topic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Difficulty (easy, medium, hard)",
+                        "name": "difficulty",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page (default: 15)",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a reusable question to the question bank",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Question Bank"
+                ],
+                "summary": "Create Bank Question",
+                "parameters": [
+                    {
+                        "description": "Bank question data",
+                        "name": "question",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateBankQuestionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BankQuestion"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a question from the question bank",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Question Bank"
+                ],
+                "summary": "Delete Bank Question",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bank Question ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a question in the question bank. Exams and exercises that already use it are not changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Question Bank"
+                ],
+                "summary": "Update Bank Question",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bank Question ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated data",
+                        "name": "question",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateBankQuestionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BankQuestion"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/question-bank/details": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch a single question from the question bank",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Question Bank"
+                ],
+                "summary": "Get Bank Question by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bank Question ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BankQuestion"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/question-bank/usages": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the exams and exercises a bank question has appeared in",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Question Bank"
+                ],
+                "summary": "Get Bank Question Usages",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bank Question ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BankQuestionUsage"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/students": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch all students from the database with pagination, filtering by grade, and sorting by NIS",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Get Students",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page (default: 15)",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by grade (e.g., '10')",
+                        "name": "grade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sort by NIS (true for ascending, false for descending)",
+                        "name": "sortByNIS",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by Name or NIS",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new student in the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Create Student",
+                "parameters": [
+                    {
+                        "description": "Student data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateStudentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Student"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a student from the database by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Delete Student",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Student ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates an existing student in the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Update Student",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Student ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Student Data",
+                        "name": "student",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateStudentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Student"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/students/details": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch a single student from the database by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Get Student by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Student ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Student"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/students/grade-migrate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Accepts \"up\" to increase grade, and \"down\" to decrease grade",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Migrates Student Grades by 1.",
+                "parameters": [
+                    {
+                        "description": "Student grade",
+                        "name": "student",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MigrateStudentGradeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Student"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/students/report-card": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a student's report card as a PDF printed with the school's layout: their profile, the current final grade and KKM of every class, and the attendance and teacher's remarks for the semester. Unreleased exam scores are not counted in the grades.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/pdf",
+                    "application/json"
+                ],
+                "tags": [
+                    "Report Cards"
+                ],
+                "summary": "Get Report Card",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Student ID",
+                        "name": "student_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Semester, e.g. 2024/2025 Ganjil",
+                        "name": "semester",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to json to get the report card data instead of the PDF",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportCard"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/students/report-card/notes": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the teacher's remarks and the attendance printed on a student's report card for a semester. Fields left out keep their value; only the teacher who wrote the remarks or an admin can change them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report Cards"
+                ],
+                "summary": "Update Report Card Notes",
+                "parameters": [
+                    {
+                        "description": "Remarks and attendance",
+                        "name": "note",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateReportCardNoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportCardNote"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/students/report-cards": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Print the report cards of every student in a grade level as one PDF, one student per page. Teachers only get the students they teach.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Report Cards"
+                ],
+                "summary": "Get Report Cards by Grade",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Grade level",
+                        "name": "grade",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Semester, e.g. 2024/2025 Ganjil",
+                        "name": "semester",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/students/transcript": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a student's full academic transcript: every class they are assigned to with all exercise attempts, the score that counts for each exam with the score before any remedial, final grades and dates. Exam scores that are not released yet are left empty. The JSON layout is versioned by schema_version.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Transcripts"
+                ],
+                "summary": "Get Transcript",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Student ID",
+                        "name": "student_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to csv to download the transcript as CSV",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transcript"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/teachers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch all teachers from the database with pagination, filtering by specialization, and sorting by NIP",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teachers"
+                ],
+                "summary": "Get Teachers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page (default: 15)",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by specialization (e.g., 'IPA')",
+                        "name": "specialization",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sort by NIP (true for ascending, false for descending)",
+                        "name": "sortByNIP",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by Name or NIP",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new teacher in the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teachers"
+                ],
+                "summary": "Create Teacher",
+                "parameters": [
+                    {
+                        "description": "Teacher data",
+                        "name": "teacher",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTeacherRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Teacher"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a teacher from the database by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teachers"
+                ],
+                "summary": "Delete Teacher",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Teacher ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates an existing teacher in the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teachers"
+                ],
+                "summary": "Update Teacher",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Teacher ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Teacher Data",
+                        "name": "teacher",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateTeacherRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Teacher"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch all users from the database with pagination, filtering by role, and sorting by username",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get Users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items per page (default: 15)",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by role (e.g., 'admin', 'student')",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sort by username (true for ascending, false for descending)",
+                        "name": "sortByUsername",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new user in the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create User",
+                "parameters": [
+                    {
+                        "description": "User data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates an existing user in the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated User Data",
+                        "name": "teacher",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "auth.LoginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.RefreshTokenRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AttendanceSummary": {
+            "type": "object",
+            "properties": {
+                "absent_days": {
+                    "type": "integer"
+                },
+                "permitted_days": {
+                    "type": "integer"
+                },
+                "sick_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AuthTokens": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AutosaveExamAnswerRequest": {
+            "type": "object",
+            "required": [
+                "exam_id",
+                "question_number",
+                "student_id"
+            ],
+            "properties": {
+                "answer": {},
+                "exam_id": {
+                    "type": "integer"
+                },
+                "question_number": {
+                    "type": "integer"
+                },
+                "student_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BankQuestion": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "difficulty": {
+                    "type": "string"
+                },
+                "grade": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "question": {
+                    "$ref": "#/definitions/models.Question"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "topic": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BankQuestionUsage": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "exam_id": {
+                    "type": "integer"
+                },
+                "exercise_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "question_id": {
+                    "type": "integer"
+                },
+                "question_number": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BelowKKM": {
+            "type": "object",
+            "properties": {
+                "exam_id": {
+                    "type": "integer"
+                },
+                "kkm": {
+                    "type": "number"
+                },
+                "students": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StudentScore"
+                    }
+                }
+            }
+        },
+        "models.Blank": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "case_sensitive": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.CalculateExamGrades": {
+            "type": "object",
+            "properties": {
+                "exam_id": {
+                    "type": "integer"
+                },
+                "student_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CalculateExerciseGrades": {
+            "type": "object",
+            "properties": {
+                "exercise_id": {
+                    "type": "integer"
+                },
+                "student_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Class": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "grade": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "teacher_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ClassAssignStudents": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "student_id": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.ClassComparison": {
+            "type": "object",
+            "properties": {
+                "class_id": {
+                    "type": "integer"
+                },
+                "mean": {
+                    "type": "number"
+                },
+                "median": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pass_rate": {
+                    "type": "number"
+                },
+                "students": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ClassReport": {
+            "type": "object",
+            "properties": {
+                "class_id": {
+                    "type": "integer"
+                },
+                "comparison": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ClassComparison"
+                    }
+                },
+                "exams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExamSummary"
+                    }
+                },
+                "grade": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "passing_score": {
+                    "type": "number"
+                },
+                "statistics": {
+                    "description": "Statistics are computed over each student's average percentage across the exams they took",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ScoreStatistics"
+                        }
+                    ]
+                },
+                "students": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StudentAverage"
+                    }
+                }
+            }
+        },
+        "models.CreateBankQuestionRequest": {
+            "type": "object",
+            "required": [
+                "difficulty",
+                "grade",
+                "subject",
+                "teacher_id",
+                "topic"
+            ],
+            "properties": {
+                "difficulty": {
+                    "type": "string",
+                    "enum": [
+                        "easy",
+                        "medium",
+                        "hard"
+                    ]
+                },
+                "grade": {
+                    "type": "integer"
+                },
+                "question": {
+                    "$ref": "#/definitions/models.Question"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "topic": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateClassRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "grade": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateDiscussionRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "replies": {},
+                "student_id": {
+                    "type": "integer"
+                },
+                "topic": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateExamAnswersRequest": {
+            "type": "object",
+            "properties": {
+                "answers": {},
+                "exam_id": {
+                    "type": "integer"
+                },
+                "student_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateExamsRequest": {
+            "type": "object",
+            "properties": {
+                "bank_question_ids": {
+                    "description": "Bank_Question_IDs are copied from the question bank and appended after Content",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "class_id": {
+                    "type": "integer"
+                },
+                "content": {
+                    "$ref": "#/definitions/models.QuestionContent"
+                },
+                "duration_minutes": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "essay_grading": {
+                    "description": "Essay_Grading is auto, manual or queued; it defaults to auto",
+                    "type": "string",
+                    "enum": [
+                        "auto",
+                        "manual",
+                        "queued"
+                    ]
+                },
+                "kkm": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "late_grace_minutes": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "negative_marking": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "release_policy": {
+                    "description": "Release_Policy is immediate, after_close or manual; it defaults to immediate",
+                    "type": "string",
+                    "enum": [
+                        "immediate",
+                        "after_close",
+                        "manual"
+                    ]
+                },
+                "remedial_of": {
+                    "description": "Remedial_Of makes this a remedial exam of another exam in the same class. It is set on creation only.",
+                    "type": "integer"
+                },
+                "remedial_policy": {
+                    "description": "Remedial_Policy is cap_at_kkm, highest or average; it defaults to cap_at_kkm",
+                    "type": "string",
+                    "enum": [
+                        "cap_at_kkm",
+                        "highest",
+                        "average"
+                    ]
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "student_ids": {
+                    "description": "Student_IDs limits a new remedial exam to these students; by default it is given to every student below the KKM",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "total_marks": {
+                    "description": "Total_Marks must equal the sum of question weights, or 0 to derive it from them",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateExerciseAnswersRequest": {
+            "type": "object",
+            "properties": {
+                "answers": {},
+                "exercise_id": {
+                    "type": "integer"
+                },
+                "student_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateExercisesRequest": {
+            "type": "object",
+            "properties": {
+                "bank_question_ids": {
+                    "description": "Bank_Question_IDs are copied from the question bank and appended after Content",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "content": {
+                    "$ref": "#/definitions/models.QuestionContent"
+                },
+                "material_id": {
+                    "type": "integer"
+                },
+                "max_attempts": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "score_policy": {
+                    "description": "Score_Policy is highest, latest or average; it defaults to latest",
+                    "type": "string",
+                    "enum": [
+                        "highest",
+                        "latest",
+                        "average"
+                    ]
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "total_marks": {
+                    "description": "Total_Marks must equal the sum of question weights, or 0 to derive it from them",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateMaterialRequest": {
+            "type": "object",
+            "properties": {
+                "class_id": {
+                    "type": "integer"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateStudentRequest": {
+            "type": "object",
+            "properties": {
+                "grade": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "nis": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateTeacherRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "nip": {
+                    "type": "string"
+                },
+                "specialization": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateUserRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "role",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Discussion": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "replies": {},
+                "student_id": {
+                    "type": "integer"
+                },
+                "topic": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EssayFeedback": {
+            "type": "object",
+            "properties": {
+                "edited_by": {
+                    "type": "integer"
+                },
+                "feedback": {
+                    "type": "string"
+                },
+                "generated": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "question_key": {
+                    "type": "string"
+                },
+                "score_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ExamAnswers": {
+            "type": "object",
+            "properties": {
+                "answers": {},
+                "exam_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_late": {
+                    "type": "boolean"
+                },
+                "late_seconds": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "integer"
+                },
+                "submitted_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ExamAttempt": {
+            "type": "object",
+            "properties": {
+                "deadline": {
+                    "type": "string"
+                },
+                "exam_id": {
+                    "type": "integer"
+                },
+                "finalized_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExamGrades": {
+            "type": "object",
+            "properties": {
+                "detail": {},
+                "exam_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "status": {
+                    "description": "Status is released once students may see the score; until then a student only sees submitted",
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExamReport": {
+            "type": "object",
+            "properties": {
+                "class_id": {
+                    "type": "integer"
+                },
+                "exam_id": {
+                    "type": "integer"
+                },
+                "passing_score": {
+                    "type": "number"
+                },
+                "statistics": {
+                    "$ref": "#/definitions/models.ScoreStatistics"
+                },
+                "students": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StudentScore"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "total_marks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExamReview": {
+            "type": "object",
+            "properties": {
+                "exam_id": {
+                    "type": "integer"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReviewQuestion"
+                    }
+                },
+                "score": {
+                    "type": "number"
+                },
+                "score_id": {
+                    "type": "integer"
+                },
+                "student_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExamSummary": {
+            "type": "object",
+            "properties": {
+                "exam_id": {
+                    "type": "integer"
+                },
+                "statistics": {
+                    "$ref": "#/definitions/models.ScoreStatistics"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Exams": {
+            "type": "object",
+            "properties": {
+                "class_id": {
+                    "type": "integer"
+                },
+                "content": {
+                    "$ref": "#/definitions/models.QuestionContent"
+                },
+                "duration_minutes": {
+                    "description": "Duration_Minutes limits each student's attempt, counted from when they start it; 0 means no timer",
+                    "type": "integer"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "essay_grading": {
+                    "description": "Essay_Grading is how essays are scored: auto, manual by the teacher, or queued for background grading",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kkm": {
+                    "description": "KKM is the passing percentage of this exam; when empty the class's KKM applies",
+                    "type": "number"
+                },
+                "late_grace_minutes": {
+                    "description": "Late_Grace_Minutes is how long after end_time answers are still accepted and flagged as late",
+                    "type": "integer"
+                },
+                "negative_marking": {
+                    "description": "Negative_Marking is the fraction of a question's points deducted for a wrong multiple choice or true/false answer",
+                    "type": "number"
+                },
+                "release_policy": {
+                    "description": "Release_Policy decides when students see their scores: immediately, after the exam window closes, or manually",
+                    "type": "string"
+                },
+                "released_at": {
+                    "description": "Released_At is when a teacher released the scores; it releases them under any policy",
+                    "type": "string"
+                },
+                "remedial_of": {
+                    "description": "Remedial_Of is the exam a remedial exam repeats; only the students assigned to it may take it",
+                    "type": "integer"
+                },
+                "remedial_policy": {
+                    "description": "Remedial_Policy decides how a remedial score is recorded on the original exam",
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "total_marks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExerciseAnswers": {
+            "type": "object",
+            "properties": {
+                "answers": {},
+                "exercise_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExerciseAttempt": {
+            "type": "object",
+            "properties": {
+                "answers": {},
+                "attempt_number": {
+                    "type": "integer"
+                },
+                "detail": {},
+                "exercise_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "student_id": {
+                    "type": "integer"
+                },
+                "submitted_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ExerciseGrades": {
+            "type": "object",
+            "properties": {
+                "attempt_id": {
+                    "type": "integer"
                 },
-                "username": {
+                "detail": {},
+                "exercise_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "student_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Exercises": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "$ref": "#/definitions/models.QuestionContent"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "material_id": {
+                    "type": "integer"
+                },
+                "max_attempts": {
+                    "description": "Max_Attempts limits how many times a student can submit; 0 means unlimited",
+                    "type": "integer"
+                },
+                "score_policy": {
+                    "type": "string"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "title": {
                     "type": "string"
+                },
+                "total_marks": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CalculateExamGrades": {
+        "models.FinalGrade": {
             "type": "object",
             "properties": {
-                "exam_id": {
+                "class_id": {
                     "type": "integer"
                 },
+                "exam_average": {
+                    "type": "number"
+                },
+                "exercise_average": {
+                    "type": "number"
+                },
+                "final_grade": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
                 "student_id": {
                     "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "models.CalculateExerciseGrades": {
+        "models.Gradebook": {
+            "type": "object",
+            "properties": {
+                "settings": {
+                    "$ref": "#/definitions/models.GradebookSettings"
+                },
+                "students": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FinalGrade"
+                    }
+                }
+            }
+        },
+        "models.GradebookSettings": {
+            "type": "object",
+            "properties": {
+                "class_id": {
+                    "type": "integer"
+                },
+                "drop_lowest_exams": {
+                    "type": "integer"
+                },
+                "drop_lowest_exercises": {
+                    "type": "integer"
+                },
+                "exam_weight": {
+                    "type": "number"
+                },
+                "exercise_weight": {
+                    "type": "number"
+                },
+                "kkm": {
+                    "description": "KKM is the minimum mastery criterion, the passing percentage of the class's exams",
+                    "type": "number"
+                }
+            }
+        },
+        "models.GradedAttempt": {
+            "type": "object",
+            "properties": {
+                "answers": {},
+                "attempt_number": {
+                    "type": "integer"
+                },
+                "detail": {},
+                "exercise_id": {
+                    "type": "integer"
+                },
+                "feedback": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EssayFeedback"
+                    }
+                },
+                "feedback_released_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "score_id": {
+                    "type": "integer"
+                },
+                "student_id": {
+                    "type": "integer"
+                },
+                "submitted_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GradingJob": {
             "type": "object",
             "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "done_items": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "exam_grade": {
+                    "$ref": "#/definitions/models.ExamGrades"
+                },
+                "exam_score_id": {
+                    "type": "integer"
+                },
                 "exercise_id": {
                     "type": "integer"
                 },
-                "student_id": {
+                "failed_items": {
+                    "type": "integer"
+                },
+                "grade": {
+                    "$ref": "#/definitions/models.ExerciseGrades"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "integer"
+                },
+                "total_items": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HistogramBucket": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "number"
+                },
+                "to": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.ItemAnalysis": {
+            "type": "object",
+            "properties": {
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ItemStatistics"
+                    }
+                },
+                "students": {
+                    "description": "Students is how many graded attempts were analysed, one per student",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ItemStatistics": {
+            "type": "object",
+            "properties": {
+                "average_score": {
+                    "type": "number"
+                },
+                "difficulty": {
+                    "description": "Difficulty is the average share of the points earned, which is the share of students correct\non questions scored right or wrong. Low values mark hard questions.",
+                    "type": "number"
+                },
+                "discrimination": {
+                    "description": "Discrimination is the difficulty among the upper 27% of students by total score\nminus the difficulty among the lower 27%. Values near or below 0 mark questions worth reviewing.",
+                    "type": "number"
+                },
+                "distractors": {
+                    "description": "Distractors counts how often each option of a multiple choice question was picked",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OptionFrequency"
+                    }
+                },
+                "max_score": {
+                    "type": "number"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "omitted": {
+                    "type": "integer"
+                },
+                "prompt": {
+                    "type": "string"
+                },
+                "responses": {
+                    "description": "Responses counts the answered questions and Omitted the unanswered ones, which score as wrong.\nEssays still waiting to be graded are left out of every statistic.",
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MatchingPair": {
+            "type": "object",
+            "properties": {
+                "left": {
+                    "type": "string"
+                },
+                "right": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Material": {
+            "type": "object",
+            "properties": {
+                "class_id": {
+                    "type": "integer"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MigrateStudentGradeRequest": {
+            "type": "object",
+            "required": [
+                "migrate"
+            ],
+            "properties": {
+                "migrate": {
+                    "type": "string",
+                    "default": "up"
+                }
+            }
+        },
+        "models.OptionFrequency": {
+            "type": "object",
+            "properties": {
+                "correct": {
+                    "type": "boolean"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "share": {
+                    "type": "number"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OverrideScoreRequest": {
+            "type": "object",
+            "required": [
+                "question_key",
+                "score_id"
+            ],
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "question_key": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "score_id": {
                     "type": "integer"
                 }
             }
         },
-        "models.Class": {
+        "models.Question": {
             "type": "object",
             "properties": {
-                "description": {
+                "bank_question_id": {
+                    "description": "Bank_Question_ID is the bank question this question was copied from.\nIt stays with the question when questions are renumbered.",
+                    "type": "integer"
+                },
+                "blanks": {
+                    "description": "Fill blank: accepted answers for each blank, in the order the blanks appear in the prompt",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Blank"
+                    }
+                },
+                "correct_answer": {
                     "type": "string"
                 },
-                "grade": {
-                    "type": "integer"
+                "correct_answers": {
+                    "description": "Multi select: keys of every correct option",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "id": {
+                "explanation": {
+                    "type": "string"
+                },
+                "keywords": {
+                    "description": "Essay: key terms a good answer mentions. Correct_Answer may hold a reference answer.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "left_items": {
+                    "description": "Matching items shown to students instead of Pairs",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "number": {
                     "type": "integer"
                 },
-                "name": {
+                "numeric_answer": {
+                    "description": "Numeric: expected value and the accepted distance from it",
+                    "type": "number"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QuestionOption"
+                    }
+                },
+                "pairs": {
+                    "description": "Matching: each left item and the right item it belongs to",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MatchingPair"
+                    }
+                },
+                "partial_credit": {
+                    "description": "Partial_Credit allows multi select, matching and fill blank answers to earn part of the points.\nIt defaults to true for those types.",
+                    "type": "boolean"
+                },
+                "prompt": {
                     "type": "string"
                 },
-                "teacher_id": {
-                    "type": "integer"
+                "right_items": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "teacher_name": {
+                "rubric": {
+                    "description": "Essay: criteria the answer is graded on, e.g. content 40%, argument 30% and language 30%",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RubricCriterion"
+                    }
+                },
+                "tolerance": {
+                    "type": "number"
+                },
+                "tolerance_type": {
+                    "type": "string"
+                },
+                "type": {
                     "type": "string"
+                },
+                "weight": {
+                    "description": "Weight is the number of points the question is worth",
+                    "type": "number"
                 }
             }
         },
-        "models.ClassAssignStudents": {
+        "models.QuestionContent": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer"
-                },
-                "student_id": {
+                "questions": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/models.Question"
                     }
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CreateClassRequest": {
+        "models.QuestionOption": {
             "type": "object",
             "properties": {
-                "description": {
+                "key": {
                     "type": "string"
                 },
-                "grade": {
-                    "type": "integer"
-                },
-                "name": {
+                "text": {
                     "type": "string"
-                },
-                "teacher_id": {
+                }
+            }
+        },
+        "models.ReleaseEssayFeedbackRequest": {
+            "type": "object",
+            "required": [
+                "score_id"
+            ],
+            "properties": {
+                "score_id": {
                     "type": "integer"
                 }
             }
         },
-        "models.CreateDiscussionRequest": {
+        "models.ReplyDiscussion": {
             "type": "object",
             "properties": {
-                "description": {
+                "replies": {
                     "type": "string"
                 },
-                "replies": {},
                 "student_id": {
                     "type": "integer"
                 },
-                "topic": {
+                "student_name": {
                     "type": "string"
                 }
             }
         },
-        "models.CreateExamAnswersRequest": {
+        "models.ReportCard": {
             "type": "object",
             "properties": {
-                "answers": {},
-                "exam_id": {
-                    "type": "integer"
+                "attendance": {
+                    "$ref": "#/definitions/models.AttendanceSummary"
                 },
-                "student_id": {
-                    "type": "integer"
+                "classes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReportCardClass"
+                    }
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "remarks": {
+                    "type": "string"
+                },
+                "semester": {
+                    "type": "string"
+                },
+                "student": {
+                    "$ref": "#/definitions/models.Student"
                 }
             }
         },
-        "models.CreateExamsRequest": {
+        "models.ReportCardClass": {
             "type": "object",
             "properties": {
                 "class_id": {
                     "type": "integer"
                 },
-                "content": {},
-                "end_time": {
-                    "type": "string"
+                "exam_average": {
+                    "type": "number"
                 },
-                "start_time": {
-                    "type": "string"
+                "exercise_average": {
+                    "type": "number"
                 },
-                "teacher_id": {
-                    "type": "integer"
+                "final_grade": {
+                    "type": "number"
                 },
-                "title": {
+                "kkm": {
+                    "type": "number"
+                },
+                "name": {
                     "type": "string"
                 },
-                "total_marks": {
-                    "type": "integer"
+                "passed": {
+                    "type": "boolean"
+                },
+                "teacher_name": {
+                    "type": "string"
                 }
             }
         },
-        "models.CreateExerciseAnswersRequest": {
+        "models.ReportCardNote": {
             "type": "object",
             "properties": {
-                "answers": {},
-                "exercise_id": {
+                "absent_days": {
                     "type": "integer"
                 },
-                "student_id": {
+                "id": {
                     "type": "integer"
-                }
-            }
-        },
-        "models.CreateExercisesRequest": {
-            "type": "object",
-            "properties": {
-                "content": {},
-                "material_id": {
+                },
+                "permitted_days": {
                     "type": "integer"
                 },
-                "teacher_id": {
+                "remarks": {
+                    "type": "string"
+                },
+                "semester": {
+                    "type": "string"
+                },
+                "sick_days": {
                     "type": "integer"
                 },
-                "title": {
+                "student_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
                     "type": "string"
                 },
-                "total_marks": {
+                "written_by": {
                     "type": "integer"
                 }
             }
         },
-        "models.CreateMaterialRequest": {
+        "models.ReviewQuestion": {
             "type": "object",
             "properties": {
-                "class_id": {
+                "answer": {},
+                "bank_question_id": {
+                    "description": "Bank_Question_ID is the bank question this question was copied from.\nIt stays with the question when questions are renumbered.",
                     "type": "integer"
                 },
-                "content": {
+                "blanks": {
+                    "description": "Fill blank: accepted answers for each blank, in the order the blanks appear in the prompt",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Blank"
+                    }
+                },
+                "comment": {
                     "type": "string"
                 },
-                "description": {
+                "correct": {
+                    "type": "boolean"
+                },
+                "correct_answer": {
                     "type": "string"
                 },
-                "teacher_id": {
-                    "type": "integer"
+                "correct_answers": {
+                    "description": "Multi select: keys of every correct option",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "title": {
+                "explanation": {
                     "type": "string"
-                }
-            }
-        },
-        "models.CreateStudentRequest": {
-            "type": "object",
-            "properties": {
-                "grade": {
+                },
+                "feedback": {
+                    "type": "string"
+                },
+                "justification": {
+                    "type": "string"
+                },
+                "keywords": {
+                    "description": "Essay: key terms a good answer mentions. Correct_Answer may hold a reference answer.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "left_items": {
+                    "description": "Matching items shown to students instead of Pairs",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_score": {
+                    "type": "number"
+                },
+                "number": {
                     "type": "integer"
                 },
-                "name": {
+                "numeric_answer": {
+                    "description": "Numeric: expected value and the accepted distance from it",
+                    "type": "number"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QuestionOption"
+                    }
+                },
+                "pairs": {
+                    "description": "Matching: each left item and the right item it belongs to",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MatchingPair"
+                    }
+                },
+                "partial_credit": {
+                    "description": "Partial_Credit allows multi select, matching and fill blank answers to earn part of the points.\nIt defaults to true for those types.",
+                    "type": "boolean"
+                },
+                "pending": {
+                    "type": "boolean"
+                },
+                "prompt": {
                     "type": "string"
                 },
-                "nis": {
+                "right_items": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rubric": {
+                    "description": "Essay: criteria the answer is graded on, e.g. content 40%, argument 30% and language 30%",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RubricCriterion"
+                    }
+                },
+                "score": {
+                    "type": "number"
+                },
+                "tolerance": {
+                    "type": "number"
+                },
+                "tolerance_type": {
                     "type": "string"
                 },
-                "status": {
+                "type": {
                     "type": "string"
+                },
+                "weight": {
+                    "description": "Weight is the number of points the question is worth",
+                    "type": "number"
                 }
             }
         },
-        "models.CreateTeacherRequest": {
+        "models.RubricCriterion": {
             "type": "object",
             "properties": {
+                "levels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RubricLevel"
+                    }
+                },
                 "name": {
                     "type": "string"
                 },
-                "nip": {
-                    "type": "string"
+                "weight": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.RubricLevel": {
+            "type": "object",
+            "properties": {
+                "credit": {
+                    "type": "number"
                 },
-                "specialization": {
+                "description": {
                     "type": "string"
                 },
-                "status": {
+                "label": {
                     "type": "string"
                 }
             }
         },
-        "models.CreateUserRequest": {
+        "models.ScoreHistory": {
             "type": "object",
-            "required": [
-                "email",
-                "password",
-                "role",
-                "username"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "changed_by": {
+                    "type": "integer"
                 },
-                "password": {
+                "created_at": {
                     "type": "string"
                 },
-                "role": {
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 },
-                "username": {
+                "new_score": {
+                    "type": "number"
+                },
+                "old_detail": {},
+                "old_score": {
+                    "type": "number"
+                },
+                "reason": {
                     "type": "string"
+                },
+                "score_id": {
+                    "type": "integer"
                 }
             }
         },
-        "models.Discussion": {
+        "models.ScoreOverride": {
             "type": "object",
             "properties": {
-                "description": {
+                "auto_score": {
+                    "type": "number"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "replies": {},
-                "student_id": {
+                "new_score": {
+                    "type": "number"
+                },
+                "overridden_by": {
                     "type": "integer"
                 },
-                "topic": {
+                "previous_score": {
+                    "type": "number"
+                },
+                "question_key": {
                     "type": "string"
+                },
+                "score_id": {
+                    "type": "integer"
                 }
             }
         },
-        "models.ExamAnswers": {
+        "models.ScoreStatistics": {
             "type": "object",
             "properties": {
-                "answers": {},
-                "exam_id": {
+                "count": {
                     "type": "integer"
                 },
-                "id": {
-                    "type": "integer"
+                "histogram": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HistogramBucket"
+                    }
                 },
-                "status": {
-                    "type": "string"
+                "max": {
+                    "type": "number"
                 },
-                "student_id": {
-                    "type": "integer"
+                "mean": {
+                    "type": "number"
+                },
+                "median": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "number"
+                },
+                "pass_rate": {
+                    "type": "number"
+                },
+                "std_dev": {
+                    "type": "number"
                 }
             }
         },
-        "models.ExamGrades": {
+        "models.StartExamAttemptRequest": {
             "type": "object",
+            "required": [
+                "exam_id",
+                "student_id"
+            ],
             "properties": {
-                "detail": {},
                 "exam_id": {
                     "type": "integer"
                 },
-                "id": {
-                    "type": "integer"
-                },
-                "score": {
-                    "type": "number"
-                },
                 "student_id": {
                     "type": "integer"
                 }
             }
         },
-        "models.Exams": {
+        "models.Student": {
             "type": "object",
             "properties": {
-                "class_id": {
+                "curr_score": {
                     "type": "integer"
                 },
-                "content": {},
-                "end_time": {
-                    "type": "string"
+                "grade": {
+                    "type": "integer"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "start_time": {
+                "name": {
                     "type": "string"
                 },
-                "status": {
+                "nis": {
                     "type": "string"
                 },
-                "teacher_id": {
-                    "type": "integer"
+                "phone_number": {
+                    "type": "string"
                 },
-                "title": {
+                "profile_picture_url": {
                     "type": "string"
                 },
-                "total_marks": {
-                    "type": "integer"
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "models.ExerciseAnswers": {
+        "models.StudentAverage": {
             "type": "object",
             "properties": {
-                "answers": {},
-                "exercise_id": {
-                    "type": "integer"
+                "average": {
+                    "type": "number"
                 },
-                "id": {
+                "exams_taken": {
                     "type": "integer"
                 },
-                "status": {
+                "name": {
+                    "type": "string"
+                },
+                "nis": {
                     "type": "string"
                 },
+                "passed": {
+                    "type": "boolean"
+                },
                 "student_id": {
                     "type": "integer"
                 }
             }
         },
-        "models.ExerciseGrades": {
+        "models.StudentScore": {
             "type": "object",
             "properties": {
-                "detail": {},
-                "exercise_id": {
-                    "type": "integer"
+                "name": {
+                    "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "nis": {
+                    "type": "string"
+                },
+                "passed": {
+                    "type": "boolean"
+                },
+                "percent": {
+                    "type": "number"
                 },
                 "score": {
                     "type": "number"
@@ -2867,134 +6328,166 @@ const docTemplate = `{
                 }
             }
         },
-        "models.Exercises": {
+        "models.SubmitExamAnswersRequest": {
             "type": "object",
+            "required": [
+                "exam_id",
+                "student_id"
+            ],
             "properties": {
-                "content": {},
-                "id": {
-                    "type": "integer"
-                },
-                "material_id": {
-                    "type": "integer"
-                },
-                "teacher_id": {
+                "exam_id": {
                     "type": "integer"
                 },
-                "title": {
-                    "type": "string"
-                },
-                "total_marks": {
+                "student_id": {
                     "type": "integer"
                 }
             }
         },
-        "models.Material": {
+        "models.Teacher": {
             "type": "object",
             "properties": {
-                "class_id": {
+                "id": {
                     "type": "integer"
                 },
-                "content": {
+                "name": {
                     "type": "string"
                 },
-                "description": {
+                "nip": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "phone_number": {
+                    "type": "string"
                 },
-                "teacher_id": {
-                    "type": "integer"
+                "profile_picture_url": {
+                    "type": "string"
                 },
-                "title": {
+                "specialization": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "models.MigrateStudentGradeRequest": {
+        "models.Transcript": {
             "type": "object",
-            "required": [
-                "migrate"
-            ],
             "properties": {
-                "migrate": {
-                    "type": "string",
-                    "default": "up"
+                "classes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TranscriptClass"
+                    }
+                },
+                "cumulative_average": {
+                    "type": "number"
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "schema_version": {
+                    "type": "string"
+                },
+                "student": {
+                    "$ref": "#/definitions/models.TranscriptStudent"
                 }
             }
         },
-        "models.ReplyDiscussion": {
+        "models.TranscriptClass": {
             "type": "object",
             "properties": {
-                "replies": {
+                "class_id": {
+                    "type": "integer"
+                },
+                "exam_average": {
+                    "type": "number"
+                },
+                "exams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TranscriptScore"
+                    }
+                },
+                "exercise_average": {
+                    "type": "number"
+                },
+                "exercises": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TranscriptScore"
+                    }
+                },
+                "final_grade": {
+                    "type": "number"
+                },
+                "kkm": {
+                    "type": "number"
+                },
+                "name": {
                     "type": "string"
                 },
-                "student_id": {
-                    "type": "integer"
+                "passed": {
+                    "type": "boolean"
                 },
-                "student_name": {
+                "teacher_name": {
                     "type": "string"
                 }
             }
         },
-        "models.Student": {
+        "models.TranscriptScore": {
             "type": "object",
             "properties": {
-                "curr_score": {
+                "attempt": {
                     "type": "integer"
                 },
-                "grade": {
+                "item_id": {
                     "type": "integer"
                 },
-                "id": {
-                    "type": "integer"
+                "percent": {
+                    "type": "number"
                 },
-                "name": {
-                    "type": "string"
+                "pre_remedial_score": {
+                    "type": "number"
                 },
-                "nis": {
+                "recorded_at": {
                     "type": "string"
                 },
-                "phone_number": {
-                    "type": "string"
+                "remedial_of": {
+                    "type": "integer"
                 },
-                "profile_picture_url": {
-                    "type": "string"
+                "score": {
+                    "type": "number"
                 },
-                "status": {
-                    "type": "string"
+                "score_id": {
+                    "type": "integer"
                 },
-                "user_id": {
+                "title": {
                     "type": "string"
+                },
+                "total_marks": {
+                    "type": "integer"
                 }
             }
         },
-        "models.Teacher": {
+        "models.TranscriptStudent": {
             "type": "object",
             "properties": {
+                "grade": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
                 "name": {
                     "type": "string"
                 },
-                "nip": {
-                    "type": "string"
-                },
-                "phone_number": {
-                    "type": "string"
-                },
-                "profile_picture_url": {
-                    "type": "string"
-                },
-                "specialization": {
+                "nis": {
                     "type": "string"
                 },
                 "status": {
                     "type": "string"
-                },
-                "user_id": {
-                    "type": "string"
                 }
             }
         },
@@ -3012,6 +6505,60 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UpdateEssayFeedbackRequest": {
+            "type": "object",
+            "required": [
+                "feedback",
+                "question_key",
+                "score_id"
+            ],
+            "properties": {
+                "feedback": {
+                    "type": "string"
+                },
+                "question_key": {
+                    "type": "string"
+                },
+                "score_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.UpdateGradebookSettingsRequest": {
+            "type": "object",
+            "required": [
+                "class_id"
+            ],
+            "properties": {
+                "class_id": {
+                    "type": "integer"
+                },
+                "drop_lowest_exams": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "drop_lowest_exercises": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "exam_weight": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "exercise_weight": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "kkm": {
+                    "description": "KKM keeps its current value when left empty",
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                }
+            }
+        },
         "models.UpdateMaterialRequest": {
             "type": "object",
             "properties": {
@@ -3032,6 +6579,37 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UpdateReportCardNoteRequest": {
+            "type": "object",
+            "required": [
+                "semester",
+                "student_id"
+            ],
+            "properties": {
+                "absent_days": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "permitted_days": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "remarks": {
+                    "type": "string"
+                },
+                "semester": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "sick_days": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "student_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "models.UpdateStudentRequest": {
             "type": "object",
             "properties": {
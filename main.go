@@ -12,10 +12,12 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"project-ppl-be/config"
 	_ "project-ppl-be/docs"
+	"project-ppl-be/src/repo"
 	"project-ppl-be/src/server"
 
 	utils "project-ppl-be/src/utils"
@@ -28,6 +30,12 @@ func main() {
 	db := config.ConnectDB()
 	defer db.Close()
 
+	// Select the essay grader from ESSAY_GRADER
+	utils.ConfigureEssayGrader()
+
+	// Load the report card layout from REPORT_CARD_TEMPLATE
+	utils.ConfigureReportCardTemplate()
+
 	// Set up the Gin router with db
 	router := server.SetupRouter()
 
@@ -38,7 +46,22 @@ func main() {
 	handler := corsHandler.Handler(router)
 
 	// Start Cron
-	utils.StartCron(db)
+	examRepo := repo.ExamRepository{}
+	exerciseRepo := repo.ExerciseRepository{}
+	utils.StartCron(db,
+		utils.CronJob{Name: "finalize expired exam attempts and closed drafts", Spec: "* * * * *", Run: func() error {
+			_, err := examRepo.FinalizeExpiredAttempts(context.Background())
+			return err
+		}},
+		utils.CronJob{Name: "grade queued essays", Spec: "@every 5s", Run: func() error {
+			_, err := exerciseRepo.ProcessGradingJobs(context.Background())
+			return err
+		}},
+		utils.CronJob{Name: "release closed exam scores", Spec: "* * * * *", Run: func() error {
+			_, err := examRepo.ReleaseClosedExamScores(context.Background())
+			return err
+		}},
+	)
 
 	// Start the server with the wrapped handler
 	log.Fatal(http.ListenAndServe(":8080", handler))
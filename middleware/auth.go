@@ -1,14 +1,19 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"os"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/repo"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
 )
 
+var authRepo = repo.AuthRepository{}
+
 // AuthMiddleware checks JWT token validity and extracts claims
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -54,6 +59,46 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Pastikan sesi token belum dicabut (logout atau akun dinonaktifkan)
+		sessionID, exists := claims["sid"].(float64)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session not found in token"})
+			c.Abort()
+			return
+		}
+
+		active, err := authRepo.IsSessionActive(context.Background(), int(sessionID))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+		if !active {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has been revoked"})
+			c.Abort()
+			return
+		}
+		c.Set("session_id", int(sessionID))
+
+		// Simpan principal bertipe agar handler bisa memeriksa kepemilikan data
+		principal := models.Principal{
+			Role:       claims["role"].(string),
+			Session_ID: int(sessionID),
+		}
+		if userID, ok := claims["user_id"].(float64); ok {
+			principal.User_ID = int(userID)
+		}
+		if displayName, ok := claims["display_name"].(string); ok {
+			principal.Display_Name = displayName
+		}
+		if studentID, ok := claims["student_id"].(float64); ok {
+			principal.Student_ID = int(studentID)
+		}
+		if teacherID, ok := claims["teacher_id"].(float64); ok {
+			principal.Teacher_ID = int(teacherID)
+		}
+		c.Set(principalKey, principal)
+
 		c.Next()
 	}
 }
@@ -0,0 +1,261 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/repo"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+const principalKey = "principal"
+
+var accessRepo = repo.AccessRepository{}
+
+// GetPrincipal returns the authenticated caller stored by AuthMiddleware
+func GetPrincipal(c *gin.Context) (models.Principal, bool) {
+	value, exists := c.Get(principalKey)
+	if !exists {
+		return models.Principal{}, false
+	}
+	principal, ok := value.(models.Principal)
+	return principal, ok
+}
+
+// AuthorizeStudent allows admins, the student themself, and teachers of one of the student's classes.
+// It writes the error response and returns false when access is denied.
+func AuthorizeStudent(c *gin.Context, studentID int) bool {
+	principal, ok := GetPrincipal(c)
+	if !ok {
+		return deny(c)
+	}
+
+	switch {
+	case principal.IsAdmin():
+		return true
+	case principal.IsStudent():
+		if principal.Student_ID == studentID {
+			return true
+		}
+	case principal.IsTeacher():
+		allowed, err := accessRepo.TeacherTeachesStudent(context.Background(), principal.Teacher_ID, studentID)
+		if err != nil {
+			return failLookup(c, err)
+		}
+		if allowed {
+			return true
+		}
+	}
+	return deny(c)
+}
+
+// AuthorizeTeacher allows admins and the teacher themself
+func AuthorizeTeacher(c *gin.Context, teacherID int) bool {
+	principal, ok := GetPrincipal(c)
+	if !ok {
+		return deny(c)
+	}
+	if principal.IsAdmin() || (principal.IsTeacher() && principal.Teacher_ID == teacherID) {
+		return true
+	}
+	return deny(c)
+}
+
+// AuthorizeClass allows admins, the teacher of the class and students assigned to it
+func AuthorizeClass(c *gin.Context, classID int) bool {
+	principal, ok := GetPrincipal(c)
+	if !ok {
+		return deny(c)
+	}
+
+	var allowed bool
+	var err error
+	switch {
+	case principal.IsAdmin():
+		return true
+	case principal.IsTeacher():
+		allowed, err = accessRepo.TeacherOwnsClass(context.Background(), principal.Teacher_ID, classID)
+	case principal.IsStudent():
+		allowed, err = accessRepo.StudentInClass(context.Background(), principal.Student_ID, classID)
+	}
+	if err != nil {
+		return failLookup(c, err)
+	}
+	if !allowed {
+		return deny(c)
+	}
+	return true
+}
+
+// AuthorizeStudentClass allows callers with access to both the student and the class,
+// as long as the student is assigned to the class
+func AuthorizeStudentClass(c *gin.Context, studentID, classID int) bool {
+	if !AuthorizeStudent(c, studentID) || !AuthorizeClass(c, classID) {
+		return false
+	}
+	allowed, err := accessRepo.StudentInClass(context.Background(), studentID, classID)
+	if err != nil {
+		return failLookup(c, err)
+	}
+	if !allowed {
+		return deny(c)
+	}
+	return true
+}
+
+// AuthorizeMaterial checks access to the class that owns the material
+func AuthorizeMaterial(c *gin.Context, materialID int) bool {
+	classID, err := accessRepo.ClassIDOfMaterial(context.Background(), materialID)
+	if err != nil {
+		return failLookup(c, err)
+	}
+	return AuthorizeClass(c, classID)
+}
+
+// AuthorizeExam checks access to the class that owns the exam.
+// Students must also be assigned to it when it is a remedial exam.
+func AuthorizeExam(c *gin.Context, examID int) bool {
+	classID, err := accessRepo.ClassIDOfExam(context.Background(), examID)
+	if err != nil {
+		return failLookup(c, err)
+	}
+	if !AuthorizeClass(c, classID) {
+		return false
+	}
+
+	principal, _ := GetPrincipal(c)
+	if !principal.IsStudent() {
+		return true
+	}
+	allowed, err := accessRepo.StudentMayTakeExam(context.Background(), principal.Student_ID, examID)
+	if err != nil {
+		return failLookup(c, err)
+	}
+	if !allowed {
+		return deny(c)
+	}
+	return true
+}
+
+// AuthorizeStudentExam allows callers with access to both the student and the exam, as long as the
+// student belongs to the exam's class and is assigned to it when it is a remedial exam
+func AuthorizeStudentExam(c *gin.Context, studentID, examID int) bool {
+	if !AuthorizeStudent(c, studentID) || !AuthorizeExam(c, examID) {
+		return false
+	}
+	allowed, err := accessRepo.StudentMayTakeExam(context.Background(), studentID, examID)
+	if err != nil {
+		return failLookup(c, err)
+	}
+	if !allowed {
+		return deny(c)
+	}
+	return true
+}
+
+// AuthorizeExercise checks access to the class that owns the exercise
+func AuthorizeExercise(c *gin.Context, exerciseID int) bool {
+	classID, err := accessRepo.ClassIDOfExercise(context.Background(), exerciseID)
+	if err != nil {
+		return failLookup(c, err)
+	}
+	return AuthorizeClass(c, classID)
+}
+
+// AuthorizeStudentExercise allows callers with access to both the student and the exercise,
+// as long as the student belongs to the exercise's class
+func AuthorizeStudentExercise(c *gin.Context, studentID, exerciseID int) bool {
+	classID, err := accessRepo.ClassIDOfExercise(context.Background(), exerciseID)
+	if err != nil {
+		return failLookup(c, err)
+	}
+	return AuthorizeStudentClass(c, studentID, classID)
+}
+
+// AuthorizeExamScore checks access to the class that owns the exam of the score
+func AuthorizeExamScore(c *gin.Context, scoreID int) bool {
+	examID, err := accessRepo.ExamIDOfScore(context.Background(), scoreID)
+	if err != nil {
+		return failLookup(c, err)
+	}
+	return AuthorizeExam(c, examID)
+}
+
+// AuthorizeExerciseScore checks access to the class that owns the exercise of the score
+func AuthorizeExerciseScore(c *gin.Context, scoreID int) bool {
+	exerciseID, err := accessRepo.ExerciseIDOfScore(context.Background(), scoreID)
+	if err != nil {
+		return failLookup(c, err)
+	}
+	return AuthorizeExercise(c, exerciseID)
+}
+
+// AuthorizeExamAnswer checks access to the student who owns the exam answer and to its exam
+func AuthorizeExamAnswer(c *gin.Context, answerID int) bool {
+	studentID, err := accessRepo.StudentIDOfExamAnswer(context.Background(), answerID)
+	if err != nil {
+		return failLookup(c, err)
+	}
+	examID, err := accessRepo.ExamIDOfExamAnswer(context.Background(), answerID)
+	if err != nil {
+		return failLookup(c, err)
+	}
+	return AuthorizeStudentExam(c, studentID, examID)
+}
+
+// AuthorizeExerciseAnswer checks access to the student who owns the exercise answer and to its exercise
+func AuthorizeExerciseAnswer(c *gin.Context, answerID int) bool {
+	studentID, err := accessRepo.StudentIDOfExerciseAnswer(context.Background(), answerID)
+	if err != nil {
+		return failLookup(c, err)
+	}
+	exerciseID, err := accessRepo.ExerciseIDOfExerciseAnswer(context.Background(), answerID)
+	if err != nil {
+		return failLookup(c, err)
+	}
+	return AuthorizeStudentExercise(c, studentID, exerciseID)
+}
+
+// AuthorizeDiscussion allows admins and the student who wrote the discussion
+func AuthorizeDiscussion(c *gin.Context, discussionID int) bool {
+	studentID, err := accessRepo.StudentIDOfDiscussion(context.Background(), discussionID)
+	if err != nil {
+		return failLookup(c, err)
+	}
+	principal, ok := GetPrincipal(c)
+	if !ok {
+		return deny(c)
+	}
+	if principal.IsAdmin() || (principal.IsStudent() && principal.Student_ID == studentID) {
+		return true
+	}
+	return deny(c)
+}
+
+// AuthorizeBankQuestion allows admins and the teacher who owns the bank question
+func AuthorizeBankQuestion(c *gin.Context, questionID int) bool {
+	teacherID, err := accessRepo.TeacherIDOfBankQuestion(context.Background(), questionID)
+	if err != nil {
+		return failLookup(c, err)
+	}
+	return AuthorizeTeacher(c, teacherID)
+}
+
+func deny(c *gin.Context) bool {
+	c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: you do not have access to this resource"})
+	c.Abort()
+	return false
+}
+
+func failLookup(c *gin.Context, err error) bool {
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
+	} else {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+	c.Abort()
+	return false
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"project-ppl-be/src/repo"
 
@@ -15,14 +16,18 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type RefreshTokenRequest struct {
+	Refresh_Token string `json:"refresh_token" binding:"required"`
+}
+
 // AuthHandler handles user authentication
 // @Summary Login authentication
-// @Description Auth API to differentiate roles
+// @Description Auth API to differentiate roles, returns a short-lived access token and a refresh token
 // @Tags Auth
 // @Accept  json
 // @Produce  json
 // @Param request body LoginRequest true "Login Credentials"
-// @Success 200 {object} map[string]string
+// @Success 200 {object} models.AuthTokens
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Router /api/v1/auth [post]
@@ -33,11 +38,72 @@ func AuthHandler(c *gin.Context) {
 		return
 	}
 
-	token, err := authRepo.LoginUser(context.Background(), req.Username, req.Password)
+	tokens, err := authRepo.LoginUser(context.Background(), req.Username, req.Password)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	c.JSON(http.StatusOK, tokens)
+}
+
+// RefreshHandler rotates the refresh token and issues a new access token
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access token. The refresh token is rotated and the old one stops working.
+// @Tags Auth
+// @Accept  json
+// @Produce  json
+// @Param request body RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} models.AuthTokens
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/auth/refresh [post]
+func RefreshHandler(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	tokens, err := authRepo.RefreshSession(context.Background(), req.Refresh_Token)
+	if err != nil {
+		if errors.Is(err, repo.ErrInvalidRefreshToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// LogoutHandler revokes the session of a refresh token
+// @Summary Logout
+// @Description Revoke the session owning the refresh token. Access tokens of that session are rejected right away.
+// @Tags Auth
+// @Accept  json
+// @Produce  json
+// @Param request body RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/auth/logout [post]
+func LogoutHandler(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	if err := authRepo.RevokeSession(context.Background(), req.Refresh_Token); err != nil {
+		if errors.Is(err, repo.ErrInvalidRefreshToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"math"
 	"net/http"
+	"project-ppl-be/middleware"
 	"project-ppl-be/src/models"
 	"project-ppl-be/src/repo"
 	"strconv"
@@ -36,8 +37,19 @@ func ClassGetHandler(c *gin.Context) {
 		pageSize = 15
 	}
 
+	// Siswa memakai /classes/assigned, guru hanya melihat kelas yang diajarnya
+	principal, _ := middleware.GetPrincipal(c)
+	if principal.IsStudent() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: Teachers and Admins only"})
+		return
+	}
+	teacherID := 0
+	if principal.IsTeacher() {
+		teacherID = principal.Teacher_ID
+	}
+
 	// Ambil data dengan pagination dan filter grade
-	classes, total, err := classesRepo.GettAllClasses(context.Background(), page, pageSize)
+	classes, total, err := classesRepo.GettAllClasses(context.Background(), page, pageSize, teacherID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -74,6 +86,10 @@ func ClassPostHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeTeacher(c, req.Teacher_ID) {
+		return
+	}
+
 	// Call CreateUser with the extracted values
 	user, err := classesRepo.CreateClass(context.Background(), req.Name, req.Description, req.Teacher_ID, req.Grade)
 	if err != nil {
@@ -107,12 +123,20 @@ func ClassUpdateHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeClass(c, id) {
+		return
+	}
+
 	// Parse JSON request body
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	if !middleware.AuthorizeTeacher(c, req.Teacher_ID) {
+		return
+	}
+
 	// Call UpdateTeacher with the correct parameters
 	class, err := classesRepo.UpdateClass(
 		context.Background(),
@@ -150,6 +174,10 @@ func ClassDeleteHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeClass(c, id) {
+		return
+	}
+
 	// Call DeleteTeacher function from repository
 	err = classesRepo.DeleteClass(context.Background(), id)
 	if err != nil {
@@ -189,6 +217,10 @@ func GetClassIDHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeTeacher(c, teacherID) {
+		return
+	}
+
 	// Ambil data dari repo
 	classes, total, err := classesRepo.GetClassId(context.Background(), grade, teacherID)
 	if err != nil {
@@ -232,6 +264,10 @@ func GetClassForStudentHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeStudent(c, studentID) {
+		return
+	}
+
 	// Ambil parameter pagination
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "15"))
@@ -4,6 +4,7 @@ import (
 	"context"
 	"math"
 	"net/http"
+	"project-ppl-be/middleware"
 	"project-ppl-be/src/models"
 	"strconv"
 
@@ -29,6 +30,10 @@ func ClassGetByIdHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing class ID"})
 		return
 	}
+
+	if !middleware.AuthorizeClass(c, id) {
+		return
+	}
 	// Ambil parameter query dari request
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "15"))
@@ -80,6 +85,10 @@ func ClassAssignStudentsHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeClass(c, req.ID) {
+		return
+	}
+
 	// Call AssignStudents with the extracted values
 	err := classesRepo.AssignStudents(context.Background(), req.ID, req.Student_ID)
 	if err != nil {
@@ -112,6 +121,10 @@ func ClassUnassignStudentsHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeClass(c, req.ID) {
+		return
+	}
+
 	// Call UnassignStudents with the extracted values
 	err := classesRepo.UnassignStudents(context.Background(), req.ID, req.Student_ID)
 	if err != nil {
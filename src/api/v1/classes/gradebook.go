@@ -0,0 +1,121 @@
+package classes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"project-ppl-be/middleware"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/repo"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+var gradebookRepo = repo.GradebookRepository{}
+
+// @Summary Get Gradebook
+// @Description Get the category weights of a class with the running final grade of every student
+// @Tags Gradebook
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param class_id query int true "Class ID"
+// @Success 200 {object} models.Gradebook
+// @Router /api/v1/classes/gradebook [get]
+func GradebookGetHandler(c *gin.Context) {
+	classIDStr := c.Query("class_id")
+	classID, err := strconv.Atoi(classIDStr)
+	if err != nil || classID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing class_id"})
+		return
+	}
+
+	if !middleware.AuthorizeClass(c, classID) {
+		return
+	}
+
+	gradebook, err := gradebookRepo.GetGradebook(context.Background(), classID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gradebook)
+}
+
+// @Summary Update Gradebook Settings
+// @Description Set the exercise and exam weights of a class, how many of the lowest scores to drop and its KKM, then recompute its final grades
+// @Tags Gradebook
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param settings body models.UpdateGradebookSettingsRequest true "Gradebook settings"
+// @Success 200 {object} models.GradebookSettings
+// @Router /api/v1/classes/gradebook [patch]
+func GradebookSettingsUpdateHandler(c *gin.Context) {
+	var req models.UpdateGradebookSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !middleware.AuthorizeClass(c, req.Class_ID) {
+		return
+	}
+
+	settings, err := gradebookRepo.UpdateGradebookSettings(context.Background(), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// @Summary Get Final Grade
+// @Description Get a student's running final grade in a class
+// @Tags Gradebook
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param class_id query int true "Class ID"
+// @Param student_id query int true "Student ID"
+// @Success 200 {object} models.FinalGrade
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/classes/final-grade [get]
+func FinalGradeGetHandler(c *gin.Context) {
+	classIDStr := c.Query("class_id")
+	classID, err := strconv.Atoi(classIDStr)
+	if err != nil || classID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing class_id"})
+		return
+	}
+
+	studentIDStr := c.Query("student_id")
+	studentID, err := strconv.Atoi(studentIDStr)
+	if err != nil || studentID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing student_id"})
+		return
+	}
+
+	if !middleware.AuthorizeStudentClass(c, studentID, classID) {
+		return
+	}
+
+	grade, err := gradebookRepo.GetFinalGrade(context.Background(), classID, studentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No graded work in this class yet"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, grade)
+}
@@ -0,0 +1,25 @@
+package classes
+
+import (
+	"project-ppl-be/middleware"
+	"project-ppl-be/src/api/v1/scores"
+	"project-ppl-be/src/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get Class Report
+// @Description Get the performance of a class over its exams with the mean, median, standard deviation, histogram and pass rate, compared with the classes of the same grade. Only the class's teacher or an admin can see it.
+// @Tags Class Reports
+// @Security BearerAuth
+// @Accept json
+// @Produce json,text/csv
+// @Param class_id query int true "Class ID"
+// @Param passing_score query number false "Passing percentage, 75 by default"
+// @Param format query string false "Set to csv to download the report as CSV"
+// @Success 200 {object} models.ClassReport
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/classes/report [get]
+func ClassReportGetHandler(c *gin.Context) {
+	scores.Report(c, "class_id", "Class", middleware.AuthorizeClass, classesRepo.GetClassReport, utils.ClassReportCSV, "class-%d-report.csv")
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"project-ppl-be/middleware"
 	"project-ppl-be/src/models"
 	"project-ppl-be/src/repo"
 	"strconv"
@@ -71,6 +72,10 @@ func DiscussionsPostHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeStudent(c, req.Student_ID) {
+		return
+	}
+
 	discussion, err := discussionsRepo.CreateDiscussion(
 		context.Background(),
 		req.Student_ID,
@@ -107,11 +112,19 @@ func DiscussionsUpdateHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeDiscussion(c, id) {
+		return
+	}
+
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	if !middleware.AuthorizeStudent(c, req.Student_ID) {
+		return
+	}
+
 	discussion, err := discussionsRepo.UpdateDiscussion(
 		context.Background(),
 		id,
@@ -145,6 +158,10 @@ func DiscussionsDeleteHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeDiscussion(c, id) {
+		return
+	}
+
 	err = discussionsRepo.DeleteDiscussion(context.Background(), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -187,6 +204,10 @@ func DiscussionsReplyHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeStudent(c, req.Student_ID) {
+		return
+	}
+
 	// Debug: Log body yang di-bind
 	fmt.Println("Received body:", req)
 
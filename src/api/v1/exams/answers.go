@@ -2,12 +2,17 @@ package exams
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"project-ppl-be/middleware"
 	"project-ppl-be/src/models"
 	"project-ppl-be/src/repo"
+	"project-ppl-be/src/utils"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 )
 
 var examAnswersRepo = repo.ExamRepository{}
@@ -37,6 +42,10 @@ func ExamAnswersGetHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeStudentExam(c, studentID, examID) {
+		return
+	}
+
 	exams, err := examAnswersRepo.GetExamAnswers(context.Background(), examID, studentID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -47,13 +56,15 @@ func ExamAnswersGetHandler(c *gin.Context) {
 }
 
 // @Summary Create Exam Answers
-// @Description Create a new exam
+// @Description Submit answers for an exam. Answers before start_time or after the late grace period are rejected.
 // @Tags Exam Answers (Student Answers)
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param exam body models.CreateExamAnswersRequest true "Exam answers data"
 // @Success 200 {object} models.ExamAnswers
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /api/v1/exams-answers [post]
 func ExamAnswersPostHandler(c *gin.Context) {
 	var req models.CreateExamAnswersRequest
@@ -63,8 +74,20 @@ func ExamAnswersPostHandler(c *gin.Context) {
 		return
 	}
 
-	exam, err := examsRepo.CreateExamAnswers(context.Background(), req)
-	if err != nil {
+	if !middleware.AuthorizeStudentExam(c, req.Student_ID, req.Exam_ID) {
+		return
+	}
+
+	lateSeconds, ok := checkSubmissionWindow(c, req.Exam_ID, req.Student_ID)
+	if !ok {
+		return
+	}
+
+	exam, err := examsRepo.CreateExamAnswers(context.Background(), req, lateSeconds)
+	if errors.Is(err, repo.ErrAnswersSubmitted) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -73,7 +96,7 @@ func ExamAnswersPostHandler(c *gin.Context) {
 }
 
 // @Summary Update Exam Answer
-// @Description Update an existing exam answer
+// @Description Update an existing exam answer. Answers submitted after end_time are flagged as late.
 // @Tags Exam Answers (Student Answers)
 // @Security BearerAuth
 // @Accept json
@@ -81,6 +104,9 @@ func ExamAnswersPostHandler(c *gin.Context) {
 // @Param id query int true "Exam Answer ID (Student's Answer ID)"
 // @Param exam body models.CreateExamAnswersRequest true "Updated data"
 // @Success 200 {object} models.ExamAnswers
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /api/v1/exams-answers [patch]
 func ExamAnswersUpdateHandler(c *gin.Context) {
 	idStr := c.Query("id")
@@ -90,14 +116,33 @@ func ExamAnswersUpdateHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeExamAnswer(c, id) {
+		return
+	}
+
 	var req models.CreateExamAnswersRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	exam, err := examsRepo.UpdateExamAnswers(context.Background(), id, req)
-	if err != nil {
+	if !middleware.AuthorizeStudentExam(c, req.Student_ID, req.Exam_ID) {
+		return
+	}
+
+	lateSeconds, ok := checkSubmissionWindow(c, req.Exam_ID, req.Student_ID)
+	if !ok {
+		return
+	}
+
+	exam, err := examsRepo.UpdateExamAnswers(context.Background(), id, req, lateSeconds)
+	if errors.Is(err, repo.ErrAnswersSubmitted) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	} else if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exam answer not found"})
+		return
+	} else if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -106,13 +151,15 @@ func ExamAnswersUpdateHandler(c *gin.Context) {
 }
 
 // @Summary Delete Exam Answer
-// @Description Delete an exam answer by ID
+// @Description Delete an exam answer by ID. Submitted answers cannot be deleted.
 // @Tags Exam Answers (Student Answers)
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id query int true "Exam ID"
 // @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /api/v1/exams-answers [delete]
 func ExamAnswersDeleteHandler(c *gin.Context) {
 	idStr := c.Query("id")
@@ -122,8 +169,18 @@ func ExamAnswersDeleteHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeExamAnswer(c, id) {
+		return
+	}
+
 	err = examsRepo.DeleteExamAnswers(context.Background(), id)
-	if err != nil {
+	if errors.Is(err, repo.ErrAnswersSubmitted) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	} else if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exam answer not found"})
+		return
+	} else if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -132,13 +189,16 @@ func ExamAnswersDeleteHandler(c *gin.Context) {
 }
 
 // @Summary Calculate Grade
-// @Description Calculate grade after submitting exam
+// @Description Submit the student's answers and grade them, like /exams-answers/submit. Answers are graded only once.
 // @Tags Exam Answers (Student Answers)
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param exam body models.CalculateExamGrades true "Exam grades data"
 // @Success 200 {object} models.ExamGrades
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /api/v1/exams/calculate-grade [post]
 func CalculateGradePostHandler(c *gin.Context) {
 	var req models.CalculateExamGrades
@@ -148,17 +208,11 @@ func CalculateGradePostHandler(c *gin.Context) {
 		return
 	}
 
-	exam, err := examsRepo.CalculateExamGrades(context.Background(), req)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusOK, exam)
+	submitExamAnswers(c, models.SubmitExamAnswersRequest{Exam_ID: req.Exam_ID, Student_ID: req.Student_ID})
 }
 
 // @Summary Get Grade
-// @Description Get calculated grade
+// @Description Get calculated grade. Students only see the score once the exam's release policy allows it; until then the status is submitted.
 // @Tags Exam Answers (Student Answers)
 // @Security BearerAuth
 // @Accept json
@@ -182,17 +236,22 @@ func ExamGradesGetHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeStudentExam(c, studentID, examID) {
+		return
+	}
+
 	exams, err := examAnswersRepo.GetExamGrades(context.Background(), examID, studentID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, exams)
+	principal, _ := middleware.GetPrincipal(c)
+	c.JSON(http.StatusOK, utils.WithholdUnreleasedGrades(principal.Role, exams))
 }
 
 // @Summary Get ALl Grades
-// @Description Get calculated grades for all exams
+// @Description Get calculated grades for all exams. Scores that are not released yet are withheld from students.
 // @Tags Exam Answers (Student Answers)
 // @Security BearerAuth
 // @Accept json
@@ -208,11 +267,134 @@ func ExamsAllGradesGetHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeStudent(c, studentID) {
+		return
+	}
+
 	exams, err := examAnswersRepo.GetAllExamGrades(context.Background(), studentID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, exams)
+	principal, _ := middleware.GetPrincipal(c)
+	c.JSON(http.StatusOK, utils.WithholdUnreleasedGrades(principal.Role, exams))
+}
+
+// @Summary Autosave Exam Answer
+// @Description Save a single answer into the student's draft without overwriting the other answers. The draft is created on the first autosave.
+// @Tags Exam Answers (Student Answers)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param answer body models.AutosaveExamAnswerRequest true "Question number and answer"
+// @Success 200 {object} models.ExamAnswers
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/exams-answers/autosave [patch]
+func ExamAnswerAutosaveHandler(c *gin.Context) {
+	var req models.AutosaveExamAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !middleware.AuthorizeStudentExam(c, req.Student_ID, req.Exam_ID) {
+		return
+	}
+
+	if _, ok := checkSubmissionWindow(c, req.Exam_ID, req.Student_ID); !ok {
+		return
+	}
+
+	answers, err := examsRepo.AutosaveExamAnswer(context.Background(), req)
+	if errors.Is(err, repo.ErrAnswersSubmitted) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, answers)
+}
+
+// @Summary Submit Exam Answers
+// @Description Freeze the student's draft so it can no longer change, then grade it
+// @Tags Exam Answers (Student Answers)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param submission body models.SubmitExamAnswersRequest true "Exam and student"
+// @Success 200 {object} models.ExamGrades
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/exams-answers/submit [post]
+func ExamAnswersSubmitHandler(c *gin.Context) {
+	var req models.SubmitExamAnswersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	submitExamAnswers(c, req)
+}
+
+// submitExamAnswers freezes and grades the student's answers inside the submission window
+func submitExamAnswers(c *gin.Context, req models.SubmitExamAnswersRequest) {
+	if !middleware.AuthorizeStudentExam(c, req.Student_ID, req.Exam_ID) {
+		return
+	}
+
+	lateSeconds, ok := checkSubmissionWindow(c, req.Exam_ID, req.Student_ID)
+	if !ok {
+		return
+	}
+
+	grade, err := examsRepo.SubmitExamAnswers(context.Background(), req, lateSeconds)
+	if errors.Is(err, repo.ErrAnswersSubmitted) || errors.Is(err, repo.ErrEssaysChanged) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	} else if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No answers to submit"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal, _ := middleware.GetPrincipal(c)
+	c.JSON(http.StatusOK, utils.WithholdUnreleasedGrades(principal.Role, []models.ExamGrades{grade})[0])
+}
+
+// checkSubmissionWindow rejects answers outside the exam window or after the student's attempt deadline,
+// and returns how many seconds late an answer submitted during the grace period is
+func checkSubmissionWindow(c *gin.Context, examID, studentID int) (int, bool) {
+	exam, err := examsRepo.GetExamByID(context.Background(), examID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return 0, false
+	}
+
+	// Ujian berwaktu memakai batas waktu attempt, bukan masa tenggang
+	if exam.Duration_Minutes > 0 {
+		err := examsRepo.CheckAttemptOpen(context.Background(), examID, studentID)
+		if errors.Is(err, repo.ErrAttemptNotStarted) || errors.Is(err, repo.ErrAttemptExpired) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return 0, false
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return 0, false
+		}
+		return 0, true
+	}
+
+	grace := time.Duration(exam.Late_Grace_Minutes) * time.Minute
+	late, err := utils.CheckSubmissionWindow(time.Now(), exam.Start_Time, exam.End_Time, grace)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return 0, false
+	}
+	return int(late.Seconds()), true
 }
@@ -0,0 +1,103 @@
+package exams
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"project-ppl-be/middleware"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// @Summary Start Exam Attempt
+// @Description Start the student's timed attempt. The deadline is duration_minutes from now, capped at the exam end_time. Starting again returns the existing attempt.
+// @Tags Exam Attempts
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param attempt body models.StartExamAttemptRequest true "Exam and student"
+// @Success 200 {object} models.ExamAttempt
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/v1/exams/start-attempt [post]
+func StartExamAttemptHandler(c *gin.Context) {
+	var req models.StartExamAttemptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !middleware.AuthorizeStudentExam(c, req.Student_ID, req.Exam_ID) {
+		return
+	}
+
+	exam, err := examsRepo.GetExamByID(context.Background(), req.Exam_ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if exam.Duration_Minutes == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Exam has no time limit"})
+		return
+	}
+
+	if _, err := utils.CheckSubmissionWindow(time.Now(), exam.Start_Time, exam.End_Time, 0); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	attempt, err := examsRepo.StartExamAttempt(context.Background(), exam, req.Student_ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, attempt)
+}
+
+// @Summary Get Exam Attempt
+// @Description Get the student's timed attempt, including its deadline
+// @Tags Exam Attempts
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param exam_id query int true "Exam ID"
+// @Param student_id query int true "Student ID"
+// @Success 200 {object} models.ExamAttempt
+// @Router /api/v1/exams/attempt [get]
+func ExamAttemptGetHandler(c *gin.Context) {
+	examIDStr := c.Query("exam_id")
+	examID, err := strconv.Atoi(examIDStr)
+	if err != nil || examID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing exam_id"})
+		return
+	}
+
+	studentIDStr := c.Query("student_id")
+	studentID, err := strconv.Atoi(studentIDStr)
+	if err != nil || studentID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing student_id"})
+		return
+	}
+
+	if !middleware.AuthorizeStudentExam(c, studentID, examID) {
+		return
+	}
+
+	attempt, err := examsRepo.GetExamAttempt(context.Background(), examID, studentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attempt not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, attempt)
+}
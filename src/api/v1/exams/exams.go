@@ -2,18 +2,24 @@ package exams
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"project-ppl-be/middleware"
 	"project-ppl-be/src/models"
 	"project-ppl-be/src/repo"
+	"project-ppl-be/src/utils"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 )
 
 var examsRepo = repo.ExamRepository{}
+var questionBankRepo = repo.QuestionBankRepository{}
 
 // @Summary Get Exams by Class ID
-// @Description Fetch exam from a class
+// @Description Fetch exam from a class. Students get no questions of exams that have not started or, when timed, that they have no attempt in progress for.
 // @Tags Exams
 // @Security BearerAuth
 // @Accept json
@@ -29,31 +35,57 @@ func ExamsGetByClassHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeClass(c, classID) {
+		return
+	}
+
 	exams, err := examsRepo.GetExamsByClassID(context.Background(), classID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, exams)
+	// Siswa hanya melihat ujian remedial yang diberikan kepadanya
+	principal, _ := middleware.GetPrincipal(c)
+	if principal.IsStudent() {
+		exams, err = examsRepo.RemoveUnassignedRemedials(context.Background(), exams, principal.Student_ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Soal ujian yang belum dimulai atau belum dikerjakan tidak ikut dikirim
+		for i, exam := range exams {
+			err := examClosedToStudent(context.Background(), exam, principal.Student_ID)
+			if errors.Is(err, utils.ErrExamNotStarted) || errors.Is(err, repo.ErrAttemptNotStarted) || errors.Is(err, repo.ErrAttemptExpired) {
+				exams[i].Content.Questions = []models.Question{}
+			} else if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.SanitizeExamsForRole(principal.Role, exams))
 }
 
 // @Summary Get Exams for Student
-// @Description Fetch all exams for a specific class
+// @Description Fetch one question of an exam without its answer key. Students only get exams they may take once they are open to them.
 // @Tags Exams
 // @Security BearerAuth
 // @Accept json
 // @Produce json
-// @Param class_id query int true "Class ID"
+// @Param exam_id query int true "Exam ID"
 // @Param number query int true "Number"
 // @Success 200 {array} models.Exams
+// @Failure 404 {object} map[string]string
 // @Router /api/v1/exams/student [get]
-func ExamsGetByClassForStudentHandler(c *gin.Context) {
-	classIDStr := c.Query("class_id")
+func ExamsGetForStudentHandler(c *gin.Context) {
+	examIDStr := c.Query("exam_id")
 	numberStr := c.Query("number")
-	classID, err := strconv.Atoi(classIDStr)
-	if err != nil || classID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing class_id"})
+	examID, err := strconv.Atoi(examIDStr)
+	if err != nil || examID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing exam_id"})
 		return
 	}
 
@@ -63,17 +95,47 @@ func ExamsGetByClassForStudentHandler(c *gin.Context) {
 		return
 	}
 
-	exams, err := examsRepo.GetExamsByClassIDForStudent(context.Background(), classID, number)
-	if err != nil {
+	// Ujian remedial hanya untuk siswa yang diberi remedial
+	if !middleware.AuthorizeExam(c, examID) {
+		return
+	}
+
+	exams, err := examsRepo.GetExamForStudent(context.Background(), examID, number)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exam not found"})
+		return
+	} else if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, exams)
+	// Soal ujian belum boleh dilihat siswa sebelum ujian dimulai
+	principal, _ := middleware.GetPrincipal(c)
+	if principal.IsStudent() {
+		for _, exam := range exams {
+			if err := examClosedToStudent(context.Background(), exam, principal.Student_ID); err != nil {
+				c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+				return
+			}
+		}
+	}
+	c.JSON(http.StatusOK, utils.SanitizeExamsForRole(principal.Role, exams))
+}
+
+// examClosedToStudent returns why a student may not see the exam's questions yet: the exam has not
+// started, or it is timed and the student has no attempt in progress
+func examClosedToStudent(ctx context.Context, exam models.Exams, studentID int) error {
+	if time.Now().Before(exam.Start_Time) {
+		return utils.ErrExamNotStarted
+	}
+	if exam.Duration_Minutes > 0 {
+		return examsRepo.CheckAttemptOpen(ctx, exam.ID, studentID)
+	}
+	return nil
 }
 
 // @Summary Create Exam
-// @Description Create a new exam
+// @Description Create a new exam. With remedial_of it becomes a remedial exam given to student_ids, or to every student below the original exam's KKM.
 // @Tags Exams
 // @Security BearerAuth
 // @Accept json
@@ -89,13 +151,51 @@ func ExamsPostHandler(c *gin.Context) {
 		return
 	}
 
+	err := questionBankRepo.AppendBankQuestions(context.Background(), &req.Content, req.Bank_Question_IDs)
+	if errors.Is(err, repo.ErrBankQuestionNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.Content.Normalize()
+	if err := req.Content.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content: " + err.Error()})
+		return
+	}
+
+	req.Total_Marks, err = req.Content.ResolveTotalMarks(req.Total_Marks)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !middleware.AuthorizeClass(c, req.Class_ID) || !middleware.AuthorizeTeacher(c, req.Teacher_ID) {
+		return
+	}
+
+	// Ujian remedial harus mengulang ujian biasa dari kelas yang sama
+	if req.Remedial_Of != nil {
+		original, err := examsRepo.GetExamByID(context.Background(), *req.Remedial_Of)
+		if errors.Is(err, pgx.ErrNoRows) || (err == nil && (original.Class_ID != req.Class_ID || original.Remedial_Of != nil)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "remedial_of must be a regular exam of the same class"})
+			return
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	exam, err := examsRepo.CreateExam(context.Background(), req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, exam)
+	principal, _ := middleware.GetPrincipal(c)
+	c.JSON(http.StatusOK, utils.SanitizeExamsForRole(principal.Role, []models.Exams{exam})[0])
 }
 
 // @Summary Update Exam
@@ -106,7 +206,9 @@ func ExamsPostHandler(c *gin.Context) {
 // @Produce json
 // @Param id query int true "Exam ID"
 // @Param exam body models.CreateExamsRequest true "Updated data"
+// @Param confirm_regrade query bool false "Regrade stored scores when answer keys or weights change"
 // @Success 200 {object} models.Exams
+// @Failure 409 {object} map[string]interface{} "Stored scores would change; includes a preview"
 // @Router /api/v1/exams [patch]
 func ExamsUpdateHandler(c *gin.Context) {
 	idStr := c.Query("id")
@@ -116,19 +218,84 @@ func ExamsUpdateHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeExam(c, id) {
+		return
+	}
+
 	var req models.CreateExamsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	exam, err := examsRepo.UpdateExam(context.Background(), id, req)
+	err = questionBankRepo.AppendBankQuestions(context.Background(), &req.Content, req.Bank_Question_IDs)
+	if errors.Is(err, repo.ErrBankQuestionNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.Content.Normalize()
+	if err := req.Content.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content: " + err.Error()})
+		return
+	}
+
+	req.Total_Marks, err = req.Content.ResolveTotalMarks(req.Total_Marks)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !middleware.AuthorizeClass(c, req.Class_ID) || !middleware.AuthorizeTeacher(c, req.Teacher_ID) {
+		return
+	}
+
+	current, err := examsRepo.GetExamByID(context.Background(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Nilai yang sudah tersimpan dinilai ulang hanya setelah guru melihat pratinjau dan mengonfirmasi
+	changedQuestions := utils.ScoringChanges(current.Content, req.Content)
+	regrade := len(changedQuestions) > 0 || current.Negative_Marking != req.Negative_Marking
+	confirmRegrade, _ := strconv.ParseBool(c.DefaultQuery("confirm_regrade", "false"))
+	if regrade && !confirmRegrade {
+		changes, total, err := examsRepo.PreviewExamRegrade(context.Background(), id, req.Content, req.Negative_Marking)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if total > 0 {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "Changing the answer key or weights affects stored scores; resend with confirm_regrade=true to regrade them",
+				"preview": models.RegradePreview{
+					Changed_Questions: changedQuestions,
+					Total_Scores:      total,
+					Affected_Scores:   len(changes),
+					Changes:           changes,
+				},
+			})
+			return
+		}
+	}
+
+	principal, _ := middleware.GetPrincipal(c)
+	var regradeBy *int
+	if regrade && confirmRegrade {
+		regradeBy = &principal.User_ID
+	}
+
+	exam, err := examsRepo.UpdateExam(context.Background(), id, req, regradeBy)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, exam)
+	c.JSON(http.StatusOK, utils.SanitizeExamsForRole(principal.Role, []models.Exams{exam})[0])
 }
 
 // @Summary Delete Exam
@@ -148,6 +315,10 @@ func ExamsDeleteHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeExam(c, id) {
+		return
+	}
+
 	err = examsRepo.DeleteExam(context.Background(), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
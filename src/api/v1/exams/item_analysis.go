@@ -0,0 +1,22 @@
+package exams
+
+import (
+	"project-ppl-be/middleware"
+	"project-ppl-be/src/api/v1/scores"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get Exam Item Analysis
+// @Description Get the difficulty, discrimination, distractor frequencies and average score of every question in the exam
+// @Tags Exam Analytics
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param exam_id query int true "Exam ID"
+// @Success 200 {object} models.ItemAnalysis
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/exams/item-analysis [get]
+func ExamItemAnalysisGetHandler(c *gin.Context) {
+	scores.ItemAnalysis(c, "exam_id", "Exam", middleware.AuthorizeExam, examsRepo.GetExamItemAnalysis)
+}
@@ -0,0 +1,36 @@
+package exams
+
+import (
+	"project-ppl-be/middleware"
+	"project-ppl-be/src/api/v1/scores"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Override Exam Question Score
+// @Description Replace the score of one question in a graded exam and recompute the total. Also grades pending essays of exams with manual essay grading. The original auto score, the change, who made it and when are kept in the audit trail.
+// @Tags Exam Overrides
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param override body models.OverrideScoreRequest true "Score, question, new score and comment"
+// @Success 200 {object} models.ExamGrades
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/exams/override-score [patch]
+func ExamScoreOverrideHandler(c *gin.Context) {
+	scores.Override(c, middleware.AuthorizeExamScore, examsRepo.OverrideExamScore)
+}
+
+// @Summary Get Exam Score Overrides
+// @Description List the audit trail of overrides made to a graded exam, oldest first
+// @Tags Exam Overrides
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param score_id query int true "Score ID"
+// @Success 200 {array} models.ScoreOverride
+// @Router /api/v1/exams/score-overrides [get]
+func ExamScoreOverridesGetHandler(c *gin.Context) {
+	scores.Overrides(c, middleware.AuthorizeExamScore, examsRepo.GetExamScoreOverrides)
+}
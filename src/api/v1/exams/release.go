@@ -0,0 +1,90 @@
+package exams
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"project-ppl-be/middleware"
+	"project-ppl-be/src/repo"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// @Summary Release Exam Scores
+// @Description Make the scores of an exam and its review visible to students, whatever its release policy
+// @Tags Exam Release
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id query int true "Exam ID"
+// @Success 200 {object} models.Exams
+// @Router /api/v1/exams/release [post]
+func ExamScoresReleaseHandler(c *gin.Context) {
+	idStr := c.Query("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing exam ID"})
+		return
+	}
+
+	if !middleware.AuthorizeExam(c, id) {
+		return
+	}
+
+	exam, err := examsRepo.ReleaseExamScores(context.Background(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, exam)
+}
+
+// @Summary Get Exam Review
+// @Description Get the student's graded answers next to the correct answers and explanations. Students only see it once the scores are released.
+// @Tags Exam Release
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param exam_id query int true "Exam ID"
+// @Param student_id query int true "Student ID"
+// @Success 200 {object} models.ExamReview
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/exams/review [get]
+func ExamReviewGetHandler(c *gin.Context) {
+	examIDStr := c.Query("exam_id")
+	examID, err := strconv.Atoi(examIDStr)
+	if err != nil || examID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing exam_id"})
+		return
+	}
+
+	studentIDStr := c.Query("student_id")
+	studentID, err := strconv.Atoi(studentIDStr)
+	if err != nil || studentID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing student_id"})
+		return
+	}
+
+	if !middleware.AuthorizeStudentExam(c, studentID, examID) {
+		return
+	}
+
+	principal, _ := middleware.GetPrincipal(c)
+	review, err := examsRepo.GetExamReview(context.Background(), examID, studentID, !principal.IsStudent())
+	if errors.Is(err, repo.ErrScoresNotReleased) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	} else if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Graded exam not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
@@ -0,0 +1,46 @@
+package exams
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"project-ppl-be/middleware"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// @Summary Get Students Below KKM
+// @Description List the students whose latest score on an exam is below its KKM, the candidates for a remedial exam. The exam's KKM falls back to the class's.
+// @Tags Remedial Exams
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param exam_id query int true "Exam ID"
+// @Success 200 {object} models.BelowKKM
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/exams/below-kkm [get]
+func BelowKKMGetHandler(c *gin.Context) {
+	examIDStr := c.Query("exam_id")
+	examID, err := strconv.Atoi(examIDStr)
+	if err != nil || examID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing exam_id"})
+		return
+	}
+
+	if !middleware.AuthorizeExam(c, examID) {
+		return
+	}
+
+	below, err := examsRepo.GetBelowKKM(context.Background(), examID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exam not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, below)
+}
@@ -0,0 +1,25 @@
+package exams
+
+import (
+	"project-ppl-be/middleware"
+	"project-ppl-be/src/api/v1/scores"
+	"project-ppl-be/src/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get Exam Report
+// @Description Get the score distribution of an exam: mean, median, standard deviation, histogram and pass rate, per student as well. Only the class's teacher or an admin can see it.
+// @Tags Exam Analytics
+// @Security BearerAuth
+// @Accept json
+// @Produce json,text/csv
+// @Param exam_id query int true "Exam ID"
+// @Param passing_score query number false "Passing percentage, 75 by default"
+// @Param format query string false "Set to csv to download the report as CSV"
+// @Success 200 {object} models.ExamReport
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/exams/report [get]
+func ExamReportGetHandler(c *gin.Context) {
+	scores.Report(c, "exam_id", "Exam", middleware.AuthorizeExam, examsRepo.GetExamReport, utils.ExamReportCSV, "exam-%d-report.csv")
+}
@@ -0,0 +1,21 @@
+package exams
+
+import (
+	"project-ppl-be/middleware"
+	"project-ppl-be/src/api/v1/scores"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get Exam Score History
+// @Description List the previous versions of a graded exam kept when it was regraded, oldest first
+// @Tags Exam Overrides
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param score_id query int true "Score ID"
+// @Success 200 {array} models.ScoreHistory
+// @Router /api/v1/exams/score-history [get]
+func ExamScoreHistoryGetHandler(c *gin.Context) {
+	scores.History(c, middleware.AuthorizeExamScore, examsRepo.GetExamScoreHistory)
+}
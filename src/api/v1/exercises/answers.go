@@ -2,9 +2,12 @@ package exercises
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"project-ppl-be/middleware"
 	"project-ppl-be/src/models"
 	"project-ppl-be/src/repo"
+	"project-ppl-be/src/utils"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -37,6 +40,10 @@ func ExerciseAnswersGetHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeStudentExercise(c, studentID, exerciseID) {
+		return
+	}
+
 	exercises, err := exerciseAnswersRepo.GetExerciseAnswers(context.Background(), exerciseID, studentID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -63,6 +70,10 @@ func ExerciseAnswersPostHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeStudentExercise(c, req.Student_ID, req.Exercise_ID) {
+		return
+	}
+
 	exercise, err := exercisesRepo.CreateExerciseAnswers(context.Background(), req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -90,12 +101,20 @@ func ExerciseAnswersUpdateHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeExerciseAnswer(c, id) {
+		return
+	}
+
 	var req models.CreateExerciseAnswersRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	if !middleware.AuthorizeStudentExercise(c, req.Student_ID, req.Exercise_ID) {
+		return
+	}
+
 	exercise, err := exercisesRepo.UpdateExerciseAnswers(context.Background(), id, req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -122,6 +141,10 @@ func ExerciseAnswersDeleteHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeExerciseAnswer(c, id) {
+		return
+	}
+
 	err = exercisesRepo.DeleteExerciseAnswers(context.Background(), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -132,13 +155,15 @@ func ExerciseAnswersDeleteHandler(c *gin.Context) {
 }
 
 // @Summary Calculate Grade
-// @Description Calculate grade after submitting exercise
+// @Description Submit the current answers as a new attempt and grade it. Fails when the attempt limit is reached or the answers were already graded.
 // @Tags Exercise Answers (Student Answers)
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param exercise body models.CalculateExerciseGrades true "Exercise grades data"
 // @Success 200 {object} models.ExerciseGrades
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /api/v1/exercises/calculate-grade [post]
 func CalculateGradePostHandler(c *gin.Context) {
 	var req models.CalculateExerciseGrades
@@ -148,17 +173,28 @@ func CalculateGradePostHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeStudentExercise(c, req.Student_ID, req.Exercise_ID) {
+		return
+	}
+
 	exercise, err := exercisesRepo.CalculateExerciseGrades(context.Background(), req)
-	if err != nil {
+	if errors.Is(err, repo.ErrMaxAttemptsReached) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	} else if errors.Is(err, repo.ErrNoNewAnswers) || errors.Is(err, repo.ErrGradingInProgress) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, exercise)
+	principal, _ := middleware.GetPrincipal(c)
+	c.JSON(http.StatusOK, utils.WithholdJustifications(principal.Role, []models.ExerciseGrades{exercise})[0])
 }
 
 // @Summary Get Grade
-// @Description Get calculated grade
+// @Description Get the calculated grade following the exercise score policy (highest, latest or average attempt)
 // @Tags Exercise Answers (Student Answers)
 // @Security BearerAuth
 // @Accept json
@@ -182,13 +218,18 @@ func ExerciseGradesGetHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeStudentExercise(c, studentID, exerciseID) {
+		return
+	}
+
 	exercises, err := exerciseAnswersRepo.GetExerciseGrades(context.Background(), exerciseID, studentID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, exercises)
+	principal, _ := middleware.GetPrincipal(c)
+	c.JSON(http.StatusOK, utils.WithholdJustifications(principal.Role, exercises))
 }
 
 // @Summary Get All Grades
@@ -208,11 +249,55 @@ func ExerciseAllGradesGetHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeStudent(c, studentID) {
+		return
+	}
+
 	exercises, err := exerciseAnswersRepo.GetAllExerciseGrades(context.Background(), studentID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, exercises)
+	principal, _ := middleware.GetPrincipal(c)
+	c.JSON(http.StatusOK, utils.WithholdJustifications(principal.Role, exercises))
+}
+
+// @Summary Get Exercise Attempts
+// @Description Get the student's attempt history with the answers and score of each attempt
+// @Tags Exercise Answers (Student Answers)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param exercise_id query int true "Exercise ID"
+// @Param student_id query int true "Student ID"
+// @Success 200 {array} models.ExerciseAttempt
+// @Router /api/v1/exercises/attempts [get]
+func ExerciseAttemptsGetHandler(c *gin.Context) {
+	exerciseIDStr := c.Query("exercise_id")
+	exerciseID, err := strconv.Atoi(exerciseIDStr)
+	if err != nil || exerciseID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing exercise_id"})
+		return
+	}
+
+	studentIDStr := c.Query("student_id")
+	studentID, err := strconv.Atoi(studentIDStr)
+	if err != nil || studentID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing student_id"})
+		return
+	}
+
+	if !middleware.AuthorizeStudentExercise(c, studentID, exerciseID) {
+		return
+	}
+
+	attempts, err := exerciseAnswersRepo.GetExerciseAttempts(context.Background(), exerciseID, studentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal, _ := middleware.GetPrincipal(c)
+	c.JSON(http.StatusOK, utils.WithholdAttemptJustifications(principal.Role, attempts))
 }
@@ -2,15 +2,19 @@ package exercises
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"project-ppl-be/middleware"
 	"project-ppl-be/src/models"
 	"project-ppl-be/src/repo"
+	"project-ppl-be/src/utils"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 var exercisesRepo = repo.ExerciseRepository{}
+var questionBankRepo = repo.QuestionBankRepository{}
 
 // @Summary Get Exercises by Material ID
 // @Description Fetch all exercises for a specific material
@@ -29,13 +33,18 @@ func ExercisesGetByMaterialHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeMaterial(c, materialID) {
+		return
+	}
+
 	exercises, err := exercisesRepo.GetExercisesByMaterialID(context.Background(), materialID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, exercises)
+	principal, _ := middleware.GetPrincipal(c)
+	c.JSON(http.StatusOK, utils.SanitizeExercisesForRole(principal.Role, exercises))
 }
 
 // @Summary Get Exercises for Student
@@ -63,13 +72,18 @@ func ExercisesGetByMaterialForStudentHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeMaterial(c, materialID) {
+		return
+	}
+
 	exercises, err := exercisesRepo.GetExercisesByMaterialIDForStudent(context.Background(), materialID, number)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, exercises)
+	principal, _ := middleware.GetPrincipal(c)
+	c.JSON(http.StatusOK, utils.SanitizeExercisesForRole(principal.Role, exercises))
 }
 
 // @Summary Create Exercise
@@ -89,13 +103,39 @@ func ExercisesPostHandler(c *gin.Context) {
 		return
 	}
 
+	err := questionBankRepo.AppendBankQuestions(context.Background(), &req.Content, req.Bank_Question_IDs)
+	if errors.Is(err, repo.ErrBankQuestionNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.Content.Normalize()
+	if err := req.Content.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content: " + err.Error()})
+		return
+	}
+
+	req.Total_Marks, err = req.Content.ResolveTotalMarks(req.Total_Marks)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !middleware.AuthorizeMaterial(c, req.Material_ID) || !middleware.AuthorizeTeacher(c, req.Teacher_ID) {
+		return
+	}
+
 	exercise, err := exercisesRepo.CreateExercise(context.Background(), req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, exercise)
+	principal, _ := middleware.GetPrincipal(c)
+	c.JSON(http.StatusOK, utils.SanitizeExercisesForRole(principal.Role, []models.Exercises{exercise})[0])
 }
 
 // @Summary Update Exercise
@@ -106,7 +146,9 @@ func ExercisesPostHandler(c *gin.Context) {
 // @Produce json
 // @Param id query int true "Exercise ID"
 // @Param exercise body models.CreateExercisesRequest true "Updated data"
+// @Param confirm_regrade query bool false "Regrade stored scores when answer keys or weights change"
 // @Success 200 {object} models.Exercises
+// @Failure 409 {object} map[string]interface{} "Stored scores would change; includes a preview"
 // @Router /api/v1/exercises [patch]
 func ExercisesUpdateHandler(c *gin.Context) {
 	idStr := c.Query("id")
@@ -116,19 +158,84 @@ func ExercisesUpdateHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeExercise(c, id) {
+		return
+	}
+
 	var req models.CreateExercisesRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	exercise, err := exercisesRepo.UpdateExercise(context.Background(), id, req)
+	err = questionBankRepo.AppendBankQuestions(context.Background(), &req.Content, req.Bank_Question_IDs)
+	if errors.Is(err, repo.ErrBankQuestionNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.Content.Normalize()
+	if err := req.Content.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content: " + err.Error()})
+		return
+	}
+
+	req.Total_Marks, err = req.Content.ResolveTotalMarks(req.Total_Marks)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !middleware.AuthorizeMaterial(c, req.Material_ID) || !middleware.AuthorizeTeacher(c, req.Teacher_ID) {
+		return
+	}
+
+	current, err := exercisesRepo.GetExerciseByID(context.Background(), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, exercise)
+	// Nilai yang sudah tersimpan dinilai ulang hanya setelah guru melihat pratinjau dan mengonfirmasi
+	changedQuestions := utils.ScoringChanges(current.Content, req.Content)
+	regrade := len(changedQuestions) > 0
+	confirmRegrade, _ := strconv.ParseBool(c.DefaultQuery("confirm_regrade", "false"))
+	if regrade && !confirmRegrade {
+		changes, total, err := exercisesRepo.PreviewExerciseRegrade(context.Background(), id, req.Content)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if total > 0 {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "Changing the answer key or weights affects stored scores; resend with confirm_regrade=true to regrade them",
+				"preview": models.RegradePreview{
+					Changed_Questions: changedQuestions,
+					Total_Scores:      total,
+					Affected_Scores:   len(changes),
+					Changes:           changes,
+				},
+			})
+			return
+		}
+	}
+
+	principal, _ := middleware.GetPrincipal(c)
+	var regradeBy *int
+	if regrade && confirmRegrade {
+		regradeBy = &principal.User_ID
+	}
+
+	exercise, err := exercisesRepo.UpdateExercise(context.Background(), id, req, regradeBy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SanitizeExercisesForRole(principal.Role, []models.Exercises{exercise})[0])
 }
 
 // @Summary Delete Exercise
@@ -148,6 +255,10 @@ func ExercisesDeleteHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeExercise(c, id) {
+		return
+	}
+
 	err = exercisesRepo.DeleteExercise(context.Background(), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
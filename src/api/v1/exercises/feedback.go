@@ -0,0 +1,117 @@
+package exercises
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"project-ppl-be/middleware"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// @Summary Update Essay Feedback
+// @Description Replace the feedback on one question of a graded attempt. Students see it once the feedback is released.
+// @Tags Exercise Feedback
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param feedback body models.UpdateEssayFeedbackRequest true "Score, question and feedback"
+// @Success 200 {object} models.EssayFeedback
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/exercises/feedback [patch]
+func EssayFeedbackUpdateHandler(c *gin.Context) {
+	var req models.UpdateEssayFeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !middleware.AuthorizeExerciseScore(c, req.Score_ID) {
+		return
+	}
+
+	principal, _ := middleware.GetPrincipal(c)
+	feedback, err := exercisesRepo.UpdateEssayFeedback(context.Background(), req, principal.User_ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found in this score"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, feedback)
+}
+
+// @Summary Release Essay Feedback
+// @Description Make the feedback of a graded attempt visible to the student
+// @Tags Exercise Feedback
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param release body models.ReleaseEssayFeedbackRequest true "Score ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/exercises/feedback/release [post]
+func EssayFeedbackReleaseHandler(c *gin.Context) {
+	var req models.ReleaseEssayFeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !middleware.AuthorizeExerciseScore(c, req.Score_ID) {
+		return
+	}
+
+	err := exercisesRepo.ReleaseEssayFeedback(context.Background(), req.Score_ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Feedback released successfully"})
+}
+
+// @Summary Get Graded Attempt
+// @Description Get a graded exercise attempt with its per-question detail and essay feedback. Students only see feedback after it is released.
+// @Tags Exercise Feedback
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param attempt_id query int true "Attempt ID"
+// @Success 200 {object} models.GradedAttempt
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/exercises/graded-attempt [get]
+func GradedAttemptGetHandler(c *gin.Context) {
+	attemptIDStr := c.Query("attempt_id")
+	attemptID, err := strconv.Atoi(attemptIDStr)
+	if err != nil || attemptID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing attempt_id"})
+		return
+	}
+
+	principal, _ := middleware.GetPrincipal(c)
+	attempt, err := exercisesRepo.GetGradedAttempt(context.Background(), attemptID, !principal.IsStudent())
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Graded attempt not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !middleware.AuthorizeStudent(c, attempt.Student_ID) {
+		return
+	}
+
+	attempt.ExerciseAttempt = utils.WithholdAttemptJustifications(principal.Role, []models.ExerciseAttempt{attempt.ExerciseAttempt})[0]
+	c.JSON(http.StatusOK, attempt)
+}
@@ -0,0 +1,93 @@
+package exercises
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"project-ppl-be/middleware"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/repo"
+	"project-ppl-be/src/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// @Summary Queue Grade Calculation
+// @Description Submit the current answers as a new attempt and queue it for grading. Essays are graded in the background; poll the grading job until it is Completed to get the grade.
+// @Tags Exercise Answers (Student Answers)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param exercise body models.CalculateExerciseGrades true "Exercise grades data"
+// @Success 202 {object} models.GradingJob
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/exercises/calculate-grade-async [post]
+func CalculateGradeAsyncPostHandler(c *gin.Context) {
+	var req models.CalculateExerciseGrades
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !middleware.AuthorizeStudentExercise(c, req.Student_ID, req.Exercise_ID) {
+		return
+	}
+
+	job, err := exercisesRepo.EnqueueExerciseGrading(context.Background(), req)
+	if errors.Is(err, repo.ErrMaxAttemptsReached) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	} else if errors.Is(err, repo.ErrNoNewAnswers) || errors.Is(err, repo.ErrGradingInProgress) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// @Summary Get Grading Job
+// @Description Get the progress of a queued grade calculation. The grade is included once the job is Completed; students only see an exam score once it is released.
+// @Tags Exercise Answers (Student Answers)
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id query int true "Grading job ID"
+// @Success 200 {object} models.GradingJob
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/exercises/grading-job [get]
+func GradingJobGetHandler(c *gin.Context) {
+	idStr := c.Query("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing id"})
+		return
+	}
+
+	job, err := exercisesRepo.GetGradingJob(context.Background(), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Grading job not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !middleware.AuthorizeStudent(c, job.Student_ID) {
+		return
+	}
+
+	// Nilai ujian baru terlihat oleh siswa setelah dirilis
+	if job.Exam_Grade != nil {
+		principal, _ := middleware.GetPrincipal(c)
+		grade := utils.WithholdUnreleasedGrades(principal.Role, []models.ExamGrades{*job.Exam_Grade})[0]
+		job.Exam_Grade = &grade
+	}
+
+	c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,22 @@
+package exercises
+
+import (
+	"project-ppl-be/middleware"
+	"project-ppl-be/src/api/v1/scores"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get Exercise Item Analysis
+// @Description Get the difficulty, discrimination, distractor frequencies and average score of every question in the exercise
+// @Tags Exercise Analytics
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param exercise_id query int true "Exercise ID"
+// @Success 200 {object} models.ItemAnalysis
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/exercises/item-analysis [get]
+func ExerciseItemAnalysisGetHandler(c *gin.Context) {
+	scores.ItemAnalysis(c, "exercise_id", "Exercise", middleware.AuthorizeExercise, exercisesRepo.GetExerciseItemAnalysis)
+}
@@ -0,0 +1,36 @@
+package exercises
+
+import (
+	"project-ppl-be/middleware"
+	"project-ppl-be/src/api/v1/scores"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Override Exercise Question Score
+// @Description Replace the score of one question in a graded exercise and recompute the total. The original auto score, the change, who made it and when are kept in the audit trail.
+// @Tags Exercise Overrides
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param override body models.OverrideScoreRequest true "Score, question, new score and comment"
+// @Success 200 {object} models.ExerciseGrades
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/exercises/override-score [patch]
+func ExerciseScoreOverrideHandler(c *gin.Context) {
+	scores.Override(c, middleware.AuthorizeExerciseScore, exercisesRepo.OverrideExerciseScore)
+}
+
+// @Summary Get Exercise Score Overrides
+// @Description List the audit trail of overrides made to a graded exercise, oldest first
+// @Tags Exercise Overrides
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param score_id query int true "Score ID"
+// @Success 200 {array} models.ScoreOverride
+// @Router /api/v1/exercises/score-overrides [get]
+func ExerciseScoreOverridesGetHandler(c *gin.Context) {
+	scores.Overrides(c, middleware.AuthorizeExerciseScore, exercisesRepo.GetExerciseScoreOverrides)
+}
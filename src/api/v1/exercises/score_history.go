@@ -0,0 +1,21 @@
+package exercises
+
+import (
+	"project-ppl-be/middleware"
+	"project-ppl-be/src/api/v1/scores"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get Exercise Score History
+// @Description List the previous versions of a graded exercise kept when it was regraded, oldest first
+// @Tags Exercise Overrides
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param score_id query int true "Score ID"
+// @Success 200 {array} models.ScoreHistory
+// @Router /api/v1/exercises/score-history [get]
+func ExerciseScoreHistoryGetHandler(c *gin.Context) {
+	scores.History(c, middleware.AuthorizeExerciseScore, exercisesRepo.GetExerciseScoreHistory)
+}
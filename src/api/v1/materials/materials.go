@@ -4,6 +4,7 @@ import (
 	"context"
 	"math"
 	"net/http"
+	"project-ppl-be/middleware"
 	"project-ppl-be/src/models"
 	"project-ppl-be/src/repo"
 	"strconv"
@@ -36,8 +37,14 @@ func MaterialsGetHandler(c *gin.Context) {
 		pageSize = 15
 	}
 
+	// Guru hanya melihat materi dari kelas yang diajarnya
+	teacherID := 0
+	if principal, _ := middleware.GetPrincipal(c); principal.IsTeacher() {
+		teacherID = principal.Teacher_ID
+	}
+
 	// Ambil data dengan pagination dan filter grade
-	materials, total, err := materialsRepo.GetAllMaterials(context.Background(), page, pageSize)
+	materials, total, err := materialsRepo.GetAllMaterials(context.Background(), page, pageSize, teacherID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -76,6 +83,10 @@ func MaterialsGetByClassIdHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeClass(c, id) {
+		return
+	}
+
 	// Ambil parameter query dari request
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "15"))
@@ -125,6 +136,10 @@ func MaterialsPostHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeClass(c, req.Class_ID) || !middleware.AuthorizeTeacher(c, req.Teacher_ID) {
+		return
+	}
+
 	// Call CreateUser with the extracted values
 	user, err := materialsRepo.CreateMaterial(context.Background(), req.Class_ID, req.Title, req.Description, req.Content, req.Teacher_ID)
 	if err != nil {
@@ -158,12 +173,20 @@ func MaterialsUpdateHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeMaterial(c, id) {
+		return
+	}
+
 	// Parse JSON request body
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	if !middleware.AuthorizeClass(c, req.Class_ID) || !middleware.AuthorizeTeacher(c, req.Teacher_ID) {
+		return
+	}
+
 	// Call UpdateMaterial with the correct parameters
 	material, err := materialsRepo.UpdateMaterial(
 		context.Background(),
@@ -202,6 +225,10 @@ func MaterialsDeleteHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeMaterial(c, id) {
+		return
+	}
+
 	// Call DeleteTeacher function from repository
 	err = materialsRepo.DeleteMaterial(context.Background(), id)
 	if err != nil {
@@ -0,0 +1,235 @@
+package questionbank
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"project-ppl-be/middleware"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/repo"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+var questionBankRepo = repo.QuestionBankRepository{}
+
+// @Summary Get Bank Questions
+// @Description Fetch questions from the question bank with optional filters and pagination
+// @Tags Question Bank
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param subject query string false "Subject"
+// @Param grade query int false "Grade"
+// @Param topic query string false "Topic (partial match)"
+// @Param difficulty query string false "Difficulty (easy, medium, hard)"
+// @Param tag query string false "Tag"
+// @Param page query int false "Page number (default: 1)"
+// @Param pageSize query int false "Number of items per page (default: 15)"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/question-bank [get]
+func BankQuestionsGetHandler(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "15"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 15
+	}
+
+	grade, _ := strconv.Atoi(c.Query("grade"))
+	filter := models.BankQuestionFilter{
+		Subject:    c.Query("subject"),
+		Grade:      grade,
+		Topic:      c.Query("topic"),
+		Difficulty: c.Query("difficulty"),
+		Tag:        c.Query("tag"),
+	}
+
+	questions, total, err := questionBankRepo.GetBankQuestions(context.Background(), page, pageSize, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"questions": questions,
+		"meta": gin.H{
+			"page":      page,
+			"pageSize":  pageSize,
+			"total":     total,
+			"totalPage": int(math.Ceil(float64(total) / float64(pageSize))),
+		},
+	})
+}
+
+// @Summary Get Bank Question by ID
+// @Description Fetch a single question from the question bank
+// @Tags Question Bank
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id query int true "Bank Question ID"
+// @Success 200 {object} models.BankQuestion
+// @Router /api/v1/question-bank/details [get]
+func BankQuestionGetByIDHandler(c *gin.Context) {
+	idStr := c.Query("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing question ID"})
+		return
+	}
+
+	question, err := questionBankRepo.GetBankQuestionByID(context.Background(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, question)
+}
+
+// @Summary Create Bank Question
+// @Description Add a reusable question to the question bank
+// @Tags Question Bank
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param question body models.CreateBankQuestionRequest true "Bank question data"
+// @Success 200 {object} models.BankQuestion
+// @Router /api/v1/question-bank [post]
+func BankQuestionPostHandler(c *gin.Context) {
+	var req models.CreateBankQuestionRequest
+	if !bindBankQuestion(c, &req) {
+		return
+	}
+
+	if !middleware.AuthorizeTeacher(c, req.Teacher_ID) {
+		return
+	}
+
+	question, err := questionBankRepo.CreateBankQuestion(context.Background(), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, question)
+}
+
+// @Summary Update Bank Question
+// @Description Update a question in the question bank. Exams and exercises that already use it are not changed.
+// @Tags Question Bank
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id query int true "Bank Question ID"
+// @Param question body models.CreateBankQuestionRequest true "Updated data"
+// @Success 200 {object} models.BankQuestion
+// @Router /api/v1/question-bank [patch]
+func BankQuestionUpdateHandler(c *gin.Context) {
+	idStr := c.Query("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing question ID"})
+		return
+	}
+
+	if !middleware.AuthorizeBankQuestion(c, id) {
+		return
+	}
+
+	var req models.CreateBankQuestionRequest
+	if !bindBankQuestion(c, &req) {
+		return
+	}
+
+	if !middleware.AuthorizeTeacher(c, req.Teacher_ID) {
+		return
+	}
+
+	question, err := questionBankRepo.UpdateBankQuestion(context.Background(), id, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, question)
+}
+
+// @Summary Delete Bank Question
+// @Description Delete a question from the question bank
+// @Tags Question Bank
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id query int true "Bank Question ID"
+// @Success 200 {object} map[string]string
+// @Router /api/v1/question-bank [delete]
+func BankQuestionDeleteHandler(c *gin.Context) {
+	idStr := c.Query("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing question ID"})
+		return
+	}
+
+	if !middleware.AuthorizeBankQuestion(c, id) {
+		return
+	}
+
+	if err := questionBankRepo.DeleteBankQuestion(context.Background(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Question deleted successfully"})
+}
+
+// @Summary Get Bank Question Usages
+// @Description List the exams and exercises a bank question has appeared in
+// @Tags Question Bank
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id query int true "Bank Question ID"
+// @Success 200 {array} models.BankQuestionUsage
+// @Router /api/v1/question-bank/usages [get]
+func BankQuestionUsagesGetHandler(c *gin.Context) {
+	idStr := c.Query("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing question ID"})
+		return
+	}
+
+	if !middleware.AuthorizeBankQuestion(c, id) {
+		return
+	}
+
+	usages, err := questionBankRepo.GetUsages(context.Background(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, usages)
+}
+
+// bindBankQuestion binds the request body and validates the question against the question schema
+func bindBankQuestion(c *gin.Context, req *models.CreateBankQuestionRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+
+	req.Question.Normalize()
+	if err := req.Question.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question: " + err.Error()})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package scores
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"project-ppl-be/src/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// ItemAnalysis responds with the item analysis of the exam or exercise whose ID is in the param query parameter
+// after authorize allows the caller to see it. notFound names the item in the 404 message.
+func ItemAnalysis(c *gin.Context, param, notFound string, authorize func(*gin.Context, int) bool, get func(context.Context, int) (models.ItemAnalysis, error)) {
+	id, ok := IDQuery(c, param)
+	if !ok {
+		return
+	}
+
+	if !authorize(c, id) {
+		return
+	}
+
+	analysis, err := get(context.Background(), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound + " not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, analysis)
+}
+
+// IDQuery reads a positive ID from the param query parameter
+func IDQuery(c *gin.Context, param string) (int, bool) {
+	id, err := strconv.Atoi(c.Query(param))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing " + param})
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,13 @@
+package scores
+
+import (
+	"context"
+	"project-ppl-be/src/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// History lists the previous versions of the score_id in the query after authorize allows the caller to see the score
+func History(c *gin.Context, authorize func(*gin.Context, int) bool, list func(context.Context, int) ([]models.ScoreHistory, error)) {
+	scoreList(c, authorize, list)
+}
@@ -0,0 +1,79 @@
+// Package scores holds the handler code shared by exam and exercise scores
+package scores
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"project-ppl-be/middleware"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/repo"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Override replaces the score of one question with override after authorize allows the caller to change the score
+func Override[T any](c *gin.Context, authorize func(*gin.Context, int) bool, override func(context.Context, models.OverrideScoreRequest, int) (T, error)) {
+	var req models.OverrideScoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Score == nil && req.Comment == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Provide a score, a comment or both"})
+		return
+	}
+
+	if !authorize(c, req.Score_ID) {
+		return
+	}
+
+	principal, _ := middleware.GetPrincipal(c)
+	grade, err := override(context.Background(), req, principal.User_ID)
+	if errors.Is(err, repo.ErrQuestionNotInScore) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	} else if errors.Is(err, repo.ErrScoreAboveMax) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	} else if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, grade)
+}
+
+// Overrides lists the audit trail of the score_id in the query after authorize allows the caller to see the score
+func Overrides(c *gin.Context, authorize func(*gin.Context, int) bool, list func(context.Context, int) ([]models.ScoreOverride, error)) {
+	scoreList(c, authorize, list)
+}
+
+// scoreList responds with what list returns for the score_id in the query after authorize allows the caller to see the score
+func scoreList[T any](c *gin.Context, authorize func(*gin.Context, int) bool, list func(context.Context, int) ([]T, error)) {
+	scoreID, ok := ScoreIDQuery(c)
+	if !ok {
+		return
+	}
+
+	if !authorize(c, scoreID) {
+		return
+	}
+
+	items, err := list(context.Background(), scoreID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// ScoreIDQuery reads the score_id query parameter
+func ScoreIDQuery(c *gin.Context) (int, bool) {
+	return IDQuery(c, "score_id")
+}
@@ -0,0 +1,63 @@
+package scores
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"project-ppl-be/src/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// Report responds with the report of the exam or class whose ID is in the param query parameter after authorize
+// allows the caller to see it. With format=csv the records are downloaded as the file named by filename,
+// a format taking the ID.
+func Report[T any](c *gin.Context, param, notFound string, authorize func(*gin.Context, int) bool,
+	get func(context.Context, int, float64) (T, error), records func(T) [][]string, filename string) {
+	id, ok := IDQuery(c, param)
+	if !ok {
+		return
+	}
+
+	passingScore, err := strconv.ParseFloat(c.DefaultQuery("passing_score", strconv.Itoa(utils.DefaultPassingScore)), 64)
+	if err != nil || passingScore < 0 || passingScore > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "passing_score must be a percentage between 0 and 100"})
+		return
+	}
+
+	if !authorize(c, id) {
+		return
+	}
+
+	report, err := get(context.Background(), id, passingScore)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound + " not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		CSV(c, records(report), fmt.Sprintf(filename, id))
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// CSV downloads records as a CSV file
+func CSV(c *gin.Context, records [][]string, filename string) {
+	var buf bytes.Buffer
+	if err := csv.NewWriter(&buf).WriteAll(records); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, "text/csv", buf.Bytes())
+}
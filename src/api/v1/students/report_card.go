@@ -0,0 +1,167 @@
+package students
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"project-ppl-be/middleware"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/repo"
+	"project-ppl-be/src/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+var reportCardRepo = repo.ReportCardRepository{}
+
+// @Summary Get Report Card
+// @Description Get a student's report card as a PDF printed with the school's layout: their profile, the current final grade and KKM of every class, and the attendance and teacher's remarks for the semester. Unreleased exam scores are not counted in the grades.
+// @Tags Report Cards
+// @Security BearerAuth
+// @Accept json
+// @Produce application/pdf,json
+// @Param student_id query int true "Student ID"
+// @Param semester query string true "Semester, e.g. 2024/2025 Ganjil"
+// @Param format query string false "Set to json to get the report card data instead of the PDF"
+// @Success 200 {object} models.ReportCard
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/students/report-card [get]
+func ReportCardGetHandler(c *gin.Context) {
+	idStr := c.Query("student_id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing student_id"})
+		return
+	}
+
+	semester, ok := semesterQuery(c)
+	if !ok {
+		return
+	}
+
+	if !middleware.AuthorizeStudent(c, id) {
+		return
+	}
+
+	card, err := reportCardRepo.GetReportCard(context.Background(), id, semester)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Student not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("format") == "json" {
+		c.JSON(http.StatusOK, card)
+		return
+	}
+	writeReportCards(c, []models.ReportCard{card}, fmt.Sprintf("report-card-%d.pdf", id))
+}
+
+// @Summary Get Report Cards by Grade
+// @Description Print the report cards of every student in a grade level as one PDF, one student per page. Teachers only get the students they teach.
+// @Tags Report Cards
+// @Security BearerAuth
+// @Accept json
+// @Produce application/pdf
+// @Param grade query int true "Grade level"
+// @Param semester query string true "Semester, e.g. 2024/2025 Ganjil"
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/students/report-cards [get]
+func ReportCardsByGradeHandler(c *gin.Context) {
+	gradeStr := c.Query("grade")
+	grade, err := strconv.Atoi(gradeStr)
+	if err != nil || grade <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing grade"})
+		return
+	}
+
+	semester, ok := semesterQuery(c)
+	if !ok {
+		return
+	}
+
+	// Admin mencetak semua siswa, guru hanya siswa yang diajarnya
+	principal, _ := middleware.GetPrincipal(c)
+	teacherID := 0
+	if !principal.IsAdmin() {
+		teacherID = principal.Teacher_ID
+	}
+
+	cards, err := reportCardRepo.GetReportCardsByGrade(context.Background(), grade, semester, teacherID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(cards) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No students found in this grade"})
+		return
+	}
+
+	writeReportCards(c, cards, fmt.Sprintf("report-cards-grade-%d.pdf", grade))
+}
+
+// @Summary Update Report Card Notes
+// @Description Set the teacher's remarks and the attendance printed on a student's report card for a semester. Fields left out keep their value; only the teacher who wrote the remarks or an admin can change them.
+// @Tags Report Cards
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param note body models.UpdateReportCardNoteRequest true "Remarks and attendance"
+// @Success 200 {object} models.ReportCardNote
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/students/report-card/notes [patch]
+func ReportCardNoteUpdateHandler(c *gin.Context) {
+	var req models.UpdateReportCardNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Remarks == nil && req.Sick_Days == nil && req.Permitted_Days == nil && req.Absent_Days == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Provide remarks, attendance or both"})
+		return
+	}
+
+	if !middleware.AuthorizeStudent(c, req.Student_ID) {
+		return
+	}
+
+	principal, _ := middleware.GetPrincipal(c)
+	note, err := reportCardRepo.UpdateReportCardNote(context.Background(), req, principal.User_ID, principal.IsAdmin())
+	if errors.Is(err, repo.ErrRemarksWrittenByOther) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, note)
+}
+
+func semesterQuery(c *gin.Context) (string, bool) {
+	semester := c.Query("semester")
+	if semester == "" || len(semester) > 20 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "semester is required and at most 20 characters"})
+		return "", false
+	}
+	return semester, true
+}
+
+func writeReportCards(c *gin.Context, cards []models.ReportCard, filename string) {
+	var buf bytes.Buffer
+	if err := utils.RenderReportCards(&buf, cards); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
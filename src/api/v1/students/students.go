@@ -4,6 +4,7 @@ import (
 	"context"
 	"math"
 	"net/http"
+	"project-ppl-be/middleware"
 	"project-ppl-be/src/models"
 	"project-ppl-be/src/repo"
 	"strconv"
@@ -200,6 +201,10 @@ func StudentGetByIDHandler(c *gin.Context) {
 		return
 	}
 
+	if !middleware.AuthorizeStudent(c, id) {
+		return
+	}
+
 	// Ambil data student berdasarkan ID
 	student, err := studentRepo.GetStudentByID(context.Background(), id)
 	if err != nil {
@@ -0,0 +1,55 @@
+package students
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"project-ppl-be/middleware"
+	"project-ppl-be/src/api/v1/scores"
+	"project-ppl-be/src/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// @Summary Get Transcript
+// @Description Get a student's full academic transcript: every class they are assigned to with all exercise attempts, the score that counts for each exam with the score before any remedial, final grades and dates. Exam scores that are not released yet are left empty. The JSON layout is versioned by schema_version.
+// @Tags Transcripts
+// @Security BearerAuth
+// @Accept json
+// @Produce json,text/csv
+// @Param student_id query int true "Student ID"
+// @Param format query string false "Set to csv to download the transcript as CSV"
+// @Success 200 {object} models.Transcript
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/students/transcript [get]
+func TranscriptGetHandler(c *gin.Context) {
+	idStr := c.Query("student_id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing student_id"})
+		return
+	}
+
+	if !middleware.AuthorizeStudent(c, id) {
+		return
+	}
+
+	transcript, err := studentRepo.GetTranscript(context.Background(), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Student not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		scores.CSV(c, utils.TranscriptCSV(transcript), fmt.Sprintf("transcript-%d.csv", id))
+		return
+	}
+
+	c.JSON(http.StatusOK, transcript)
+}
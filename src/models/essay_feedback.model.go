@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// EssayFeedback is the feedback on one essay answer of a graded attempt.
+// Generated keeps the grader's text, Feedback is what the student sees once released.
+type EssayFeedback struct {
+	ID           int       `json:"id" db:"id"`
+	Score_ID     int       `json:"score_id" db:"score_id"`
+	Question_Key string    `json:"question_key" db:"question_key"`
+	Generated    string    `json:"generated,omitempty" db:"generated"`
+	Feedback     string    `json:"feedback" db:"feedback"`
+	Edited_By    *int      `json:"edited_by,omitempty" db:"edited_by"`
+	Updated_At   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// UpdateEssayFeedbackRequest replaces the feedback on one essay answer
+type UpdateEssayFeedbackRequest struct {
+	Score_ID     int    `json:"score_id" binding:"required"`
+	Question_Key string `json:"question_key" binding:"required"`
+	Feedback     string `json:"feedback" binding:"required"`
+}
+
+// ReleaseEssayFeedbackRequest releases the feedback of a graded attempt to the student
+type ReleaseEssayFeedbackRequest struct {
+	Score_ID int `json:"score_id" binding:"required"`
+}
+
+// GradedAttempt is an exercise attempt with its score, per-question detail and released feedback
+type GradedAttempt struct {
+	ExerciseAttempt
+	Score_ID             int             `json:"score_id"`
+	Feedback_Released_At *time.Time      `json:"feedback_released_at"`
+	Feedback             []EssayFeedback `json:"feedback"`
+}
@@ -7,23 +7,97 @@ type Exams struct {
 	ID          int    `json:"id" db:"id"`
 	Class_ID  int    `json:"class_id" db:"class_id"`
 	Title       string `json:"title" db:"title"`
-	Content any `json:"content" db:"content"`
+	Content QuestionContent `json:"content" db:"content"`
 	Total_Marks     int    `json:"total_marks" db:"total_marks"`
 	Teacher_ID int `json:"teacher_id" db:"teacher_id"`
 	Start_Time   time.Time      `json:"start_time" db:"start_time"`
 	End_Time     time.Time      `json:"end_time" db:"end_time"`
 	Status       string         `json:"status" db:"status"`
+	// Negative_Marking is the fraction of a question's points deducted for a wrong multiple choice or true/false answer
+	Negative_Marking float64 `json:"negative_marking" db:"negative_marking"`
+	// Late_Grace_Minutes is how long after end_time answers are still accepted and flagged as late
+	Late_Grace_Minutes int `json:"late_grace_minutes" db:"late_grace_minutes"`
+	// Duration_Minutes limits each student's attempt, counted from when they start it; 0 means no timer
+	Duration_Minutes int `json:"duration_minutes" db:"duration_minutes"`
+	// Essay_Grading is how essays are scored: auto, manual by the teacher, or queued for background grading
+	Essay_Grading string `json:"essay_grading" db:"essay_grading"`
+	// Release_Policy decides when students see their scores: immediately, after the exam window closes, or manually
+	Release_Policy string `json:"release_policy" db:"release_policy"`
+	// Released_At is when a teacher released the scores; it releases them under any policy
+	Released_At *time.Time `json:"released_at" db:"released_at"`
+	// KKM is the passing percentage of this exam; when empty the class's KKM applies
+	KKM *float64 `json:"kkm" db:"kkm"`
+	// Remedial_Of is the exam a remedial exam repeats; only the students assigned to it may take it
+	Remedial_Of *int `json:"remedial_of" db:"remedial_of"`
+	// Remedial_Policy decides how a remedial score is recorded on the original exam
+	Remedial_Policy string `json:"remedial_policy" db:"remedial_policy"`
+}
+
+// Essay grading modes of an exam
+const (
+	EssayGradingAuto   = "auto"
+	EssayGradingManual = "manual"
+	EssayGradingQueued = "queued"
+)
+
+// Remedial score policies: the recorded score never drops below the original one
+const (
+	// RemedialCapAtKKM records the remedial score up to the KKM
+	RemedialCapAtKKM = "cap_at_kkm"
+	// RemedialHighest records the higher of the two scores
+	RemedialHighest = "highest"
+	// RemedialAverage records the average of the two scores
+	RemedialAverage = "average"
+)
+
+// Score release policies of an exam
+const (
+	ReleaseImmediate  = "immediate"
+	ReleaseAfterClose = "after_close"
+	ReleaseManual     = "manual"
+)
+
+// ScoresReleased reports whether students may see their scores at the given time.
+// The window closes once the late grace period after end_time is over.
+func (e Exams) ScoresReleased(now time.Time) bool {
+	if e.Released_At != nil {
+		return true
+	}
+	switch e.Release_Policy {
+	case ReleaseAfterClose:
+		return !now.Before(e.End_Time.Add(time.Duration(e.Late_Grace_Minutes) * time.Minute))
+	case ReleaseManual:
+		return false
+	}
+	return true
 }
 
 // CreateExercisesRequest represents the request body for creating an exam
 type CreateExamsRequest struct {
 	Class_ID  int    `json:"class_id" db:"class_id"`
 	Title       string `json:"title" db:"title"`
-	Content any `json:"content" db:"content"`
+	Content QuestionContent `json:"content" db:"content"`
+	// Total_Marks must equal the sum of question weights, or 0 to derive it from them
 	Total_Marks     int    `json:"total_marks" db:"total_marks"`
 	Teacher_ID int `json:"teacher_id" db:"teacher_id"`
 	Start_Time   time.Time      `json:"start_time" db:"start_time"`
 	End_Time     time.Time      `json:"end_time" db:"end_time"`
+	Negative_Marking float64 `json:"negative_marking" db:"negative_marking" binding:"gte=0,lte=1"`
+	Late_Grace_Minutes int `json:"late_grace_minutes" db:"late_grace_minutes" binding:"gte=0"`
+	Duration_Minutes int `json:"duration_minutes" db:"duration_minutes" binding:"gte=0"`
+	// Essay_Grading is auto, manual or queued; it defaults to auto
+	Essay_Grading string `json:"essay_grading" db:"essay_grading" binding:"omitempty,oneof=auto manual queued"`
+	// Release_Policy is immediate, after_close or manual; it defaults to immediate
+	Release_Policy string `json:"release_policy" db:"release_policy" binding:"omitempty,oneof=immediate after_close manual"`
+	KKM *float64 `json:"kkm" db:"kkm" binding:"omitempty,gte=0,lte=100"`
+	// Remedial_Of makes this a remedial exam of another exam in the same class. It is set on creation only.
+	Remedial_Of *int `json:"remedial_of,omitempty" db:"remedial_of"`
+	// Remedial_Policy is cap_at_kkm, highest or average; it defaults to cap_at_kkm
+	Remedial_Policy string `json:"remedial_policy" db:"remedial_policy" binding:"omitempty,oneof=cap_at_kkm highest average"`
+	// Student_IDs limits a new remedial exam to these students; by default it is given to every student below the KKM
+	Student_IDs []int `json:"student_ids,omitempty" db:"-"`
+	// Bank_Question_IDs are copied from the question bank and appended after Content
+	Bank_Question_IDs []int `json:"bank_question_ids,omitempty" db:"-"`
 }
 
 type ExamAnswers struct {
@@ -32,6 +106,29 @@ type ExamAnswers struct {
 	Answers any `json:"answers" db:"answers"`
 	Student_ID int `json:"student_id" db:"student_id"`
 	Status     string    `json:"status" db:"status"`
+	Submitted_At *time.Time `json:"submitted_at" db:"submitted_at"`
+	Is_Late      bool       `json:"is_late" db:"is_late"`
+	Late_Seconds int        `json:"late_seconds" db:"late_seconds"`
+}
+
+// Exam answer statuses: drafts stay editable until they are submitted
+const (
+	AnswerStatusDraft     = "Active"
+	AnswerStatusSubmitted = "Submitted"
+)
+
+// AutosaveExamAnswerRequest patches a single answer into the student's draft
+type AutosaveExamAnswerRequest struct {
+	Exam_ID         int `json:"exam_id" binding:"required"`
+	Student_ID      int `json:"student_id" binding:"required"`
+	Question_Number int `json:"question_number" binding:"required,gt=0"`
+	Answer          any `json:"answer"`
+}
+
+// SubmitExamAnswersRequest freezes the student's draft and grades it
+type SubmitExamAnswersRequest struct {
+	Exam_ID    int `json:"exam_id" binding:"required"`
+	Student_ID int `json:"student_id" binding:"required"`
 }
 
 // CreateExamAnswersRequest represents the request body for creating an exam
@@ -56,6 +153,58 @@ type ExamGrades struct {
 	Student_ID       int `json:"student_id" db:"student_id"`
 	Score     float64    `json:"score" db:"score"`
 	Detail any `json:"detail" db:"detail"`
+	// Status is released once students may see the score; until then a student only sees submitted
+	Status string `json:"status" db:"-"`
+}
+
+// Score statuses shown to students
+const (
+	ScoreStatusSubmitted = "submitted"
+	ScoreStatusReleased  = "released"
+)
+
+// ExamReview shows a student's graded answers next to the correct answers and explanations
+type ExamReview struct {
+	Score_ID   int              `json:"score_id"`
+	Exam_ID    int              `json:"exam_id"`
+	Student_ID int              `json:"student_id"`
+	Score      float64          `json:"score"`
+	Questions  []ReviewQuestion `json:"questions"`
+}
+
+// ReviewQuestion is a question with its answer key and the student's result on it
+type ReviewQuestion struct {
+	Question
+	Answer        any     `json:"answer"`
+	Score         float64 `json:"score"`
+	Max_Score     float64 `json:"max_score"`
+	Correct       bool    `json:"correct"`
+	Pending       bool    `json:"pending,omitempty"`
+	Justification string  `json:"justification,omitempty"`
+	Comment       string  `json:"comment,omitempty"`
+	Feedback      string  `json:"feedback,omitempty"`
+}
+
+// Exam attempt statuses
+const (
+	AttemptInProgress = "In Progress"
+	AttemptFinalized  = "Finalized"
+)
+
+// ExamAttempt is a student's timed attempt at an exam
+type ExamAttempt struct {
+	ID           int        `json:"id" db:"id"`
+	Exam_ID      int        `json:"exam_id" db:"exam_id"`
+	Student_ID   int        `json:"student_id" db:"student_id"`
+	Started_At   time.Time  `json:"started_at" db:"started_at"`
+	Deadline     time.Time  `json:"deadline" db:"deadline"`
+	Finalized_At *time.Time `json:"finalized_at" db:"finalized_at"`
+	Status       string     `json:"status" db:"status"`
+}
+
+type StartExamAttemptRequest struct {
+	Exam_ID    int `json:"exam_id" binding:"required"`
+	Student_ID int `json:"student_id" binding:"required"`
 }
 
 type CalculateExamGrades struct {
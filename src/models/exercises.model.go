@@ -1,22 +1,40 @@
 package models
 
+import "time"
+
+// Score policies used to report a student's exercise grade across attempts
+const (
+	ScorePolicyHighest = "highest"
+	ScorePolicyLatest  = "latest"
+	ScorePolicyAverage = "average"
+)
+
 // Exercises represents the exercises model stored in the database
 type Exercises struct {
 	ID          int    `json:"id" db:"id"`
 	Material_ID  int    `json:"material_id" db:"material_id"`
 	Title       string `json:"title" db:"title"`
-	Content any `json:"content" db:"content"`
+	Content QuestionContent `json:"content" db:"content"`
 	Total_Marks     int    `json:"total_marks" db:"total_marks"`
 	Teacher_ID int `json:"teacher_id" db:"teacher_id"`
+	// Max_Attempts limits how many times a student can submit; 0 means unlimited
+	Max_Attempts int `json:"max_attempts" db:"max_attempts"`
+	Score_Policy string `json:"score_policy" db:"score_policy"`
 }
 
 // CreateExercisesRequest represents the request body for creating an exercise
 type CreateExercisesRequest struct {
 	Material_ID  int    `json:"material_id" db:"material_id"`
 	Title       string `json:"title" db:"title"`
-	Content any `json:"content" db:"content"`
+	Content QuestionContent `json:"content" db:"content"`
+	// Total_Marks must equal the sum of question weights, or 0 to derive it from them
 	Total_Marks     int    `json:"total_marks" db:"total_marks"`
 	Teacher_ID int `json:"teacher_id" db:"teacher_id"`
+	Max_Attempts int `json:"max_attempts" db:"max_attempts" binding:"gte=0"`
+	// Score_Policy is highest, latest or average; it defaults to latest
+	Score_Policy string `json:"score_policy" db:"score_policy" binding:"omitempty,oneof=highest latest average"`
+	// Bank_Question_IDs are copied from the question bank and appended after Content
+	Bank_Question_IDs []int `json:"bank_question_ids,omitempty" db:"-"`
 }
 
 type ExerciseAnswers struct {
@@ -49,6 +67,19 @@ type ExerciseGrades struct {
 	Student_ID       int `json:"student_id" db:"student_id"`
 	Score     float64    `json:"score" db:"score"`
 	Detail any `json:"detail" db:"detail"`
+	Attempt_ID *int `json:"attempt_id" db:"attempt_id"`
+}
+
+// ExerciseAttempt is one numbered submission of an exercise with a snapshot of its answers
+type ExerciseAttempt struct {
+	ID             int       `json:"id" db:"id"`
+	Exercise_ID    int       `json:"exercise_id" db:"exercise_id"`
+	Student_ID     int       `json:"student_id" db:"student_id"`
+	Attempt_Number int       `json:"attempt_number" db:"attempt_number"`
+	Answers        any       `json:"answers" db:"answers"`
+	Submitted_At   time.Time `json:"submitted_at" db:"submitted_at"`
+	Score          *float64  `json:"score" db:"score"`
+	Detail         any       `json:"detail" db:"detail"`
 }
 
 type CalculateExerciseGrades struct {
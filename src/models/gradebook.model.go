@@ -0,0 +1,62 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// GradebookSettings are the category weights of a class, in percent, and how many of the lowest
+// scores of each category are left out of the final grade
+type GradebookSettings struct {
+	Class_ID              int     `json:"class_id" db:"class_id"`
+	Exercise_Weight       float64 `json:"exercise_weight" db:"exercise_weight"`
+	Exam_Weight           float64 `json:"exam_weight" db:"exam_weight"`
+	Drop_Lowest_Exercises int     `json:"drop_lowest_exercises" db:"drop_lowest_exercises"`
+	Drop_Lowest_Exams     int     `json:"drop_lowest_exams" db:"drop_lowest_exams"`
+	// KKM is the minimum mastery criterion, the passing percentage of the class's exams
+	KKM float64 `json:"kkm" db:"kkm"`
+}
+
+// Gradebook settings used by classes that have not set their own
+const (
+	DefaultExerciseWeight = 30
+	DefaultExamWeight     = 70
+	DefaultKKM            = 75
+)
+
+// UpdateGradebookSettingsRequest sets the gradebook of a class; the weights must add up to 100
+type UpdateGradebookSettingsRequest struct {
+	Class_ID              int     `json:"class_id" binding:"required"`
+	Exercise_Weight       float64 `json:"exercise_weight" binding:"gte=0,lte=100"`
+	Exam_Weight           float64 `json:"exam_weight" binding:"gte=0,lte=100"`
+	Drop_Lowest_Exercises int     `json:"drop_lowest_exercises" binding:"gte=0"`
+	Drop_Lowest_Exams     int     `json:"drop_lowest_exams" binding:"gte=0"`
+	// KKM keeps its current value when left empty
+	KKM *float64 `json:"kkm" binding:"omitempty,gte=0,lte=100"`
+}
+
+// Validate checks that the weights add up to 100
+func (r UpdateGradebookSettingsRequest) Validate() error {
+	if r.Exercise_Weight+r.Exam_Weight != 100 {
+		return errors.New("exercise_weight and exam_weight must add up to 100")
+	}
+	return nil
+}
+
+// FinalGrade is a student's running grade in a class. Averages are percentages and stay empty
+// until the student has a score in that category.
+type FinalGrade struct {
+	Class_ID         int       `json:"class_id" db:"class_id"`
+	Student_ID       int       `json:"student_id" db:"student_id"`
+	Name             string    `json:"name" db:"name"`
+	Exercise_Average *float64  `json:"exercise_average" db:"exercise_average"`
+	Exam_Average     *float64  `json:"exam_average" db:"exam_average"`
+	Final_Grade      *float64  `json:"final_grade" db:"final_grade"`
+	Updated_At       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Gradebook is the settings of a class with the final grade of every student
+type Gradebook struct {
+	Settings GradebookSettings `json:"settings"`
+	Students []FinalGrade      `json:"students"`
+}
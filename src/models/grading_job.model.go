@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Grading job statuses
+const (
+	GradingJobQueued    = "Queued"
+	GradingJobRunning   = "Running"
+	GradingJobCompleted = "Completed"
+	GradingJobFailed    = "Failed"
+)
+
+// Grading job item statuses
+const (
+	GradingItemPending = "Pending"
+	GradingItemRunning = "Running"
+	GradingItemDone    = "Done"
+	GradingItemFailed  = "Failed"
+)
+
+// GradingJob is a queued exercise grade, or the queued essays of an exam score.
+// Each essay is graded as a separate item, the grade is saved once every item is done.
+type GradingJob struct {
+	ID            int             `json:"id" db:"id"`
+	Exercise_ID   *int            `json:"exercise_id" db:"exercise_id"`
+	Exam_Score_ID *int            `json:"exam_score_id" db:"exam_score_id"`
+	Student_ID    int             `json:"student_id" db:"student_id"`
+	Status        string          `json:"status" db:"status"`
+	Error         *string         `json:"error" db:"error"`
+	Total_Items   int             `json:"total_items"`
+	Done_Items    int             `json:"done_items"`
+	Failed_Items  int             `json:"failed_items"`
+	Created_At    time.Time       `json:"created_at" db:"created_at"`
+	Completed_At  *time.Time      `json:"completed_at" db:"completed_at"`
+	Grade         *ExerciseGrades `json:"grade,omitempty"`
+	Exam_Grade    *ExamGrades     `json:"exam_grade,omitempty"`
+}
@@ -0,0 +1,44 @@
+package models
+
+// ItemAnalysis holds the classical item statistics of every question in an exam or exercise
+type ItemAnalysis struct {
+	// Students is how many graded attempts were analysed, one per student
+	Students  int              `json:"students"`
+	Questions []ItemStatistics `json:"questions"`
+}
+
+// ItemStatistics describes how students did on one question
+type ItemStatistics struct {
+	Number int    `json:"number"`
+	Type   string `json:"type"`
+	Prompt string `json:"prompt"`
+	// Responses counts the answered questions and Omitted the unanswered ones, which score as wrong.
+	// Essays still waiting to be graded are left out of every statistic.
+	Responses int `json:"responses"`
+	Omitted   int `json:"omitted"`
+	// Difficulty is the average share of the points earned, which is the share of students correct
+	// on questions scored right or wrong. Low values mark hard questions.
+	Difficulty *float64 `json:"difficulty"`
+	// Discrimination is the difficulty among the upper 27% of students by total score
+	// minus the difficulty among the lower 27%. Values near or below 0 mark questions worth reviewing.
+	Discrimination *float64 `json:"discrimination"`
+	Average_Score  *float64 `json:"average_score"`
+	Max_Score      float64  `json:"max_score"`
+	// Distractors counts how often each option of a multiple choice question was picked
+	Distractors []OptionFrequency `json:"distractors,omitempty"`
+}
+
+// OptionFrequency is how often students picked one option
+type OptionFrequency struct {
+	Key     string  `json:"key"`
+	Text    string  `json:"text"`
+	Correct bool    `json:"correct"`
+	Count   int     `json:"count"`
+	Share   float64 `json:"share"`
+}
+
+// GradedDetail is one student's total score with its per-question detail
+type GradedDetail struct {
+	Score  float64
+	Detail map[string]QuestionResult
+}
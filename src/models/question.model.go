@@ -0,0 +1,377 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// QuestionSchemaVersion is the current version of the exam and exercise content schema
+const QuestionSchemaVersion = 1
+
+// Supported question types
+const (
+	QuestionTypeMultipleChoice = "multiple_choice"
+	QuestionTypeEssay          = "essay"
+	QuestionTypeMultiSelect    = "multi_select"
+	QuestionTypeNumeric        = "numeric"
+	QuestionTypeTrueFalse      = "true_false"
+	QuestionTypeMatching       = "matching"
+	QuestionTypeFillBlank      = "fill_blank"
+)
+
+// Tolerance types of numeric questions
+const (
+	ToleranceAbsolute = "absolute"
+	ToleranceRelative = "relative"
+)
+
+// QuestionContent is the versioned content stored in exams.content and exercises.content
+type QuestionContent struct {
+	Version   int        `json:"version"`
+	Questions []Question `json:"questions"`
+}
+
+// Question represents a single exam or exercise question
+type Question struct {
+	Number         int              `json:"number"`
+	Type           string           `json:"type"`
+	Prompt         string           `json:"prompt"`
+	Options        []QuestionOption `json:"options,omitempty"`
+	Correct_Answer string           `json:"correct_answer,omitempty"`
+	// Weight is the number of points the question is worth
+	Weight      float64 `json:"weight"`
+	Explanation string  `json:"explanation,omitempty"`
+	// Partial_Credit allows multi select, matching and fill blank answers to earn part of the points.
+	// It defaults to true for those types.
+	Partial_Credit *bool `json:"partial_credit,omitempty"`
+
+	// Multi select: keys of every correct option
+	Correct_Answers []string `json:"correct_answers,omitempty"`
+	// Numeric: expected value and the accepted distance from it
+	Numeric_Answer *float64 `json:"numeric_answer,omitempty"`
+	Tolerance      float64  `json:"tolerance,omitempty"`
+	Tolerance_Type string   `json:"tolerance_type,omitempty"`
+	// Matching: each left item and the right item it belongs to
+	Pairs []MatchingPair `json:"pairs,omitempty"`
+	// Matching items shown to students instead of Pairs
+	Left_Items  []string `json:"left_items,omitempty"`
+	Right_Items []string `json:"right_items,omitempty"`
+	// Fill blank: accepted answers for each blank, in the order the blanks appear in the prompt
+	Blanks []Blank `json:"blanks,omitempty"`
+	// Essay: key terms a good answer mentions. Correct_Answer may hold a reference answer.
+	Keywords []string `json:"keywords,omitempty"`
+	// Essay: criteria the answer is graded on, e.g. content 40%, argument 30% and language 30%
+	Rubric []RubricCriterion `json:"rubric,omitempty"`
+
+	// Bank_Question_ID is the bank question this question was copied from.
+	// It stays with the question when questions are renumbered.
+	Bank_Question_ID *int `json:"bank_question_id,omitempty"`
+}
+
+// QuestionOption represents a selectable option of a multiple choice question
+type QuestionOption struct {
+	Key  string `json:"key"`
+	Text string `json:"text"`
+}
+
+// MatchingPair is one correct pair of a matching question
+type MatchingPair struct {
+	Left  string `json:"left"`
+	Right string `json:"right"`
+}
+
+// Blank holds the accepted answers of one blank in a fill blank question
+type Blank struct {
+	Accepted       []string `json:"accepted,omitempty"`
+	Case_Sensitive bool     `json:"case_sensitive,omitempty"`
+}
+
+// RubricCriterion is one part of an essay rubric. Weight is its share of the question points in percent.
+type RubricCriterion struct {
+	Name   string        `json:"name"`
+	Weight float64       `json:"weight"`
+	Levels []RubricLevel `json:"levels,omitempty"`
+}
+
+// RubricLevel describes what an answer at this level looks like. Credit is the share of the
+// criterion points it earns, from 0 to 1.
+type RubricLevel struct {
+	Label       string  `json:"label"`
+	Description string  `json:"description"`
+	Credit      float64 `json:"credit"`
+}
+
+// CriterionScore is the score an essay earned on one rubric criterion
+type CriterionScore struct {
+	Name          string  `json:"name"`
+	Score         float64 `json:"score"`
+	Max_Score     float64 `json:"max_score"`
+	Level         string  `json:"level,omitempty"`
+	Justification string  `json:"justification,omitempty"`
+}
+
+// QuestionResult is the per-question detail stored in exam_scores.detail and exercise_scores.detail
+type QuestionResult struct {
+	Type      string  `json:"type"`
+	Score     float64 `json:"score"`
+	Max_Score float64 `json:"max_score"`
+	Correct   bool    `json:"correct"`
+	Answer    any     `json:"answer"`
+	// Essays: why the grader gave the score and, with a rubric, the score of each criterion
+	Justification string           `json:"justification,omitempty"`
+	Criteria      []CriterionScore `json:"criteria,omitempty"`
+	// Set when a teacher overrides the score: the score given by the grader and the teacher's comment
+	Auto_Score *float64 `json:"auto_score,omitempty"`
+	Comment    string   `json:"comment,omitempty"`
+	// Pending essays wait for a teacher or the grading queue and score 0 until graded
+	Pending bool `json:"pending,omitempty"`
+	// Exam essays: the grader's feedback to the student, shown once the score is released
+	Feedback string `json:"feedback,omitempty"`
+}
+
+// Key returns the key used for the question in student answers, e.g. "1"
+func (q Question) Key() string {
+	return strconv.Itoa(q.Number)
+}
+
+// Only returns the content limited to the question with the given number
+func (c QuestionContent) Only(number int) QuestionContent {
+	filtered := QuestionContent{Version: c.Version, Questions: []Question{}}
+	for _, q := range c.Questions {
+		if q.Number == number {
+			filtered.Questions = append(filtered.Questions, q)
+		}
+	}
+	return filtered
+}
+
+// Normalize fills in defaults for fields the client may omit
+func (c *QuestionContent) Normalize() {
+	if c.Version == 0 {
+		c.Version = QuestionSchemaVersion
+	}
+	for i := range c.Questions {
+		c.Questions[i].Normalize()
+	}
+}
+
+// Normalize fills in defaults for question fields the client may omit
+func (q *Question) Normalize() {
+	if q.Weight == 0 {
+		q.Weight = 1
+	}
+	if q.Type == QuestionTypeNumeric && q.Tolerance_Type == "" {
+		q.Tolerance_Type = ToleranceAbsolute
+	}
+}
+
+// AllowsPartialCredit reports whether the question type can earn part of its points
+func (q Question) AllowsPartialCredit() bool {
+	return q.Type == QuestionTypeMultiSelect || q.Type == QuestionTypeMatching || q.Type == QuestionTypeFillBlank
+}
+
+// PartialCreditEnabled reports whether the question gives partial credit, defaulting to true for types that allow it
+func (q Question) PartialCreditEnabled() bool {
+	if q.Partial_Credit != nil {
+		return *q.Partial_Credit && q.AllowsPartialCredit()
+	}
+	return q.AllowsPartialCredit()
+}
+
+// TotalPoints returns the sum of the question weights
+func (c QuestionContent) TotalPoints() float64 {
+	var total float64
+	for _, q := range c.Questions {
+		total += q.Weight
+	}
+	return total
+}
+
+// ResolveTotalMarks derives total marks from the question weights when totalMarks is 0,
+// otherwise it checks that totalMarks equals the sum of the weights
+func (c QuestionContent) ResolveTotalMarks(totalMarks int) (int, error) {
+	total := c.TotalPoints()
+	if totalMarks == 0 {
+		totalMarks = int(math.Round(total))
+	}
+	if math.Abs(float64(totalMarks)-total) > 1e-9 {
+		return 0, fmt.Errorf("total_marks (%d) must equal the sum of question weights (%g)", totalMarks, total)
+	}
+	return totalMarks, nil
+}
+
+// Validate checks that the content follows the question schema
+func (c QuestionContent) Validate() error {
+	if c.Version != QuestionSchemaVersion {
+		return fmt.Errorf("unsupported content version %d", c.Version)
+	}
+	if len(c.Questions) == 0 {
+		return errors.New("content must have at least one question")
+	}
+
+	numbers := make(map[int]bool, len(c.Questions))
+	for _, q := range c.Questions {
+		if q.Number <= 0 {
+			return errors.New("question number must be a positive integer")
+		}
+		if numbers[q.Number] {
+			return fmt.Errorf("question %d: duplicate question number", q.Number)
+		}
+		numbers[q.Number] = true
+
+		if err := q.Validate(); err != nil {
+			return fmt.Errorf("question %d: %w", q.Number, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks a single question against the rules of its type
+func (q Question) Validate() error {
+	if q.Prompt == "" {
+		return errors.New("prompt is required")
+	}
+	if q.Weight <= 0 {
+		return errors.New("weight must be greater than zero")
+	}
+	if q.Partial_Credit != nil && *q.Partial_Credit && !q.AllowsPartialCredit() {
+		return fmt.Errorf("partial credit is not supported for %s questions", q.Type)
+	}
+
+	switch q.Type {
+	case QuestionTypeMultipleChoice:
+		keys, err := q.validateOptions()
+		if err != nil {
+			return err
+		}
+		if !keys[q.Correct_Answer] {
+			return errors.New("correct_answer must be one of the option keys")
+		}
+	case QuestionTypeMultiSelect:
+		keys, err := q.validateOptions()
+		if err != nil {
+			return err
+		}
+		if len(q.Correct_Answers) == 0 {
+			return errors.New("multi select needs at least one correct answer")
+		}
+		seen := make(map[string]bool, len(q.Correct_Answers))
+		for _, key := range q.Correct_Answers {
+			if !keys[key] {
+				return fmt.Errorf("correct answer %q is not an option key", key)
+			}
+			if seen[key] {
+				return fmt.Errorf("duplicate correct answer %q", key)
+			}
+			seen[key] = true
+		}
+	case QuestionTypeNumeric:
+		if q.Numeric_Answer == nil {
+			return errors.New("numeric questions need a numeric_answer")
+		}
+		if q.Tolerance < 0 {
+			return errors.New("tolerance cannot be negative")
+		}
+		if q.Tolerance_Type != ToleranceAbsolute && q.Tolerance_Type != ToleranceRelative {
+			return fmt.Errorf("unknown tolerance type %q", q.Tolerance_Type)
+		}
+	case QuestionTypeTrueFalse:
+		if q.Correct_Answer != "true" && q.Correct_Answer != "false" {
+			return errors.New(`correct_answer must be "true" or "false"`)
+		}
+	case QuestionTypeMatching:
+		if len(q.Pairs) < 2 {
+			return errors.New("matching needs at least two pairs")
+		}
+		lefts := make(map[string]bool, len(q.Pairs))
+		rights := make(map[string]bool, len(q.Pairs))
+		for _, pair := range q.Pairs {
+			if pair.Left == "" || pair.Right == "" {
+				return errors.New("every pair needs a left and a right item")
+			}
+			if lefts[pair.Left] || rights[pair.Right] {
+				return errors.New("pair items must be unique")
+			}
+			lefts[pair.Left] = true
+			rights[pair.Right] = true
+		}
+	case QuestionTypeFillBlank:
+		if len(q.Blanks) == 0 {
+			return errors.New("fill blank needs at least one blank")
+		}
+		for i, blank := range q.Blanks {
+			if len(blank.Accepted) == 0 {
+				return fmt.Errorf("blank %d needs at least one accepted answer", i+1)
+			}
+			for _, accepted := range blank.Accepted {
+				if accepted == "" {
+					return fmt.Errorf("blank %d has an empty accepted answer", i+1)
+				}
+			}
+		}
+	case QuestionTypeEssay:
+		if len(q.Options) > 0 {
+			return errors.New("essay questions cannot have options")
+		}
+		if err := validateRubric(q.Rubric); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown question type %q", q.Type)
+	}
+	return nil
+}
+
+// validateRubric checks that criteria are named uniquely and their weights add up to 100 percent
+func validateRubric(rubric []RubricCriterion) error {
+	if len(rubric) == 0 {
+		return nil
+	}
+	names := make(map[string]bool, len(rubric))
+	var total float64
+	for _, criterion := range rubric {
+		if criterion.Name == "" {
+			return errors.New("every rubric criterion needs a name")
+		}
+		if names[criterion.Name] {
+			return fmt.Errorf("duplicate rubric criterion %q", criterion.Name)
+		}
+		names[criterion.Name] = true
+		if criterion.Weight <= 0 {
+			return fmt.Errorf("rubric criterion %q needs a weight greater than zero", criterion.Name)
+		}
+		total += criterion.Weight
+		for _, level := range criterion.Levels {
+			if level.Label == "" {
+				return fmt.Errorf("rubric criterion %q has a level without a label", criterion.Name)
+			}
+			if level.Credit < 0 || level.Credit > 1 {
+				return fmt.Errorf("rubric level %q credit must be between 0 and 1", level.Label)
+			}
+		}
+	}
+	if math.Abs(total-100) > 1e-6 {
+		return fmt.Errorf("rubric weights must add up to 100, got %g", total)
+	}
+	return nil
+}
+
+// validateOptions checks the options of a choice question and returns the set of option keys
+func (q Question) validateOptions() (map[string]bool, error) {
+	if len(q.Options) < 2 {
+		return nil, errors.New("choice questions need at least two options")
+	}
+	keys := make(map[string]bool, len(q.Options))
+	for _, option := range q.Options {
+		if option.Key == "" || option.Text == "" {
+			return nil, errors.New("every option needs a key and a text")
+		}
+		if keys[option.Key] {
+			return nil, fmt.Errorf("duplicate option key %q", option.Key)
+		}
+		keys[option.Key] = true
+	}
+	return keys, nil
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Supported question bank difficulties
+const (
+	DifficultyEasy   = "easy"
+	DifficultyMedium = "medium"
+	DifficultyHard   = "hard"
+)
+
+// BankQuestion represents a reusable question stored in the question bank
+type BankQuestion struct {
+	ID         int       `json:"id" db:"id"`
+	Teacher_ID int       `json:"teacher_id" db:"teacher_id"`
+	Subject    string    `json:"subject" db:"subject"`
+	Grade      int       `json:"grade" db:"grade"`
+	Topic      string    `json:"topic" db:"topic"`
+	Difficulty string    `json:"difficulty" db:"difficulty"`
+	Tags       []string  `json:"tags" db:"tags"`
+	Question   Question  `json:"question" db:"question"`
+	Created_At time.Time `json:"created_at" db:"created_at"`
+	Updated_At time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateBankQuestionRequest represents the request body for creating a bank question.
+// The question number is assigned when the question is used in an exam or exercise.
+type CreateBankQuestionRequest struct {
+	Teacher_ID int      `json:"teacher_id" binding:"required"`
+	Subject    string   `json:"subject" binding:"required"`
+	Grade      int      `json:"grade" binding:"required"`
+	Topic      string   `json:"topic" binding:"required"`
+	Difficulty string   `json:"difficulty" binding:"required,oneof=easy medium hard"`
+	Tags       []string `json:"tags"`
+	Question   Question `json:"question"`
+}
+
+// BankQuestionFilter holds the optional filters of the question bank listing
+type BankQuestionFilter struct {
+	Subject    string
+	Grade      int
+	Topic      string
+	Difficulty string
+	Tag        string
+}
+
+// BankQuestionUsage records an exam or exercise a bank question has appeared in
+type BankQuestionUsage struct {
+	ID              int       `json:"id" db:"id"`
+	Question_ID     int       `json:"question_id" db:"question_id"`
+	Exam_ID         *int      `json:"exam_id" db:"exam_id"`
+	Exercise_ID     *int      `json:"exercise_id" db:"exercise_id"`
+	Title           string    `json:"title" db:"title"`
+	Question_Number int       `json:"question_number" db:"question_number"`
+	Created_At      time.Time `json:"created_at" db:"created_at"`
+}
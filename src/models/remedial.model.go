@@ -0,0 +1,8 @@
+package models
+
+// BelowKKM lists the students whose latest score on an exam is below its KKM
+type BelowKKM struct {
+	Exam_ID  int            `json:"exam_id"`
+	KKM      float64        `json:"kkm"`
+	Students []StudentScore `json:"students"`
+}
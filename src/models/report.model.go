@@ -0,0 +1,81 @@
+package models
+
+// ScoreStatistics summarises a set of scores given as percentages of the total marks
+type ScoreStatistics struct {
+	Count     int               `json:"count"`
+	Mean      float64           `json:"mean"`
+	Median    float64           `json:"median"`
+	Std_Dev   float64           `json:"std_dev"`
+	Min       float64           `json:"min"`
+	Max       float64           `json:"max"`
+	Pass_Rate float64           `json:"pass_rate"`
+	Histogram []HistogramBucket `json:"histogram"`
+}
+
+// HistogramBucket counts the scores from From up to but not including To; the last bucket includes 100
+type HistogramBucket struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Count int     `json:"count"`
+}
+
+// ExamReport is the score distribution of one exam, using each student's latest score
+type ExamReport struct {
+	Exam_ID       int             `json:"exam_id"`
+	Title         string          `json:"title"`
+	Class_ID      int             `json:"class_id"`
+	Total_Marks   int             `json:"total_marks"`
+	Passing_Score float64         `json:"passing_score"`
+	Statistics    ScoreStatistics `json:"statistics"`
+	Students      []StudentScore  `json:"students"`
+}
+
+// StudentScore is one student's score on an exam
+type StudentScore struct {
+	Student_ID int     `json:"student_id"`
+	Name       string  `json:"name"`
+	NIS        string  `json:"nis"`
+	Score      float64 `json:"score"`
+	Percent    float64 `json:"percent"`
+	Passed     bool    `json:"passed"`
+}
+
+// ClassReport is the performance of a class over all its exams, compared with the classes of the same grade
+type ClassReport struct {
+	Class_ID      int     `json:"class_id"`
+	Name          string  `json:"name"`
+	Grade         int     `json:"grade"`
+	Passing_Score float64 `json:"passing_score"`
+	// Statistics are computed over each student's average percentage across the exams they took
+	Statistics ScoreStatistics   `json:"statistics"`
+	Exams      []ExamSummary     `json:"exams"`
+	Students   []StudentAverage  `json:"students"`
+	Comparison []ClassComparison `json:"comparison"`
+}
+
+// ExamSummary is the score distribution of one exam within a class report
+type ExamSummary struct {
+	Exam_ID    int             `json:"exam_id"`
+	Title      string          `json:"title"`
+	Statistics ScoreStatistics `json:"statistics"`
+}
+
+// StudentAverage is one student's average percentage across the exams of a class
+type StudentAverage struct {
+	Student_ID  int     `json:"student_id"`
+	Name        string  `json:"name"`
+	NIS         string  `json:"nis"`
+	Exams_Taken int     `json:"exams_taken"`
+	Average     float64 `json:"average"`
+	Passed      bool    `json:"passed"`
+}
+
+// ClassComparison compares a class of the same grade on its students' average percentages
+type ClassComparison struct {
+	Class_ID  int     `json:"class_id"`
+	Name      string  `json:"name"`
+	Students  int     `json:"students"`
+	Mean      float64 `json:"mean"`
+	Median    float64 `json:"median"`
+	Pass_Rate float64 `json:"pass_rate"`
+}
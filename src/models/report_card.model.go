@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// ReportCard is a student's report card: their profile, the running final grade of every class they are
+// assigned to when the card is generated, and their attendance and the teacher's remarks for the semester.
+// Classes are not kept per semester, so the semester only applies to the attendance and remarks.
+type ReportCard struct {
+	Student      Student            `json:"student"`
+	Semester     string             `json:"semester"`
+	Classes      []ReportCardClass  `json:"classes"`
+	Attendance   *AttendanceSummary `json:"attendance"`
+	Remarks      string             `json:"remarks"`
+	Generated_At time.Time          `json:"generated_at"`
+}
+
+// ReportCardClass is one class on a report card. Grades are percentages and stay empty
+// until the student has a score in the class.
+type ReportCardClass struct {
+	Class_ID         int      `json:"class_id" db:"class_id"`
+	Name             string   `json:"name" db:"name"`
+	Teacher_Name     string   `json:"teacher_name" db:"teacher_name"`
+	Exercise_Average *float64 `json:"exercise_average" db:"exercise_average"`
+	Exam_Average     *float64 `json:"exam_average" db:"exam_average"`
+	Final_Grade      *float64 `json:"final_grade" db:"final_grade"`
+	KKM              float64  `json:"kkm" db:"kkm"`
+	Passed           bool     `json:"passed"`
+}
+
+// AttendanceSummary counts the days a student missed in a semester
+type AttendanceSummary struct {
+	Sick_Days      int `json:"sick_days"`
+	Permitted_Days int `json:"permitted_days"`
+	Absent_Days    int `json:"absent_days"`
+}
+
+// ReportCardNote is what the teacher writes on a student's report card for a semester.
+// Attendance stays empty until it is recorded. Written_By is the user who wrote the remarks;
+// only they or an admin may change them.
+type ReportCardNote struct {
+	ID             int       `json:"id" db:"id"`
+	Student_ID     int       `json:"student_id" db:"student_id"`
+	Semester       string    `json:"semester" db:"semester"`
+	Remarks        string    `json:"remarks" db:"remarks"`
+	Sick_Days      *int      `json:"sick_days" db:"sick_days"`
+	Permitted_Days *int      `json:"permitted_days" db:"permitted_days"`
+	Absent_Days    *int      `json:"absent_days" db:"absent_days"`
+	Written_By     *int      `json:"written_by" db:"written_by"`
+	Updated_At     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// UpdateReportCardNoteRequest sets the remarks and attendance on a student's report card.
+// Fields left out keep their saved value.
+type UpdateReportCardNoteRequest struct {
+	Student_ID     int     `json:"student_id" binding:"required"`
+	Semester       string  `json:"semester" binding:"required,max=20"`
+	Remarks        *string `json:"remarks"`
+	Sick_Days      *int    `json:"sick_days" binding:"omitempty,gte=0"`
+	Permitted_Days *int    `json:"permitted_days" binding:"omitempty,gte=0"`
+	Absent_Days    *int    `json:"absent_days" binding:"omitempty,gte=0"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// RegradePreview shows how stored scores would change after an answer key or weight change
+type RegradePreview struct {
+	Changed_Questions []int         `json:"changed_questions"`
+	Total_Scores      int           `json:"total_scores"`
+	Affected_Scores   int           `json:"affected_scores"`
+	Changes           []ScoreChange `json:"changes"`
+}
+
+// ScoreChange is the old and new total of one regraded score
+type ScoreChange struct {
+	Score_ID   int     `json:"score_id"`
+	Student_ID int     `json:"student_id"`
+	Old_Score  float64 `json:"old_score"`
+	New_Score  float64 `json:"new_score"`
+}
+
+// ScoreHistory is a previous version of a score, kept when the score was regraded
+type ScoreHistory struct {
+	ID         int       `json:"id" db:"id"`
+	Score_ID   int       `json:"score_id" db:"score_id"`
+	Old_Score  float64   `json:"old_score" db:"old_score"`
+	New_Score  float64   `json:"new_score" db:"new_score"`
+	Old_Detail any       `json:"old_detail" db:"old_detail"`
+	Reason     string    `json:"reason" db:"reason"`
+	Changed_By *int      `json:"changed_by" db:"changed_by"`
+	Created_At time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// OverrideScoreRequest replaces the score of one question in a graded exam or exercise and recomputes the total.
+// Leave Score empty to only change the comment.
+type OverrideScoreRequest struct {
+	Score_ID     int      `json:"score_id" binding:"required"`
+	Question_Key string   `json:"question_key" binding:"required"`
+	Score        *float64 `json:"score" binding:"omitempty,gte=0"`
+	Comment      string   `json:"comment"`
+}
+
+// ScoreOverride is one audited change to a question score made by a teacher
+type ScoreOverride struct {
+	ID             int       `json:"id" db:"id"`
+	Score_ID       int       `json:"score_id" db:"score_id"`
+	Question_Key   string    `json:"question_key" db:"question_key"`
+	Auto_Score     float64   `json:"auto_score" db:"auto_score"`
+	Previous_Score float64   `json:"previous_score" db:"previous_score"`
+	New_Score      float64   `json:"new_score" db:"new_score"`
+	Comment        string    `json:"comment" db:"comment"`
+	Overridden_By  *int      `json:"overridden_by" db:"overridden_by"`
+	Created_At     time.Time `json:"created_at" db:"created_at"`
+}
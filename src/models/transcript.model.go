@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// TranscriptSchemaVersion changes whenever a field of the transcript is renamed or removed,
+// so schools importing transcripts can tell the layouts apart
+const TranscriptSchemaVersion = "1"
+
+// Transcript is a student's full academic record: every class they were assigned to with all
+// their exercise and exam scores and final grades. Grades and percents are percentages.
+type Transcript struct {
+	Schema_Version     string            `json:"schema_version"`
+	Generated_At       time.Time         `json:"generated_at"`
+	Student            TranscriptStudent `json:"student"`
+	Cumulative_Average *float64          `json:"cumulative_average"`
+	Classes            []TranscriptClass `json:"classes"`
+}
+
+// TranscriptStudent is the part of the student profile printed on a transcript
+type TranscriptStudent struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	NIS    string `json:"nis"`
+	Grade  int    `json:"grade"`
+	Status string `json:"status"`
+}
+
+// TranscriptClass is one class on a transcript. Averages and the final grade stay empty
+// until the student has a score in the class.
+type TranscriptClass struct {
+	Class_ID         int               `json:"class_id"`
+	Name             string            `json:"name"`
+	Teacher_Name     string            `json:"teacher_name"`
+	Exercise_Average *float64          `json:"exercise_average"`
+	Exam_Average     *float64          `json:"exam_average"`
+	Final_Grade      *float64          `json:"final_grade"`
+	KKM              float64           `json:"kkm"`
+	Passed           bool              `json:"passed"`
+	Exercises        []TranscriptScore `json:"exercises"`
+	Exams            []TranscriptScore `json:"exams"`
+}
+
+// TranscriptScore is one graded exercise attempt, or the score that counts for an exam. Score and percent
+// stay empty while essays are still being graded or the exam's scores are not released yet. Attempt is only
+// set on exercises and Remedial_Of only on remedial exams. Pre_Remedial_Score is the exam score before
+// a remedial exam replaced it.
+type TranscriptScore struct {
+	Score_ID           int        `json:"score_id"`
+	Item_ID            int        `json:"item_id"`
+	Title              string     `json:"title"`
+	Attempt            *int       `json:"attempt"`
+	Remedial_Of        *int       `json:"remedial_of"`
+	Score              *float64   `json:"score"`
+	Pre_Remedial_Score *float64   `json:"pre_remedial_score"`
+	Total_Marks        int        `json:"total_marks"`
+	Percent            *float64   `json:"percent"`
+	Recorded_At        *time.Time `json:"recorded_at"`
+}
@@ -17,3 +17,36 @@ type CreateUserRequest struct {
 	Password string `json:"password" binding:"required"`
 	Role     string `json:"role" binding:"required"`
 }
+
+// AuthTokens represents the access and refresh token pair returned by login and refresh
+type AuthTokens struct {
+	Token         string `json:"token"`
+	Refresh_Token string `json:"refresh_token"`
+	Expires_In    int    `json:"expires_in"`
+}
+
+// Principal represents the authenticated caller taken from the JWT claims.
+// Student_ID and Teacher_ID are zero when the caller does not have that role.
+type Principal struct {
+	User_ID      int    `json:"user_id"`
+	Role         string `json:"role"`
+	Display_Name string `json:"display_name"`
+	Student_ID   int    `json:"student_id"`
+	Teacher_ID   int    `json:"teacher_id"`
+	Session_ID   int    `json:"session_id"`
+}
+
+// IsAdmin reports whether the principal is an admin
+func (p Principal) IsAdmin() bool {
+	return p.Role == "admin"
+}
+
+// IsTeacher reports whether the principal is a teacher
+func (p Principal) IsTeacher() bool {
+	return p.Role == "teacher" && p.Teacher_ID > 0
+}
+
+// IsStudent reports whether the principal is a student
+func (p Principal) IsStudent() bool {
+	return p.Role == "student" && p.Student_ID > 0
+}
@@ -0,0 +1,123 @@
+package repo
+
+import (
+	"context"
+	"project-ppl-be/config"
+)
+
+// AccessRepository answers ownership questions used for authorization
+type AccessRepository struct{}
+
+// TeacherOwnsClass reports whether the teacher teaches the class
+func (r *AccessRepository) TeacherOwnsClass(ctx context.Context, teacherID, classID int) (bool, error) {
+	return r.exists(ctx, `SELECT 1 FROM classes WHERE id = $1 AND teacher_id = $2`, classID, teacherID)
+}
+
+// StudentInClass reports whether the student is assigned to the class
+func (r *AccessRepository) StudentInClass(ctx context.Context, studentID, classID int) (bool, error) {
+	return r.exists(ctx, `SELECT 1 FROM assigned_students_class WHERE class_id = $1 AND student_id = $2`, classID, studentID)
+}
+
+// TeacherTeachesStudent reports whether the student is assigned to any class the teacher teaches
+func (r *AccessRepository) TeacherTeachesStudent(ctx context.Context, teacherID, studentID int) (bool, error) {
+	return r.exists(ctx, `
+		SELECT 1
+		FROM assigned_students_class asc_tbl
+		JOIN classes c ON c.id = asc_tbl.class_id
+		WHERE c.teacher_id = $1 AND asc_tbl.student_id = $2
+	`, teacherID, studentID)
+}
+
+// StudentMayTakeExam reports whether the student belongs to the exam's class and, for remedial exams,
+// is assigned to the exam; regular exams are open to the whole class
+func (r *AccessRepository) StudentMayTakeExam(ctx context.Context, studentID, examID int) (bool, error) {
+	return r.exists(ctx, `
+		SELECT 1
+		FROM exams e
+		JOIN assigned_students_class asc_tbl ON asc_tbl.class_id = e.class_id AND asc_tbl.student_id = $2
+		WHERE e.id = $1 AND (
+			e.remedial_of IS NULL
+			OR EXISTS (SELECT 1 FROM exam_participants p WHERE p.exam_id = e.id AND p.student_id = $2)
+		)
+	`, examID, studentID)
+}
+
+// ClassIDOfMaterial returns the class a material belongs to
+func (r *AccessRepository) ClassIDOfMaterial(ctx context.Context, materialID int) (int, error) {
+	return r.scanID(ctx, `SELECT class_id FROM materials WHERE id = $1`, materialID)
+}
+
+// ClassIDOfExam returns the class an exam belongs to
+func (r *AccessRepository) ClassIDOfExam(ctx context.Context, examID int) (int, error) {
+	return r.scanID(ctx, `SELECT class_id FROM exams WHERE id = $1`, examID)
+}
+
+// ClassIDOfExercise returns the class an exercise belongs to through its material
+func (r *AccessRepository) ClassIDOfExercise(ctx context.Context, exerciseID int) (int, error) {
+	return r.scanID(ctx, `
+		SELECT m.class_id
+		FROM exercises e
+		JOIN materials m ON m.id = e.material_id
+		WHERE e.id = $1
+	`, exerciseID)
+}
+
+// ExamIDOfScore returns the exam an exam score belongs to
+func (r *AccessRepository) ExamIDOfScore(ctx context.Context, scoreID int) (int, error) {
+	return r.scanID(ctx, `SELECT exam_id FROM exam_scores WHERE id = $1`, scoreID)
+}
+
+// ExerciseIDOfScore returns the exercise an exercise score belongs to
+func (r *AccessRepository) ExerciseIDOfScore(ctx context.Context, scoreID int) (int, error) {
+	return r.scanID(ctx, `SELECT exercise_id FROM exercise_scores WHERE id = $1`, scoreID)
+}
+
+// StudentIDOfExamAnswer returns the owner of an exam answer row
+func (r *AccessRepository) StudentIDOfExamAnswer(ctx context.Context, answerID int) (int, error) {
+	return r.scanID(ctx, `SELECT student_id FROM exam_answers WHERE id = $1`, answerID)
+}
+
+// StudentIDOfExerciseAnswer returns the owner of an exercise answer row
+func (r *AccessRepository) StudentIDOfExerciseAnswer(ctx context.Context, answerID int) (int, error) {
+	return r.scanID(ctx, `SELECT student_id FROM exercise_answers WHERE id = $1`, answerID)
+}
+
+// ExamIDOfExamAnswer returns the exam an exam answer row belongs to
+func (r *AccessRepository) ExamIDOfExamAnswer(ctx context.Context, answerID int) (int, error) {
+	return r.scanID(ctx, `SELECT exam_id FROM exam_answers WHERE id = $1`, answerID)
+}
+
+// ExerciseIDOfExerciseAnswer returns the exercise an exercise answer row belongs to
+func (r *AccessRepository) ExerciseIDOfExerciseAnswer(ctx context.Context, answerID int) (int, error) {
+	return r.scanID(ctx, `SELECT exercise_id FROM exercise_answers WHERE id = $1`, answerID)
+}
+
+// TeacherIDOfBankQuestion returns the teacher who owns a bank question
+func (r *AccessRepository) TeacherIDOfBankQuestion(ctx context.Context, questionID int) (int, error) {
+	return r.scanID(ctx, `SELECT teacher_id FROM question_bank WHERE id = $1`, questionID)
+}
+
+// StudentIDOfDiscussion returns the author of a discussion
+func (r *AccessRepository) StudentIDOfDiscussion(ctx context.Context, discussionID int) (int, error) {
+	return r.scanID(ctx, `SELECT student_id FROM general_forum WHERE id = $1`, discussionID)
+}
+
+func (r *AccessRepository) exists(ctx context.Context, query string, args ...any) (bool, error) {
+	var found bool
+	err := config.DB.QueryRow(ctx, "SELECT EXISTS ("+query+")", args...).Scan(&found)
+	if err != nil {
+		return false, err
+	}
+	return found, nil
+}
+
+func (r *AccessRepository) scanID(ctx context.Context, query string, args ...any) (int, error) {
+	var id *int
+	if err := config.DB.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		return 0, err
+	}
+	if id == nil {
+		return 0, nil
+	}
+	return *id, nil
+}
@@ -2,6 +2,9 @@ package repo
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
@@ -13,55 +16,179 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 7 * 24 * time.Hour
+)
+
+// ErrInvalidRefreshToken is returned when a refresh token is unknown, expired or revoked
+var ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
 // AuthRepository struct
 type AuthRepository struct{}
 
-// LoginUser finds a user by username and verifies password
-func (r *AuthRepository) LoginUser(ctx context.Context, username, password string) (string, error) {
-	// Query untuk menggabungkan tabel users dengan teachers dan students
-	query := `
-		SELECT u.id, u.username, u.email, u.password, u.role, u.display_name,
-		       t.id AS teacher_id, s.id AS student_id
-		FROM users u
-		LEFT JOIN teachers t ON t.user_id = u.id
-		LEFT JOIN students s ON s.user_id = u.id
-		WHERE u.username = $1
-	`
+// Query untuk menggabungkan tabel users dengan teachers dan students
+const userWithRolesQuery = `
+	SELECT u.id, u.username, u.email, u.password, u.role, u.display_name,
+	       t.id AS teacher_id, s.id AS student_id,
+	       COALESCE(s.status, t.status, '') AS account_status
+	FROM users u
+	LEFT JOIN teachers t ON t.user_id = u.id
+	LEFT JOIN students s ON s.user_id = u.id
+`
 
+// LoginUser finds a user by username, verifies password and opens a new session
+func (r *AuthRepository) LoginUser(ctx context.Context, username, password string) (models.AuthTokens, error) {
 	// Eksekusi query
-	row := config.DB.QueryRow(ctx, query, username)
+	row := config.DB.QueryRow(ctx, userWithRolesQuery+" WHERE u.username = $1", username)
 
 	var user models.User
 	var teacherID, studentID *int
+	var accountStatus string
 
 	// Scan hasil query ke variabel user dan ID untuk teacher dan student
 	err := row.Scan(
 		&user.ID, &user.Username, &user.Email, &user.Password, &user.Role, &user.Display_Name,
-		&teacherID, &studentID,
+		&teacherID, &studentID, &accountStatus,
 	)
 	if err != nil {
 		fmt.Println("User not found error:", err)
-		return "", errors.New("user not found")
+		return models.AuthTokens{}, errors.New("user not found")
 	}
 
 	// Cek kecocokan password dengan bcrypt
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
 		fmt.Println("Password mismatch:", err)
-		return "", errors.New("invalid password")
+		return models.AuthTokens{}, errors.New("invalid password")
+	}
+
+	// Akun yang dinonaktifkan tidak boleh login
+	if accountStatus == "Inactive" {
+		return models.AuthTokens{}, errors.New("account is inactive")
+	}
+
+	refreshToken, err := generateRefreshToken()
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+
+	// Simpan sesi baru dengan hash dari refresh token
+	var sessionID int
+	err = config.DB.QueryRow(ctx,
+		`INSERT INTO sessions (user_id, refresh_token_hash, expires_at) VALUES ($1, $2, $3) RETURNING id`,
+		user.ID, hashRefreshToken(refreshToken), time.Now().Add(refreshTokenTTL),
+	).Scan(&sessionID)
+	if err != nil {
+		return models.AuthTokens{}, fmt.Errorf("failed to create session: %w", err)
 	}
 
 	// Generate JWT token
-	token, err := generateJWT(user, teacherID, studentID)
+	token, err := generateJWT(user, teacherID, studentID, sessionID)
 	if err != nil {
 		fmt.Println("Error generating token:", err)
-		return "", err
+		return models.AuthTokens{}, err
 	}
 
-	return token, nil
+	return models.AuthTokens{
+		Token:         token,
+		Refresh_Token: refreshToken,
+		Expires_In:    int(accessTokenTTL.Seconds()),
+	}, nil
+}
+
+// RefreshSession rotates the refresh token of an active session and issues a new access token
+func (r *AuthRepository) RefreshSession(ctx context.Context, refreshToken string) (models.AuthTokens, error) {
+	oldHash := hashRefreshToken(refreshToken)
+
+	row := config.DB.QueryRow(ctx, userWithRolesQuery+`
+		JOIN sessions ss ON ss.user_id = u.id
+		WHERE ss.refresh_token_hash = $1 AND ss.revoked_at IS NULL AND ss.expires_at > NOW()
+	`, oldHash)
+
+	var user models.User
+	var teacherID, studentID *int
+	var accountStatus string
+	if err := row.Scan(
+		&user.ID, &user.Username, &user.Email, &user.Password, &user.Role, &user.Display_Name,
+		&teacherID, &studentID, &accountStatus,
+	); err != nil {
+		return models.AuthTokens{}, ErrInvalidRefreshToken
+	}
+
+	if accountStatus == "Inactive" {
+		return models.AuthTokens{}, ErrInvalidRefreshToken
+	}
+
+	newToken, err := generateRefreshToken()
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+
+	// Rotasi token: token lama langsung tidak berlaku lagi
+	var sessionID int
+	err = config.DB.QueryRow(ctx, `
+		UPDATE sessions
+		SET refresh_token_hash = $1, expires_at = $2, updated_at = NOW()
+		WHERE refresh_token_hash = $3 AND revoked_at IS NULL
+		RETURNING id
+	`, hashRefreshToken(newToken), time.Now().Add(refreshTokenTTL), oldHash).Scan(&sessionID)
+	if err != nil {
+		return models.AuthTokens{}, ErrInvalidRefreshToken
+	}
+
+	token, err := generateJWT(user, teacherID, studentID, sessionID)
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+
+	return models.AuthTokens{
+		Token:         token,
+		Refresh_Token: newToken,
+		Expires_In:    int(accessTokenTTL.Seconds()),
+	}, nil
+}
+
+// RevokeSession revokes the session that owns the given refresh token
+func (r *AuthRepository) RevokeSession(ctx context.Context, refreshToken string) error {
+	tag, err := config.DB.Exec(ctx, `
+		UPDATE sessions
+		SET revoked_at = NOW(), updated_at = NOW()
+		WHERE refresh_token_hash = $1 AND revoked_at IS NULL
+	`, hashRefreshToken(refreshToken))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrInvalidRefreshToken
+	}
+	return nil
+}
+
+// IsSessionActive reports whether a session is still valid and its account is not disabled
+func (r *AuthRepository) IsSessionActive(ctx context.Context, sessionID int) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM sessions ss
+			JOIN users u ON u.id = ss.user_id
+			LEFT JOIN teachers t ON t.user_id = u.id
+			LEFT JOIN students s ON s.user_id = u.id
+			WHERE ss.id = $1
+			  AND ss.revoked_at IS NULL
+			  AND ss.expires_at > NOW()
+			  AND COALESCE(s.status, t.status, '') <> 'Inactive'
+		)
+	`
+
+	var active bool
+	if err := config.DB.QueryRow(ctx, query, sessionID).Scan(&active); err != nil {
+		return false, err
+	}
+	return active, nil
 }
 
 // GenerateJWT creates a JWT token for authentication
-func generateJWT(user models.User, teacherID, studentID *int) (string, error) {
+func generateJWT(user models.User, teacherID, studentID *int, sessionID int) (string, error) {
 	// Ambil secret key dari environment variable
 	secretKey := os.Getenv("JWT_SECRET")
 	if secretKey == "" {
@@ -73,7 +200,8 @@ func generateJWT(user models.User, teacherID, studentID *int) (string, error) {
 		"user_id":      user.ID,
 		"email":        user.Email,
 		"role":         user.Role,
-		"exp":          time.Now().Add(24 * time.Hour).Unix(),
+		"sid":          sessionID,
+		"exp":          time.Now().Add(accessTokenTTL).Unix(),
 		"display_name": user.Display_Name,
 	}
 
@@ -91,3 +219,18 @@ func generateJWT(user models.User, teacherID, studentID *int) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(secretKey))
 }
+
+// generateRefreshToken creates a random opaque refresh token
+func generateRefreshToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashRefreshToken hashes a refresh token so only the digest is stored
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
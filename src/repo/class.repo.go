@@ -7,13 +7,14 @@ import (
 	"project-ppl-be/src/models"
 
 	"github.com/huandu/go-sqlbuilder"
+	"github.com/jackc/pgx/v5"
 )
 
 // ClassRepository struct
 type ClassRepository struct{}
 
-// GettAllClasses retrieves all classes from the database
-func (r *ClassRepository) GettAllClasses(ctx context.Context, page, pageSize int) ([]models.Class, int, error) {
+// GettAllClasses retrieves all classes from the database, limited to one teacher when teacherID is set
+func (r *ClassRepository) GettAllClasses(ctx context.Context, page, pageSize, teacherID int) ([]models.Class, int, error) {
 	sb := sqlbuilder.NewSelectBuilder()
 	sb.Select("classes.id", "classes.name", "classes.description", "classes.grade", "classes.teacher_id", "teachers.name AS teacher_name").
 		From("classes").
@@ -21,6 +22,10 @@ func (r *ClassRepository) GettAllClasses(ctx context.Context, page, pageSize int
 		Limit(pageSize).
 		Offset((page - 1) * pageSize)
 
+	if teacherID > 0 {
+		sb.Where(sb.Equal("classes.teacher_id", teacherID))
+	}
+
 	query, args := sb.BuildWithFlavor(sqlbuilder.PostgreSQL)
 	rows, err := config.DB.Query(ctx, query, args...)
 	if err != nil {
@@ -41,9 +46,9 @@ func (r *ClassRepository) GettAllClasses(ctx context.Context, page, pageSize int
 		return nil, 0, err
 	}
 
-	countQuery := "SELECT COUNT(*) FROM classes"
+	countQuery := "SELECT COUNT(*) FROM classes WHERE $1 = 0 OR teacher_id = $1"
 	var total int
-	err = config.DB.QueryRow(ctx, countQuery).Scan(&total)
+	err = config.DB.QueryRow(ctx, countQuery, teacherID).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -112,14 +117,35 @@ func (r *ClassRepository) UpdateClass(ctx context.Context, id int, name string,
 	return class, nil
 }
 
-// DeleteClass deletes a class from the database
+// DeleteClass deletes a class from the database and drops its final grades from the students' curr_score
 func (r *ClassRepository) DeleteClass(ctx context.Context, id int) error {
 	sb := sqlbuilder.NewDeleteBuilder()
 	sb.DeleteFrom("classes").Where(sb.Equal("id", id))
 	query, args := sb.BuildWithFlavor(sqlbuilder.PostgreSQL)
 
-	_, err := config.DB.Exec(ctx, query, args...)
-	return err
+	tx, err := config.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `SELECT student_id FROM class_final_grades WHERE class_id = $1`, id)
+	if err != nil {
+		return err
+	}
+	studentIDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		return err
+	}
+	for _, studentID := range studentIDs {
+		if err := syncCurrentScore(ctx, tx, studentID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
 }
 
 // GetClassById retrieves all classes from the database
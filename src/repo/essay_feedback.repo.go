@@ -0,0 +1,101 @@
+package repo
+
+import (
+	"context"
+	"project-ppl-be/config"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const essayFeedbackColumns = "id, score_id, question_key, generated, feedback, edited_by, updated_at"
+
+func scanEssayFeedback(row pgx.Row, f *models.EssayFeedback) error {
+	return row.Scan(&f.ID, &f.Score_ID, &f.Question_Key, &f.Generated, &f.Feedback, &f.Edited_By, &f.Updated_At)
+}
+
+// saveEssayFeedback stores the grader's feedback on every essay of a new score as an unreleased draft
+func (r *ExerciseRepository) saveEssayFeedback(ctx context.Context, tx pgx.Tx, scoreID int, essays map[string]utils.EssayGrade) error {
+	for _, draft := range utils.EssayFeedbackDrafts(scoreID, essays) {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO essay_feedback (score_id, question_key, generated, feedback) VALUES ($1, $2, $3, $4)`,
+			draft.Score_ID, draft.Question_Key, draft.Generated, draft.Feedback,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateEssayFeedback replaces the feedback on one question of a graded attempt.
+// It returns pgx.ErrNoRows when the score has no such question.
+func (r *ExerciseRepository) UpdateEssayFeedback(ctx context.Context, req models.UpdateEssayFeedbackRequest, userID int) (models.EssayFeedback, error) {
+	var feedback models.EssayFeedback
+	err := scanEssayFeedback(config.DB.QueryRow(ctx, `
+		INSERT INTO essay_feedback (score_id, question_key, feedback, edited_by)
+		SELECT id, $2, $3, $4 FROM exercise_scores WHERE id = $1 AND detail ? $2
+		ON CONFLICT (score_id, question_key) DO UPDATE
+		SET feedback = EXCLUDED.feedback, edited_by = EXCLUDED.edited_by, updated_at = NOW()
+		RETURNING `+essayFeedbackColumns,
+		req.Score_ID, req.Question_Key, req.Feedback, userID,
+	), &feedback)
+	if err != nil {
+		return models.EssayFeedback{}, err
+	}
+	return feedback, nil
+}
+
+// ReleaseEssayFeedback makes the feedback of a graded attempt visible to the student.
+// Releasing again keeps the first release time.
+func (r *ExerciseRepository) ReleaseEssayFeedback(ctx context.Context, scoreID int) error {
+	tag, err := config.DB.Exec(ctx,
+		`UPDATE exercise_scores SET feedback_released_at = COALESCE(feedback_released_at, NOW()) WHERE id = $1`,
+		scoreID,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// GetGradedAttempt returns a graded attempt with its feedback. Unless withDrafts is set,
+// feedback is only included once released and without the grader's original text.
+func (r *ExerciseRepository) GetGradedAttempt(ctx context.Context, attemptID int, withDrafts bool) (models.GradedAttempt, error) {
+	var g models.GradedAttempt
+	err := config.DB.QueryRow(ctx, `
+		SELECT a.id, a.exercise_id, a.student_id, a.attempt_number, a.answers, a.submitted_at,
+			s.score, s.detail, s.id, s.feedback_released_at
+		FROM exercise_attempts a
+		JOIN exercise_scores s ON s.attempt_id = a.id
+		WHERE a.id = $1
+	`, attemptID).Scan(
+		&g.ID, &g.Exercise_ID, &g.Student_ID, &g.Attempt_Number, &g.Answers, &g.Submitted_At,
+		&g.Score, &g.Detail, &g.Score_ID, &g.Feedback_Released_At,
+	)
+	if err != nil {
+		return models.GradedAttempt{}, err
+	}
+
+	rows, err := config.DB.Query(ctx,
+		`SELECT `+essayFeedbackColumns+` FROM essay_feedback WHERE score_id = $1 ORDER BY question_key::INT`,
+		g.Score_ID,
+	)
+	if err != nil {
+		return models.GradedAttempt{}, err
+	}
+	feedback, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.EssayFeedback, error) {
+		var f models.EssayFeedback
+		err := scanEssayFeedback(row, &f)
+		return f, err
+	})
+	if err != nil {
+		return models.GradedAttempt{}, err
+	}
+
+	g.Feedback = utils.VisibleEssayFeedback(feedback, g.Feedback_Released_At, withDrafts)
+	return g, nil
+}
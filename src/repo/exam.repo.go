@@ -3,14 +3,16 @@ package repo
 import (
     "context"
     "encoding/json"
-    "strconv"
-    "strings"
+    "errors"
 		"fmt"
+    "math"
+    "strings"
 
     "project-ppl-be/config"
     "project-ppl-be/src/models"
 
     "github.com/huandu/go-sqlbuilder"
+    "github.com/jackc/pgx/v5"
 
 		utils "project-ppl-be/src/utils"
 )
@@ -18,10 +20,32 @@ import (
 
 type ExamRepository struct{}
 
+// examColumns is the column order read by scanExam
+var examColumns = []string{"id", "class_id", "title", "content", "total_marks", "teacher_id", "start_time", "end_time", "status", "negative_marking", "late_grace_minutes", "duration_minutes", "essay_grading", "release_policy", "released_at", "kkm", "remedial_of", "remedial_policy"}
+
+func scanExam(row pgx.Row, ex *models.Exams) error {
+    return row.Scan(&ex.ID, &ex.Class_ID, &ex.Title, &ex.Content, &ex.Total_Marks, &ex.Teacher_ID, &ex.Start_Time, &ex.End_Time, &ex.Status, &ex.Negative_Marking, &ex.Late_Grace_Minutes, &ex.Duration_Minutes, &ex.Essay_Grading, &ex.Release_Policy, &ex.Released_At, &ex.KKM, &ex.Remedial_Of, &ex.Remedial_Policy)
+}
+
+// GetExamByID retrieves a single exam
+func (r *ExamRepository) GetExamByID(ctx context.Context, id int) (models.Exams, error) {
+    sb := sqlbuilder.NewSelectBuilder()
+    sb.Select(examColumns...).
+        From("exams").
+        Where(sb.Equal("id", id))
+
+    query, args := sb.BuildWithFlavor(sqlbuilder.PostgreSQL)
+    var ex models.Exams
+    if err := scanExam(config.DB.QueryRow(ctx, query, args...), &ex); err != nil {
+        return models.Exams{}, err
+    }
+    return ex, nil
+}
+
 // Get by class_id
 func (r *ExamRepository) GetExamsByClassID(ctx context.Context, classID int) ([]models.Exams, error) {
     sb := sqlbuilder.NewSelectBuilder()
-    sb.Select("id", "class_id", "title", "content", "total_marks", "teacher_id", "start_time", "end_time", "status").
+    sb.Select(examColumns...).
         From("exams").
         Where(sb.Equal("class_id", classID))
 
@@ -35,7 +59,7 @@ func (r *ExamRepository) GetExamsByClassID(ctx context.Context, classID int) ([]
     var list []models.Exams
     for rows.Next() {
         var ex models.Exams
-        if err := rows.Scan(&ex.ID, &ex.Class_ID, &ex.Title, &ex.Content, &ex.Total_Marks, &ex.Teacher_ID, &ex.Start_Time, &ex.End_Time, &ex.Status); err != nil {
+        if err := scanExam(rows, &ex); err != nil {
             return nil, err
         }
         list = append(list, ex)
@@ -43,68 +67,57 @@ func (r *ExamRepository) GetExamsByClassID(ctx context.Context, classID int) ([]
     return list, rows.Err()
 }
 
-func (r *ExamRepository) GetExamsByClassIDForStudent(ctx context.Context, classID int, number int) ([]models.Exams, error) {
-    sb := sqlbuilder.NewSelectBuilder()
-    sb.Select("id", "class_id", "title", "content", "total_marks", "teacher_id", "start_time", "end_time", "status").
-        From("exams").
-        Where(sb.Equal("class_id", classID))
-
-    query, args := sb.BuildWithFlavor(sqlbuilder.PostgreSQL)
-    row := config.DB.QueryRow(ctx, query, args...)
-
-    var ex models.Exams
-    var contentBytes []byte
-
-    if err := row.Scan(&ex.ID, &ex.Class_ID, &ex.Title, &contentBytes, &ex.Total_Marks, &ex.Teacher_ID, &ex.Start_Time, &ex.End_Time, &ex.Status); err != nil {
-        return nil, err
-    }
-
-    // Unmarshal content (JSON) ke map
-    var fullContent map[string]string
-    if err := json.Unmarshal(contentBytes, &fullContent); err != nil {
+// GetExamForStudent returns an exam with only the question with the given number, without answer keys
+func (r *ExamRepository) GetExamForStudent(ctx context.Context, examID int, number int) ([]models.Exams, error) {
+    ex, err := r.GetExamByID(ctx, examID)
+    if err != nil {
         return nil, err
     }
 
-    // Konversi number ke string
-    numberStr := strconv.Itoa(number)
-
-    // Filter content yang sesuai nomor soal dan bukan _answer
-    filtered := make(map[string]string)
-    for key, val := range fullContent {
-        if (key == numberStr || strings.HasPrefix(key, numberStr+"_")) && !strings.HasSuffix(key, "_answer") {
-            filtered[key] = val
-        }
-    }
-
-    // Assign content hasil filter ke ex.Content
-    ex.Content = filtered
+    // Ambil hanya soal dengan nomor yang diminta, tanpa kunci jawaban
+    ex.Content = utils.StripAnswerKeys(ex.Content.Only(number))
 
     return []models.Exams{ex}, nil
 }
 
-// Create
+// CreateExam inserts the exam and records the bank questions it uses. A remedial exam is given to its
+// students in the same transaction.
 func (r *ExamRepository) CreateExam(ctx context.Context, req models.CreateExamsRequest) (models.Exams, error) {
 		status := utils.CheckExamStatus(req.Start_Time, req.End_Time)
 
     ib := sqlbuilder.NewInsertBuilder()
     ib.InsertInto("exams").
-        Cols("class_id", "title", "content", "total_marks", "teacher_id", "start_time", "end_time", "status").
-        Values(req.Class_ID, req.Title, req.Content, req.Total_Marks, req.Teacher_ID, req.Start_Time, req.End_Time, status).
-        Returning("id", "class_id", "title", "content", "total_marks", "teacher_id", "start_time", "end_time", "status")
+        Cols("class_id", "title", "content", "total_marks", "teacher_id", "start_time", "end_time", "status", "negative_marking", "late_grace_minutes", "duration_minutes", "essay_grading", "release_policy", "kkm", "remedial_of", "remedial_policy").
+        Values(req.Class_ID, req.Title, req.Content, req.Total_Marks, req.Teacher_ID, req.Start_Time, req.End_Time, status, req.Negative_Marking, req.Late_Grace_Minutes, req.Duration_Minutes, essayGradingOrDefault(req.Essay_Grading), releasePolicyOrDefault(req.Release_Policy), req.KKM, req.Remedial_Of, remedialPolicyOrDefault(req.Remedial_Policy)).
+        Returning(examColumns...)
 
     query, args := ib.BuildWithFlavor(sqlbuilder.PostgreSQL)
-    var ex models.Exams
-    err := config.DB.QueryRow(ctx, query, args...).Scan(
-        &ex.ID, &ex.Class_ID, &ex.Title, &ex.Content, &ex.Total_Marks, &ex.Teacher_ID, &ex.Start_Time, &ex.End_Time, &ex.Status,
-    )
+
+    tx, err := config.DB.Begin(ctx)
     if err != nil {
         return models.Exams{}, err
     }
-    return ex, nil
+    defer tx.Rollback(ctx)
+
+    var ex models.Exams
+    if err := scanExam(tx.QueryRow(ctx, query, args...), &ex); err != nil {
+        return models.Exams{}, err
+    }
+    if err := syncUsages(ctx, tx, "exam_id", ex.ID, ex.Content); err != nil {
+        return models.Exams{}, err
+    }
+    if ex.Remedial_Of != nil {
+        if err := r.assignRemedialParticipants(ctx, tx, ex.ID, *ex.Remedial_Of, req.Student_IDs); err != nil {
+            return models.Exams{}, err
+        }
+    }
+    return ex, tx.Commit(ctx)
 }
 
-// Update
-func (r *ExamRepository) UpdateExam(ctx context.Context, id int, req models.CreateExamsRequest) (models.Exams, error) {
+// UpdateExam saves the exam and records the bank questions it uses. With regradeBy set the stored
+// scores are regraded against the new content in the same transaction. Remedial scores are recorded
+// on the original exam again under the new settings.
+func (r *ExamRepository) UpdateExam(ctx context.Context, id int, req models.CreateExamsRequest, regradeBy *int) (models.Exams, error) {
 		status := utils.CheckExamStatus(req.Start_Time, req.End_Time)
 
     ub := sqlbuilder.NewUpdateBuilder()
@@ -118,34 +131,95 @@ func (r *ExamRepository) UpdateExam(ctx context.Context, id int, req models.Crea
             ub.Assign("start_time", req.Start_Time),
             ub.Assign("end_time", req.End_Time),
             ub.Assign("status", status),
+            ub.Assign("negative_marking", req.Negative_Marking),
+            ub.Assign("late_grace_minutes", req.Late_Grace_Minutes),
+            ub.Assign("duration_minutes", req.Duration_Minutes),
+            ub.Assign("essay_grading", essayGradingOrDefault(req.Essay_Grading)),
+            ub.Assign("release_policy", releasePolicyOrDefault(req.Release_Policy)),
+            ub.Assign("kkm", req.KKM),
+            ub.Assign("remedial_policy", remedialPolicyOrDefault(req.Remedial_Policy)),
         ).
         Where(ub.Equal("id", id))
 
     query, args := ub.BuildWithFlavor(sqlbuilder.PostgreSQL)
-    query += " RETURNING id, class_id, title, content, total_marks, teacher_id, start_time, end_time, status"
+    query += " RETURNING " + strings.Join(examColumns, ", ")
 
-    var ex models.Exams
-    err := config.DB.QueryRow(ctx, query, args...).Scan(
-        &ex.ID, &ex.Class_ID, &ex.Title, &ex.Content, &ex.Total_Marks, &ex.Teacher_ID, &ex.Start_Time, &ex.End_Time, &ex.Status,
-    )
+    tx, err := config.DB.Begin(ctx)
     if err != nil {
         return models.Exams{}, err
     }
-    return ex, nil
+    defer tx.Rollback(ctx)
+
+    var ex models.Exams
+    if err := scanExam(tx.QueryRow(ctx, query, args...), &ex); err != nil {
+        return models.Exams{}, err
+    }
+    if err := syncUsages(ctx, tx, "exam_id", ex.ID, ex.Content); err != nil {
+        return models.Exams{}, err
+    }
+    if regradeBy != nil {
+        if _, _, err := regradeScores(ctx, tx, examScoreTable, "exam_id", ex.ID, ex.Content, ex.Negative_Marking, regradeBy); err != nil {
+            return models.Exams{}, err
+        }
+    }
+    // Kebijakan remedial, KKM atau rilis bisa mengubah nilai remedial yang tercatat
+    if err := syncRemedialScores(ctx, tx, ex.ID); err != nil {
+        return models.Exams{}, err
+    }
+    return ex, tx.Commit(ctx)
 }
 
-// Delete
+// DeleteExam deletes an exam with its remedial exams and recomputes the final grades its scores counted in.
+// Scores a deleted remedial exam replaced go back to what they were before the remedial.
 func (r *ExamRepository) DeleteExam(ctx context.Context, id int) error {
     db := sqlbuilder.NewDeleteBuilder()
     db.DeleteFrom("exams").Where(db.Equal("id", id))
     query, args := db.BuildWithFlavor(sqlbuilder.PostgreSQL)
-    _, err := config.DB.Exec(ctx, query, args...)
-    return err
+
+    tx, err := config.DB.Begin(ctx)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback(ctx)
+
+    students, err := classStudents(ctx, tx, examStudentsQuery, id)
+    if err != nil {
+        return err
+    }
+    if _, err := tx.Exec(ctx, `
+        UPDATE exam_scores o
+        SET score = o.pre_remedial_score, pre_remedial_score = NULL, remedial_score_id = NULL
+        FROM exam_scores rs
+        WHERE rs.id = o.remedial_score_id AND rs.exam_id = $1
+    `, id); err != nil {
+        return err
+    }
+    if _, err := tx.Exec(ctx, query, args...); err != nil {
+        return err
+    }
+    if err := syncFinalGrades(ctx, tx, students); err != nil {
+        return err
+    }
+    return tx.Commit(ctx)
+}
+
+// examAnswerColumns is the column order read by scanExamAnswer
+var examAnswerColumns = []string{"id", "exam_id", "answers", "student_id", "status", "submitted_at", "is_late", "late_seconds"}
+
+func scanExamAnswer(row pgx.Row, ex *models.ExamAnswers) error {
+    var status *string
+    if err := row.Scan(&ex.ID, &ex.Exam_ID, &ex.Answers, &ex.Student_ID, &status, &ex.Submitted_At, &ex.Is_Late, &ex.Late_Seconds); err != nil {
+        return err
+    }
+    if status != nil {
+        ex.Status = *status
+    }
+    return nil
 }
 
 func (r *ExamRepository) GetExamAnswers(ctx context.Context, examID int, studentID int) ([]models.ExamAnswers, error) {
     sb := sqlbuilder.NewSelectBuilder()
-    sb.Select("id", "exam_id", "answers", "student_id").
+    sb.Select(examAnswerColumns...).
         From("exam_answers").
         Where(sb.Equal("exam_id", examID)).
 				Where(sb.Equal("student_id", studentID))
@@ -160,7 +234,7 @@ func (r *ExamRepository) GetExamAnswers(ctx context.Context, examID int, student
     var list []models.ExamAnswers
     for rows.Next() {
         var ex models.ExamAnswers
-        if err := rows.Scan(&ex.ID, &ex.Exam_ID, &ex.Answers, &ex.Student_ID); err != nil {
+        if err := scanExamAnswer(rows, &ex); err != nil {
             return nil, err
         }
         list = append(list, ex)
@@ -168,64 +242,87 @@ func (r *ExamRepository) GetExamAnswers(ctx context.Context, examID int, student
     return list, rows.Err()
 }
 
-func (r *ExamRepository) CreateExamAnswers(ctx context.Context, req models.CreateExamAnswersRequest) (models.ExamAnswers, error) {
+// CreateExamAnswers saves a submission; lateSeconds > 0 marks it as submitted during the late grace period.
+// A student has one answer sheet per exam, so saving again replaces the draft.
+func (r *ExamRepository) CreateExamAnswers(ctx context.Context, req models.CreateExamAnswersRequest, lateSeconds int) (models.ExamAnswers, error) {
     ib := sqlbuilder.NewInsertBuilder()
     ib.InsertInto("exam_answers").
-        Cols("exam_id", "answers", "student_id").
-        Values(req.Exam_ID, req.Answers, req.Student_ID, "Active").
-        Returning("id", "exam_id", "answers", "student_id")
+        Cols("exam_id", "answers", "student_id", "status", "submitted_at", "is_late", "late_seconds").
+        Values(req.Exam_ID, req.Answers, req.Student_ID, models.AnswerStatusDraft, sqlbuilder.Raw("NOW()"), lateSeconds > 0, lateSeconds).
+        SQL(`ON CONFLICT (exam_id, student_id) DO UPDATE
+            SET answers = EXCLUDED.answers, submitted_at = EXCLUDED.submitted_at, is_late = EXCLUDED.is_late, late_seconds = EXCLUDED.late_seconds
+            WHERE exam_answers.status IS DISTINCT FROM ` + ib.Var(models.AnswerStatusSubmitted)).
+        Returning(examAnswerColumns...)
 
     query, args := ib.BuildWithFlavor(sqlbuilder.PostgreSQL)
+
+    // Jawaban yang sudah dikumpulkan tidak bisa diubah
     var ex models.ExamAnswers
-    err := config.DB.QueryRow(ctx, query, args...).Scan(
-        &ex.ID, &ex.Exam_ID, &ex.Answers, &ex.Student_ID,
-    )
+    err := scanExamAnswer(config.DB.QueryRow(ctx, query, args...), &ex)
+    if errors.Is(err, pgx.ErrNoRows) {
+        return models.ExamAnswers{}, ErrAnswersSubmitted
+    }
     if err != nil {
         return models.ExamAnswers{}, err
     }
     return ex, nil
 }
 
-// Update
-func (r *ExamRepository) UpdateExamAnswers(ctx context.Context, id int, req models.CreateExamAnswersRequest) (models.ExamAnswers, error) {
+// UpdateExamAnswers changes a draft; it returns ErrAnswersSubmitted once the answers are submitted
+func (r *ExamRepository) UpdateExamAnswers(ctx context.Context, id int, req models.CreateExamAnswersRequest, lateSeconds int) (models.ExamAnswers, error) {
     ub := sqlbuilder.NewUpdateBuilder()
     ub.Update("exam_answers").
         Set(
             ub.Assign("exam_id", req.Exam_ID),
             ub.Assign("answers", req.Answers),
             ub.Assign("student_id", req.Student_ID),
+            "submitted_at = NOW()",
+            ub.Assign("is_late", lateSeconds > 0),
+            ub.Assign("late_seconds", lateSeconds),
         ).
-        Where(ub.Equal("id", id))
+        Where(ub.Equal("id", id)).
+        Where("status IS DISTINCT FROM " + ub.Var(models.AnswerStatusSubmitted))
 
     query, args := ub.BuildWithFlavor(sqlbuilder.PostgreSQL)
-    query += " RETURNING id, exam_id, answers, student_id"
+    query += " RETURNING " + strings.Join(examAnswerColumns, ", ")
 
+    // Jawaban yang sudah dikumpulkan tidak bisa diubah
     var ex models.ExamAnswers
-    err := config.DB.QueryRow(ctx, query, args...).Scan(
-        &ex.ID, &ex.Exam_ID, &ex.Answers, &ex.Student_ID,
-    )
+    err := scanExamAnswer(config.DB.QueryRow(ctx, query, args...), &ex)
+    if errors.Is(err, pgx.ErrNoRows) {
+        return models.ExamAnswers{}, r.examAnswerLocked(ctx, id)
+    }
     if err != nil {
         return models.ExamAnswers{}, err
     }
     return ex, nil
 }
 
-// Delete
+// DeleteExamAnswers deletes a draft; it returns ErrAnswersSubmitted once the answers are submitted
 func (r *ExamRepository) DeleteExamAnswers(ctx context.Context, id int) error {
     db := sqlbuilder.NewDeleteBuilder()
-    db.DeleteFrom("exam_answers").Where(db.Equal("id", id))
+    db.DeleteFrom("exam_answers").
+        Where(db.Equal("id", id)).
+        Where("status IS DISTINCT FROM " + db.Var(models.AnswerStatusSubmitted))
     query, args := db.BuildWithFlavor(sqlbuilder.PostgreSQL)
-    _, err := config.DB.Exec(ctx, query, args...)
-    return err
+    tag, err := config.DB.Exec(ctx, query, args...)
+    if err != nil {
+        return err
+    }
+    if tag.RowsAffected() == 0 {
+        return r.examAnswerLocked(ctx, id)
+    }
+    return nil
 }
 
 func (r *ExamRepository) GetExamGrades(ctx context.Context, examID int, studentID int) ([]models.ExamGrades, error) {
 	sb := sqlbuilder.NewSelectBuilder()
-	sb.Select("id", "student_id", "exam_id", "score").
-			From("exam_scores").
-			Where(sb.Equal("exam_id", examID)).
-			Where(sb.Equal("student_id", studentID)).
-			OrderBy("id DESC").
+	sb.Select("s.id", "s.student_id", "s.exam_id", "s.score", releaseColumns).
+			From("exam_scores s").
+			Join("exams e", "e.id = s.exam_id").
+			Where(sb.Equal("s.exam_id", examID)).
+			Where(sb.Equal("s.student_id", studentID)).
+			OrderBy("s.id DESC").
 			Limit(1)
 
     query, args := sb.BuildWithFlavor(sqlbuilder.PostgreSQL)
@@ -238,9 +335,11 @@ func (r *ExamRepository) GetExamGrades(ctx context.Context, examID int, studentI
     var list []models.ExamGrades
     for rows.Next() {
         var ex models.ExamGrades
-        if err := rows.Scan(&ex.ID, &ex.Student_ID, &ex.Exam_ID, &ex.Score); err != nil {
+        var exam models.Exams
+        if err := rows.Scan(&ex.ID, &ex.Student_ID, &ex.Exam_ID, &ex.Score, &exam.Release_Policy, &exam.Released_At, &exam.End_Time, &exam.Late_Grace_Minutes); err != nil {
             return nil, err
         }
+        ex.Status = scoreStatus(exam)
         list = append(list, ex)
     }
     return list, rows.Err()
@@ -248,9 +347,10 @@ func (r *ExamRepository) GetExamGrades(ctx context.Context, examID int, studentI
 
 func (r *ExamRepository) GetAllExamGrades(ctx context.Context, studentID int) ([]models.ExamGrades, error) {
 	sb := sqlbuilder.NewSelectBuilder()
-	sb.Select("id", "student_id", "exam_id", "score").
-			From("exam_scores").
-			Where(sb.Equal("student_id", studentID))
+	sb.Select("s.id", "s.student_id", "s.exam_id", "s.score", releaseColumns).
+			From("exam_scores s").
+			Join("exams e", "e.id = s.exam_id").
+			Where(sb.Equal("s.student_id", studentID))
 
     query, args := sb.BuildWithFlavor(sqlbuilder.PostgreSQL)
     rows, err := config.DB.Query(ctx, query, args...)
@@ -262,15 +362,20 @@ func (r *ExamRepository) GetAllExamGrades(ctx context.Context, studentID int) ([
     var list []models.ExamGrades
     for rows.Next() {
         var ex models.ExamGrades
-        if err := rows.Scan(&ex.ID, &ex.Student_ID, &ex.Exam_ID, &ex.Score); err != nil {
+        var exam models.Exams
+        if err := rows.Scan(&ex.ID, &ex.Student_ID, &ex.Exam_ID, &ex.Score, &exam.Release_Policy, &exam.Released_At, &exam.End_Time, &exam.Late_Grace_Minutes); err != nil {
             return nil, err
         }
+        ex.Status = scoreStatus(exam)
         list = append(list, ex)
     }
     return list, rows.Err()
 }
 
-func (r *ExamRepository) CalculateExamGrades(ctx context.Context, req models.CalculateExamGrades) (models.ExamGrades, error) {
+// calculateExamGrades grades the student's answers and saves the score with its final grade, remedial score
+// and queued essays, then finalizes the student's timed attempt. Nothing is saved when any step fails.
+// Auto graded essays take their grade from essays, graded by gradeExamEssays before tx was opened.
+func (r *ExamRepository) calculateExamGrades(ctx context.Context, tx pgx.Tx, req models.CalculateExamGrades, essays map[string]examEssay) (models.ExamGrades, error) {
     // ---------------------------------------------------
     // 1️⃣ Ambil jawaban dari exam_answers
     sbAns := sqlbuilder.NewSelectBuilder()
@@ -281,78 +386,119 @@ func (r *ExamRepository) CalculateExamGrades(ctx context.Context, req models.Cal
     queryAns, argsAns := sbAns.BuildWithFlavor(sqlbuilder.PostgreSQL)
 
     var answerBytes []byte
-    if err := config.DB.QueryRow(ctx, queryAns, argsAns...).Scan(&answerBytes); err != nil {
+    if err := tx.QueryRow(ctx, queryAns, argsAns...).Scan(&answerBytes); err != nil {
         return models.ExamGrades{}, fmt.Errorf("failed to get student answers: %w", err)
     }
 
-    var studentAnswers map[string]string
+    var studentAnswers map[string]any
     if err := json.Unmarshal(answerBytes, &studentAnswers); err != nil {
         return models.ExamGrades{}, fmt.Errorf("failed to unmarshal student answers: %w", err)
     }
 
     // ---------------------------------------------------
-    // 2️⃣ Ambil soal dan total_marks dari exams
+    // 2️⃣ Ambil soal, aturan nilai negatif dan cara penilaian esai dari exams
     sbEx := sqlbuilder.NewSelectBuilder()
-    sbEx.Select("content", "total_marks").
+    sbEx.Select("content", "negative_marking", "essay_grading", "release_policy", "released_at", "end_time", "late_grace_minutes").
         From("exams").
         Where(sbEx.Equal("id", req.Exam_ID))
     queryEx, argsEx := sbEx.BuildWithFlavor(sqlbuilder.PostgreSQL)
 
     var contentBytes []byte
-    var totalMarks int
-    if err := config.DB.QueryRow(ctx, queryEx, argsEx...).Scan(&contentBytes, &totalMarks); err != nil {
+    var negativeMarking float64
+    var essayGrading string
+    var exam models.Exams
+    if err := tx.QueryRow(ctx, queryEx, argsEx...).Scan(&contentBytes, &negativeMarking, &essayGrading, &exam.Release_Policy, &exam.Released_At, &exam.End_Time, &exam.Late_Grace_Minutes); err != nil {
         return models.ExamGrades{}, fmt.Errorf("failed to get exam data: %w", err)
     }
 
-    var fullContent map[string]string
+    var fullContent models.QuestionContent
     if err := json.Unmarshal(contentBytes, &fullContent); err != nil {
         return models.ExamGrades{}, fmt.Errorf("failed to unmarshal exam content: %w", err)
     }
 
     // ---------------------------------------------------
-    // 3️⃣ Hitung jumlah soal dan nilai per soal
-    correctAnswerKeys := make(map[string]string)
-    for key, val := range fullContent {
-        if strings.HasSuffix(key, "_answer") {
-            soalNumber := strings.TrimSuffix(key, "_answer") // contoh: "1_answer" -> "1"
-            correctAnswerKeys[soalNumber] = val
-        }
-    }
-
-    totalQuestions := len(correctAnswerKeys)
-    if totalQuestions == 0 {
+    // 3️⃣ Pastikan ujian memiliki soal
+    questions := fullContent.Questions
+    if len(questions) == 0 {
         return models.ExamGrades{}, fmt.Errorf("no questions found for exam id %d", req.Exam_ID)
     }
 
-    marksPerQuestion := float64(totalMarks) / float64(totalQuestions)
-
     // ---------------------------------------------------
-    // 4️⃣ Hitung total nilai
+    // 4️⃣ Hitung total nilai, setiap soal bernilai sebesar bobotnya
+    // Nilai negatif bisa mengurangi total, tetapi total tidak pernah di bawah 0.
+    // Esai dinilai langsung pada mode auto; pada mode manual dan queued esai bernilai 0 sampai dinilai.
     var totalScore float64
-    for soalNumber, correctAnswer := range correctAnswerKeys {
-        if studentAnswers[soalNumber] == correctAnswer {
-            totalScore += marksPerQuestion
+    var pendingEssays []string
+    detail := make(map[string]models.QuestionResult)
+    for _, q := range questions {
+        answer := studentAnswers[q.Key()]
+
+        if q.Type == models.QuestionTypeEssay {
+            if essayGrading == models.EssayGradingAuto {
+                essay, ok := essays[q.Key()]
+                if !ok || essay.answer != utils.AnswerText(answer) {
+                    return models.ExamGrades{}, ErrEssaysChanged
+                }
+                result := examEssayResult(q, answer, essay.grade)
+                totalScore += result.Score
+                detail[q.Key()] = result
+            } else {
+                detail[q.Key()] = models.QuestionResult{Type: q.Type, Max_Score: q.Weight, Answer: answer, Pending: true}
+                pendingEssays = append(pendingEssays, q.Key())
+            }
+            continue
         }
+
+        result := utils.ScoreQuestion(q, answer, negativeMarking)
+        totalScore += result.Score
+        detail[q.Key()] = result
     }
+    totalScore = math.Max(totalScore, 0)
 
     // ---------------------------------------------------
-    // 5️⃣ Simpan nilai ke exam_scores
+    // 5️⃣ Simpan nilai dan detail ke exam_scores
+    detailBytes, err := json.Marshal(detail)
+    if err != nil {
+        return models.ExamGrades{}, fmt.Errorf("failed to marshal detail: %w", err)
+    }
+
     ib := sqlbuilder.NewInsertBuilder()
     ib.InsertInto("exam_scores").
-        Cols("student_id", "exam_id", "score").
-        Values(req.Student_ID, req.Exam_ID, totalScore).
-        Returning("student_id", "exam_id", "score")
+        Cols("student_id", "exam_id", "score", "detail").
+        Values(req.Student_ID, req.Exam_ID, totalScore, detailBytes).
+        Returning("id", "student_id", "exam_id", "score", "detail")
 
     queryInsert, argsInsert := ib.BuildWithFlavor(sqlbuilder.PostgreSQL)
 
     var savedScore models.ExamGrades
-    if err := config.DB.QueryRow(ctx, queryInsert, argsInsert...).Scan(
+    if err := tx.QueryRow(ctx, queryInsert, argsInsert...).Scan(
+        &savedScore.ID,
         &savedScore.Student_ID,
         &savedScore.Exam_ID,
         &savedScore.Score,
+        &savedScore.Detail,
     ); err != nil {
         return models.ExamGrades{}, fmt.Errorf("failed to insert exam score: %w", err)
     }
+    savedScore.Status = scoreStatus(exam)
+
+    // Nilai remedial dicatat pada ujian aslinya sesuai kebijakan remedial setelah dirilis
+    if err := syncExamScore(ctx, tx, savedScore.ID); err != nil {
+        return models.ExamGrades{}, fmt.Errorf("failed to update final grade: %w", err)
+    }
+
+    // Esai mode queued dinilai di latar belakang lalu nilai ujian diperbarui
+    if essayGrading == models.EssayGradingQueued && len(pendingEssays) > 0 {
+        if err := r.enqueueExamEssays(ctx, tx, savedScore, answerBytes, pendingEssays); err != nil {
+            return models.ExamGrades{}, fmt.Errorf("failed to queue essays: %w", err)
+        }
+    }
+
+    // ---------------------------------------------------
+    // 6️⃣ Tutup attempt berwaktu, jawaban tidak bisa diubah lagi
+    if err := finalizeExamAttempt(ctx, tx, req.Exam_ID, req.Student_ID); err != nil {
+        return models.ExamGrades{}, fmt.Errorf("failed to finalize exam attempt: %w", err)
+    }
 
     // ---------------------------------------------------
     // ✅ Return nilai yang berhasil disimpan
@@ -0,0 +1,206 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project-ppl-be/config"
+	"project-ppl-be/src/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Errors returned by CheckAttemptOpen
+var (
+	ErrAttemptNotStarted = errors.New("start the exam attempt before submitting answers")
+	ErrAttemptExpired    = errors.New("exam attempt deadline has passed")
+)
+
+const examAttemptColumns = "id, exam_id, student_id, started_at, deadline, finalized_at, status"
+
+func scanExamAttempt(row pgx.Row, a *models.ExamAttempt) error {
+	return row.Scan(&a.ID, &a.Exam_ID, &a.Student_ID, &a.Started_At, &a.Deadline, &a.Finalized_At, &a.Status)
+}
+
+// StartExamAttempt starts the student's timed attempt. The deadline is duration_minutes from now,
+// but never later than the exam end_time. Starting again returns the existing attempt.
+func (r *ExamRepository) StartExamAttempt(ctx context.Context, exam models.Exams, studentID int) (models.ExamAttempt, error) {
+	var attempt models.ExamAttempt
+	err := scanExamAttempt(config.DB.QueryRow(ctx, `
+		INSERT INTO exam_attempts (exam_id, student_id, status, deadline)
+		VALUES ($1, $2, $3, LEAST(NOW() + make_interval(mins => $4), $5))
+		ON CONFLICT (exam_id, student_id) DO NOTHING
+		RETURNING `+examAttemptColumns,
+		exam.ID, studentID, models.AttemptInProgress, exam.Duration_Minutes, exam.End_Time,
+	), &attempt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return r.GetExamAttempt(ctx, exam.ID, studentID)
+	}
+	if err != nil {
+		return models.ExamAttempt{}, err
+	}
+	return attempt, nil
+}
+
+// GetExamAttempt retrieves the student's attempt at an exam
+func (r *ExamRepository) GetExamAttempt(ctx context.Context, examID, studentID int) (models.ExamAttempt, error) {
+	var attempt models.ExamAttempt
+	err := scanExamAttempt(config.DB.QueryRow(ctx,
+		`SELECT `+examAttemptColumns+` FROM exam_attempts WHERE exam_id = $1 AND student_id = $2`,
+		examID, studentID,
+	), &attempt)
+	if err != nil {
+		return models.ExamAttempt{}, err
+	}
+	return attempt, nil
+}
+
+// CheckAttemptOpen returns ErrAttemptNotStarted or ErrAttemptExpired when the student may not write answers
+func (r *ExamRepository) CheckAttemptOpen(ctx context.Context, examID, studentID int) error {
+	attempt, err := r.GetExamAttempt(ctx, examID, studentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrAttemptNotStarted
+	}
+	if err != nil {
+		return err
+	}
+	if attempt.Status != models.AttemptInProgress || time.Now().After(attempt.Deadline) {
+		return ErrAttemptExpired
+	}
+	return nil
+}
+
+// FinalizeExpiredAttempts submits and grades the answers of every attempt whose deadline has passed and finalizes it,
+// then submits and grades the drafts of untimed exams whose submission window has closed. Each one is submitted in
+// the same transaction as its grading, so one that fails to grade is retried on the next run.
+// It returns the number of attempts and drafts finalized.
+func (r *ExamRepository) FinalizeExpiredAttempts(ctx context.Context) (int, error) {
+	expired, err := examStudents(ctx,
+		`SELECT exam_id, student_id FROM exam_attempts WHERE status = $1 AND deadline <= NOW() ORDER BY deadline`,
+		models.AttemptInProgress,
+	)
+	if err != nil {
+		return 0, err
+	}
+	closed, err := examStudents(ctx, `
+		SELECT a.exam_id, a.student_id
+		FROM exam_answers a
+		JOIN exams e ON e.id = a.exam_id
+		WHERE a.status IS DISTINCT FROM $1 AND e.duration_minutes = 0
+			AND e.end_time + make_interval(mins => e.late_grace_minutes) <= NOW()
+		ORDER BY e.end_time
+	`, models.AnswerStatusSubmitted)
+	if err != nil {
+		return 0, err
+	}
+
+	var finalized int
+	var errs []error
+	finalize := func(reqs []models.CalculateExamGrades, finalizeOne func(context.Context, int, int) (bool, error)) {
+		for _, req := range reqs {
+			done, err := finalizeOne(ctx, req.Exam_ID, req.Student_ID)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("exam %d student %d: %w", req.Exam_ID, req.Student_ID, err))
+			} else if done {
+				finalized++
+			}
+		}
+	}
+	finalize(expired, r.finalizeExpiredAttempt)
+	finalize(closed, r.finalizeClosedDraft)
+	return finalized, errors.Join(errs...)
+}
+
+// examStudents collects the exam_id and student_id pairs returned by query
+func examStudents(ctx context.Context, query string, args ...any) ([]models.CalculateExamGrades, error) {
+	rows, err := config.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.CalculateExamGrades, error) {
+		var req models.CalculateExamGrades
+		err := row.Scan(&req.Exam_ID, &req.Student_ID)
+		return req, err
+	})
+}
+
+// finalizeExpiredAttempt submits and grades the answers of one expired attempt and finalizes it.
+// It reports false when another run already took the attempt.
+func (r *ExamRepository) finalizeExpiredAttempt(ctx context.Context, examID, studentID int) (bool, error) {
+	essays, err := r.gradeExamEssays(ctx, examID, studentID)
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := config.DB.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	var id int
+	err = tx.QueryRow(ctx, `
+		SELECT id FROM exam_attempts
+		WHERE exam_id = $1 AND student_id = $2 AND status = $3 AND deadline <= NOW()
+		FOR UPDATE SKIP LOCKED
+	`, examID, studentID, models.AttemptInProgress).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	// Attempt tanpa jawaban atau yang sudah dikumpulkan tetap difinalisasi tanpa dinilai ulang
+	submit := models.SubmitExamAnswersRequest{Exam_ID: examID, Student_ID: studentID}
+	_, err = r.submitExamAnswers(ctx, tx, submit, 0, essays)
+	if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, ErrAnswersSubmitted) {
+		err = finalizeExamAttempt(ctx, tx, examID, studentID)
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+// finalizeClosedDraft submits and grades the draft of an untimed exam whose submission window has closed.
+// It reports false when the draft was submitted meanwhile or another run already took it.
+func (r *ExamRepository) finalizeClosedDraft(ctx context.Context, examID, studentID int) (bool, error) {
+	essays, err := r.gradeExamEssays(ctx, examID, studentID)
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := config.DB.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	var id int
+	err = tx.QueryRow(ctx, `
+		SELECT id FROM exam_answers
+		WHERE exam_id = $1 AND student_id = $2 AND status IS DISTINCT FROM $3
+		FOR UPDATE SKIP LOCKED
+	`, examID, studentID, models.AnswerStatusSubmitted).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	submit := models.SubmitExamAnswersRequest{Exam_ID: examID, Student_ID: studentID}
+	if _, err := r.submitExamAnswers(ctx, tx, submit, 0, essays); err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+func finalizeExamAttempt(ctx context.Context, q querier, examID, studentID int) error {
+	_, err := q.Exec(ctx, `
+		UPDATE exam_attempts
+		SET status = $1, finalized_at = NOW()
+		WHERE exam_id = $2 AND student_id = $3 AND status = $4
+	`, models.AttemptFinalized, examID, studentID, models.AttemptInProgress)
+	return err
+}
@@ -0,0 +1,142 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"project-ppl-be/config"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrEssaysChanged is returned when the essays changed between being graded and being submitted
+var ErrEssaysChanged = errors.New("essay answers changed while they were graded, submit again")
+
+// examEssay is an exam essay graded before the submission transaction, with the answer text it was graded on
+type examEssay struct {
+	answer string
+	grade  utils.EssayGrade
+}
+
+// gradeExamEssays grades the essays of the student's draft when the exam grades them automatically.
+// It runs before the submission transaction is opened, so the grader is never called while it holds locks.
+// It returns no grades when there is no draft to submit.
+func (r *ExamRepository) gradeExamEssays(ctx context.Context, examID, studentID int) (map[string]examEssay, error) {
+	var contentBytes, answerBytes []byte
+	var essayGrading string
+	err := config.DB.QueryRow(ctx, `
+		SELECT e.content, e.essay_grading, a.answers
+		FROM exams e
+		JOIN exam_answers a ON a.exam_id = e.id AND a.student_id = $2
+		WHERE e.id = $1 AND a.status IS DISTINCT FROM $3
+	`, examID, studentID, models.AnswerStatusSubmitted).Scan(&contentBytes, &essayGrading, &answerBytes)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if essayGrading != models.EssayGradingAuto {
+		return nil, nil
+	}
+
+	var content models.QuestionContent
+	if err := json.Unmarshal(contentBytes, &content); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal exam content: %w", err)
+	}
+	var answers map[string]any
+	if err := json.Unmarshal(answerBytes, &answers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal student answers: %w", err)
+	}
+
+	essays := make(map[string]examEssay)
+	for _, q := range content.Questions {
+		if q.Type != models.QuestionTypeEssay {
+			continue
+		}
+		answer := answers[q.Key()]
+		grade, err := utils.GradeEssay(ctx, essayRequest(q, answer))
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate essay: %w", err)
+		}
+		essays[q.Key()] = examEssay{answer: utils.AnswerText(answer), grade: grade}
+	}
+	return essays, nil
+}
+
+func essayGradingOrDefault(mode string) string {
+	if mode == "" {
+		return models.EssayGradingAuto
+	}
+	return mode
+}
+
+// examEssayResult is the result of a graded exam essay. Exams keep the grader's feedback in the detail,
+// so students read it with the released score.
+func examEssayResult(q models.Question, answer any, grade utils.EssayGrade) models.QuestionResult {
+	result := essayResult(q, answer, grade)
+	result.Feedback = grade.Feedback
+	return result
+}
+
+// enqueueExamEssays queues the pending essays of an exam score for background grading
+func (r *ExamRepository) enqueueExamEssays(ctx context.Context, q querier, score models.ExamGrades, answers []byte, keys []string) error {
+	var jobID int
+	if err := q.QueryRow(ctx, `
+		INSERT INTO grading_jobs (exam_score_id, student_id, answers, status)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, score.ID, score.Student_ID, answers, models.GradingJobQueued).Scan(&jobID); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if _, err := q.Exec(ctx,
+			`INSERT INTO grading_job_items (job_id, question_key, status) VALUES ($1, $2, $3)`,
+			jobID, key, models.GradingItemPending,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyQueuedEssays writes graded essays into an exam score and recomputes its total.
+// Essays a teacher graded in the meantime are left as they are.
+func (r *ExamRepository) applyQueuedEssays(ctx context.Context, tx pgx.Tx, scoreID int, content models.QuestionContent, answers map[string]any, essays map[string]utils.EssayGrade) error {
+	var detailBytes []byte
+	if err := tx.QueryRow(ctx, `SELECT detail FROM exam_scores WHERE id = $1 FOR UPDATE`, scoreID).Scan(&detailBytes); err != nil {
+		return err
+	}
+	var detail map[string]models.QuestionResult
+	if err := json.Unmarshal(detailBytes, &detail); err != nil {
+		return fmt.Errorf("failed to unmarshal exam score detail: %w", err)
+	}
+
+	for _, q := range content.Questions {
+		grade, graded := essays[q.Key()]
+		if !graded || !detail[q.Key()].Pending {
+			continue
+		}
+		detail[q.Key()] = examEssayResult(q, answers[q.Key()], grade)
+	}
+
+	var total float64
+	for _, result := range detail {
+		total += result.Score
+	}
+	detailBytes, err := json.Marshal(detail)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE exam_scores SET score = $2, detail = $3 WHERE id = $1`,
+		scoreID, math.Max(total, 0), detailBytes,
+	); err != nil {
+		return err
+	}
+	return syncExamScore(ctx, tx, scoreID)
+}
@@ -0,0 +1,132 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"project-ppl-be/config"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/utils"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrScoresNotReleased is returned when a student asks for a score the teacher has not released yet
+var ErrScoresNotReleased = errors.New("scores for this exam have not been released yet")
+
+// releaseColumns are the exam columns ScoresReleased needs, read from exams joined as e
+const releaseColumns = "e.release_policy, e.released_at, e.end_time, e.late_grace_minutes"
+
+// releasedCondition is ScoresReleased written in SQL for exams joined as e
+const releasedCondition = `(e.released_at IS NOT NULL OR (
+	e.release_policy IS DISTINCT FROM '` + models.ReleaseManual + `' AND (
+		e.release_policy IS DISTINCT FROM '` + models.ReleaseAfterClose + `'
+		OR NOW() >= e.end_time + make_interval(mins => e.late_grace_minutes))))`
+
+func releasePolicyOrDefault(policy string) string {
+	if policy == "" {
+		return models.ReleaseImmediate
+	}
+	return policy
+}
+
+// scoreStatus tells students whether the score of an exam is visible yet
+func scoreStatus(exam models.Exams) string {
+	if exam.ScoresReleased(time.Now()) {
+		return models.ScoreStatusReleased
+	}
+	return models.ScoreStatusSubmitted
+}
+
+// ReleaseExamScores makes the scores of an exam visible to students and counts them in their final grades.
+// Releasing again keeps the first release time.
+func (r *ExamRepository) ReleaseExamScores(ctx context.Context, examID int) (models.Exams, error) {
+	tx, err := config.DB.Begin(ctx)
+	if err != nil {
+		return models.Exams{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var exam models.Exams
+	err = scanExam(tx.QueryRow(ctx,
+		`UPDATE exams SET released_at = COALESCE(released_at, NOW()) WHERE id = $1 RETURNING `+strings.Join(examColumns, ", "),
+		examID,
+	), &exam)
+	if err != nil {
+		return models.Exams{}, err
+	}
+
+	// Nilai remedial yang baru dirilis menggantikan nilai ujian aslinya
+	if err := syncRemedialScores(ctx, tx, examID); err != nil {
+		return models.Exams{}, err
+	}
+	students, err := classStudents(ctx, tx, examStudentsQuery, examID)
+	if err != nil {
+		return models.Exams{}, err
+	}
+	if err := syncFinalGrades(ctx, tx, students); err != nil {
+		return models.Exams{}, err
+	}
+	return exam, tx.Commit(ctx)
+}
+
+// ReleaseClosedExamScores records the release of every after_close exam whose window has closed,
+// so the scores are counted in the final grades, and returns how many exams were released
+func (r *ExamRepository) ReleaseClosedExamScores(ctx context.Context) (int, error) {
+	rows, err := config.DB.Query(ctx, `
+		SELECT e.id FROM exams e
+		WHERE e.release_policy = $1 AND e.released_at IS NULL AND `+releasedCondition+`
+		ORDER BY e.id
+	`, models.ReleaseAfterClose)
+	if err != nil {
+		return 0, err
+	}
+	examIDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for _, examID := range examIDs {
+		if _, err := r.ReleaseExamScores(ctx, examID); err != nil {
+			return released, err
+		}
+		released++
+	}
+	return released, nil
+}
+
+// GetExamReview returns the student's latest graded attempt at an exam with the answer key.
+// Unless withUnreleased is set, it returns ErrScoresNotReleased until the scores are released.
+func (r *ExamRepository) GetExamReview(ctx context.Context, examID, studentID int, withUnreleased bool) (models.ExamReview, error) {
+	exam, err := r.GetExamByID(ctx, examID)
+	if err != nil {
+		return models.ExamReview{}, err
+	}
+	if !withUnreleased && !exam.ScoresReleased(time.Now()) {
+		return models.ExamReview{}, ErrScoresNotReleased
+	}
+
+	review := models.ExamReview{Exam_ID: examID, Student_ID: studentID}
+	var detailBytes []byte
+	if err := config.DB.QueryRow(ctx, `
+		SELECT id, COALESCE(score, 0), detail
+		FROM exam_scores
+		WHERE exam_id = $1 AND student_id = $2
+		ORDER BY id DESC
+		LIMIT 1
+	`, examID, studentID).Scan(&review.Score_ID, &review.Score, &detailBytes); err != nil {
+		return models.ExamReview{}, err
+	}
+
+	var detail map[string]models.QuestionResult
+	if detailBytes != nil {
+		if err := json.Unmarshal(detailBytes, &detail); err != nil {
+			return models.ExamReview{}, err
+		}
+	}
+	review.Questions = utils.ReviewQuestions(exam.Content, detail)
+	return review, nil
+}
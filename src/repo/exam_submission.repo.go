@@ -0,0 +1,116 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"project-ppl-be/config"
+	"project-ppl-be/src/models"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrAnswersSubmitted is returned when a student changes answers that were already submitted
+var ErrAnswersSubmitted = errors.New("answers have already been submitted")
+
+// AutosaveExamAnswer merges one answer into the student's draft without touching the other answers.
+// The draft is created on the first autosave.
+func (r *ExamRepository) AutosaveExamAnswer(ctx context.Context, req models.AutosaveExamAnswerRequest) (models.ExamAnswers, error) {
+	answer, err := json.Marshal(req.Answer)
+	if err != nil {
+		return models.ExamAnswers{}, err
+	}
+	key := strconv.Itoa(req.Question_Number)
+
+	var ex models.ExamAnswers
+	err = scanExamAnswer(config.DB.QueryRow(ctx, `
+		INSERT INTO exam_answers (exam_id, student_id, answers, status)
+		VALUES ($1, $2, jsonb_build_object($3::TEXT, $4::JSONB), $5)
+		ON CONFLICT (exam_id, student_id) DO UPDATE
+		SET answers = COALESCE(exam_answers.answers, '{}'::JSONB) || EXCLUDED.answers
+		WHERE exam_answers.status IS DISTINCT FROM $6
+		RETURNING `+strings.Join(examAnswerColumns, ", "),
+		req.Exam_ID, req.Student_ID, key, answer, models.AnswerStatusDraft, models.AnswerStatusSubmitted,
+	), &ex)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ExamAnswers{}, ErrAnswersSubmitted
+	}
+	return ex, err
+}
+
+// SubmitExamAnswers freezes the student's draft so it can no longer change, then grades it.
+// lateSeconds > 0 marks the submission as late.
+func (r *ExamRepository) SubmitExamAnswers(ctx context.Context, req models.SubmitExamAnswersRequest, lateSeconds int) (models.ExamGrades, error) {
+	essays, err := r.gradeExamEssays(ctx, req.Exam_ID, req.Student_ID)
+	if err != nil {
+		return models.ExamGrades{}, err
+	}
+
+	tx, err := config.DB.Begin(ctx)
+	if err != nil {
+		return models.ExamGrades{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	grades, err := r.submitExamAnswers(ctx, tx, req, lateSeconds, essays)
+	if err != nil {
+		return models.ExamGrades{}, err
+	}
+	return grades, tx.Commit(ctx)
+}
+
+// submitExamAnswers freezes and grades the student's draft in tx, so the answers stay a draft when grading fails.
+// essays are the auto graded essays from gradeExamEssays.
+func (r *ExamRepository) submitExamAnswers(ctx context.Context, tx pgx.Tx, req models.SubmitExamAnswersRequest, lateSeconds int, essays map[string]examEssay) (models.ExamGrades, error) {
+	tag, err := tx.Exec(ctx, `
+		UPDATE exam_answers
+		SET status = $1, submitted_at = NOW(), is_late = $2, late_seconds = $3
+		WHERE exam_id = $4 AND student_id = $5 AND status IS DISTINCT FROM $1
+	`, models.AnswerStatusSubmitted, lateSeconds > 0, lateSeconds, req.Exam_ID, req.Student_ID)
+	if err != nil {
+		return models.ExamGrades{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		if err := r.ensureNotSubmitted(ctx, tx, req.Exam_ID, req.Student_ID); err != nil {
+			return models.ExamGrades{}, err
+		}
+		return models.ExamGrades{}, pgx.ErrNoRows
+	}
+
+	return r.calculateExamGrades(ctx, tx, models.CalculateExamGrades{Exam_ID: req.Exam_ID, Student_ID: req.Student_ID}, essays)
+}
+
+// ensureNotSubmitted returns ErrAnswersSubmitted when the student's answers are already submitted
+func (r *ExamRepository) ensureNotSubmitted(ctx context.Context, q querier, examID, studentID int) error {
+	var submitted bool
+	err := q.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM exam_answers WHERE exam_id = $1 AND student_id = $2 AND status = $3)`,
+		examID, studentID, models.AnswerStatusSubmitted,
+	).Scan(&submitted)
+	if err != nil {
+		return err
+	}
+	if submitted {
+		return ErrAnswersSubmitted
+	}
+	return nil
+}
+
+// examAnswerLocked explains why an exam answer could not be changed: ErrAnswersSubmitted when it is submitted,
+// pgx.ErrNoRows when it does not exist
+func (r *ExamRepository) examAnswerLocked(ctx context.Context, id int) error {
+	var submitted bool
+	err := config.DB.QueryRow(ctx,
+		`SELECT status IS NOT DISTINCT FROM $2 FROM exam_answers WHERE id = $1`,
+		id, models.AnswerStatusSubmitted,
+	).Scan(&submitted)
+	if err != nil {
+		return err
+	}
+	if submitted {
+		return ErrAnswersSubmitted
+	}
+	return pgx.ErrNoRows
+}
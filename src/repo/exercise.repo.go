@@ -3,24 +3,54 @@ package repo
 import (
     "context"
     "encoding/json"
-    "strconv"
-    "strings"
+    "errors"
 		"fmt"
+    "strings"
 		utils "project-ppl-be/src/utils"
 
     "project-ppl-be/config"
     "project-ppl-be/src/models"
 
     "github.com/huandu/go-sqlbuilder"
+    "github.com/jackc/pgx/v5"
 )
 
 
 type ExerciseRepository struct{}
 
+// Errors returned when a student submits an exercise attempt
+var (
+    ErrNoNewAnswers       = errors.New("answers have not changed since the last attempt")
+    ErrMaxAttemptsReached = errors.New("maximum number of attempts reached")
+    ErrGradingInProgress  = errors.New("answers are already being graded")
+)
+
+// exerciseColumns is the column order read by scanExercise
+var exerciseColumns = []string{"id", "material_id", "title", "content", "total_marks", "teacher_id", "max_attempts", "score_policy"}
+
+func scanExercise(row pgx.Row, ex *models.Exercises) error {
+    return row.Scan(&ex.ID, &ex.Material_ID, &ex.Title, &ex.Content, &ex.Total_Marks, &ex.Teacher_ID, &ex.Max_Attempts, &ex.Score_Policy)
+}
+
+// GetExerciseByID retrieves a single exercise
+func (r *ExerciseRepository) GetExerciseByID(ctx context.Context, id int) (models.Exercises, error) {
+    sb := sqlbuilder.NewSelectBuilder()
+    sb.Select(exerciseColumns...).
+        From("exercises").
+        Where(sb.Equal("id", id))
+
+    query, args := sb.BuildWithFlavor(sqlbuilder.PostgreSQL)
+    var ex models.Exercises
+    if err := scanExercise(config.DB.QueryRow(ctx, query, args...), &ex); err != nil {
+        return models.Exercises{}, err
+    }
+    return ex, nil
+}
+
 // Get by class_id
 func (r *ExerciseRepository) GetExercisesByMaterialID(ctx context.Context, materialID int) ([]models.Exercises, error) {
     sb := sqlbuilder.NewSelectBuilder()
-    sb.Select("id", "material_id", "title", "content", "total_marks", "teacher_id").
+    sb.Select(exerciseColumns...).
         From("exercises").
         Where(sb.Equal("material_id", materialID))
 
@@ -34,7 +64,7 @@ func (r *ExerciseRepository) GetExercisesByMaterialID(ctx context.Context, mater
     var list []models.Exercises
     for rows.Next() {
         var ex models.Exercises
-        if err := rows.Scan(&ex.ID, &ex.Material_ID, &ex.Title, &ex.Content, &ex.Total_Marks, &ex.Teacher_ID); err != nil {
+        if err := scanExercise(rows, &ex); err != nil {
             return nil, err
         }
         list = append(list, ex)
@@ -44,7 +74,7 @@ func (r *ExerciseRepository) GetExercisesByMaterialID(ctx context.Context, mater
 
 func (r *ExerciseRepository) GetExercisesByMaterialIDForStudent(ctx context.Context, materialID int, number int) ([]models.Exercises, error) {
     sb := sqlbuilder.NewSelectBuilder()
-    sb.Select("id", "material_id", "title", "content", "total_marks", "teacher_id").
+    sb.Select(exerciseColumns...).
         From("exercises").
         Where(sb.Equal("material_id", materialID))
 
@@ -52,56 +82,45 @@ func (r *ExerciseRepository) GetExercisesByMaterialIDForStudent(ctx context.Cont
     row := config.DB.QueryRow(ctx, query, args...)
 
     var ex models.Exercises
-    var contentBytes []byte
-
-    if err := row.Scan(&ex.ID, &ex.Material_ID, &ex.Title, &contentBytes, &ex.Total_Marks, &ex.Teacher_ID); err != nil {
-        return nil, err
-    }
-
-    // Unmarshal content (JSON) ke map
-    var fullContent map[string]string
-    if err := json.Unmarshal(contentBytes, &fullContent); err != nil {
+    if err := scanExercise(row, &ex); err != nil {
         return nil, err
     }
 
-    // Konversi number ke string
-    numberStr := strconv.Itoa(number)
-
-    // Filter content yang sesuai nomor soal dan bukan _answer
-    filtered := make(map[string]string)
-    for key, val := range fullContent {
-        if (key == numberStr || strings.HasPrefix(key, numberStr+"_")) && !strings.HasSuffix(key, "_answer") {
-            filtered[key] = val
-        }
-    }
-
-    // Assign content hasil filter ke ex.Content
-    ex.Content = filtered
+    // Ambil hanya soal dengan nomor yang diminta, tanpa kunci jawaban
+    ex.Content = utils.StripAnswerKeys(ex.Content.Only(number))
 
     return []models.Exercises{ex}, nil
 }
 
-// Create
+// CreateExercise inserts the exercise and records the bank questions it uses
 func (r *ExerciseRepository) CreateExercise(ctx context.Context, req models.CreateExercisesRequest) (models.Exercises, error) {
     ib := sqlbuilder.NewInsertBuilder()
     ib.InsertInto("exercises").
-        Cols("material_id", "title", "content", "total_marks", "teacher_id").
-        Values(req.Material_ID, req.Title, req.Content, req.Total_Marks, req.Teacher_ID).
-        Returning("id", "material_id", "title", "content", "total_marks", "teacher_id")
+        Cols("material_id", "title", "content", "total_marks", "teacher_id", "max_attempts", "score_policy").
+        Values(req.Material_ID, req.Title, req.Content, req.Total_Marks, req.Teacher_ID, req.Max_Attempts, scorePolicyOrDefault(req.Score_Policy)).
+        Returning(exerciseColumns...)
 
     query, args := ib.BuildWithFlavor(sqlbuilder.PostgreSQL)
-    var ex models.Exercises
-    err := config.DB.QueryRow(ctx, query, args...).Scan(
-        &ex.ID, &ex.Material_ID, &ex.Title, &ex.Content, &ex.Total_Marks, &ex.Teacher_ID,
-    )
+
+    tx, err := config.DB.Begin(ctx)
     if err != nil {
         return models.Exercises{}, err
     }
-    return ex, nil
+    defer tx.Rollback(ctx)
+
+    var ex models.Exercises
+    if err := scanExercise(tx.QueryRow(ctx, query, args...), &ex); err != nil {
+        return models.Exercises{}, err
+    }
+    if err := syncUsages(ctx, tx, "exercise_id", ex.ID, ex.Content); err != nil {
+        return models.Exercises{}, err
+    }
+    return ex, tx.Commit(ctx)
 }
 
-// Update
-func (r *ExerciseRepository) UpdateExercise(ctx context.Context, id int, req models.CreateExercisesRequest) (models.Exercises, error) {
+// UpdateExercise saves the exercise and records the bank questions it uses. With regradeBy set the stored
+// scores are regraded against the new content in the same transaction.
+func (r *ExerciseRepository) UpdateExercise(ctx context.Context, id int, req models.CreateExercisesRequest, regradeBy *int) (models.Exercises, error) {
     ub := sqlbuilder.NewUpdateBuilder()
     ub.Update("exercises").
         Set(
@@ -110,29 +129,41 @@ func (r *ExerciseRepository) UpdateExercise(ctx context.Context, id int, req mod
             ub.Assign("content", req.Content),
             ub.Assign("total_marks", req.Total_Marks),
             ub.Assign("teacher_id", req.Teacher_ID),
+            ub.Assign("max_attempts", req.Max_Attempts),
+            ub.Assign("score_policy", scorePolicyOrDefault(req.Score_Policy)),
         ).
         Where(ub.Equal("id", id))
 
     query, args := ub.BuildWithFlavor(sqlbuilder.PostgreSQL)
-    query += " RETURNING id, material_id, title, content, total_marks, teacher_id"
+    query += " RETURNING " + strings.Join(exerciseColumns, ", ")
 
-    var ex models.Exercises
-    err := config.DB.QueryRow(ctx, query, args...).Scan(
-        &ex.ID, &ex.Material_ID, &ex.Title, &ex.Content, &ex.Total_Marks, &ex.Teacher_ID,
-    )
+    tx, err := config.DB.Begin(ctx)
     if err != nil {
         return models.Exercises{}, err
     }
-    return ex, nil
+    defer tx.Rollback(ctx)
+
+    var ex models.Exercises
+    if err := scanExercise(tx.QueryRow(ctx, query, args...), &ex); err != nil {
+        return models.Exercises{}, err
+    }
+    if err := syncUsages(ctx, tx, "exercise_id", ex.ID, ex.Content); err != nil {
+        return models.Exercises{}, err
+    }
+    if regradeBy != nil {
+        if _, _, err := regradeScores(ctx, tx, exerciseScoreTable, "exercise_id", ex.ID, ex.Content, 0, regradeBy); err != nil {
+            return models.Exercises{}, err
+        }
+    }
+    return ex, tx.Commit(ctx)
 }
 
-// Delete
+// DeleteExercise deletes an exercise and recomputes the final grades its scores counted in
 func (r *ExerciseRepository) DeleteExercise(ctx context.Context, id int) error {
     db := sqlbuilder.NewDeleteBuilder()
     db.DeleteFrom("exercises").Where(db.Equal("id", id))
     query, args := db.BuildWithFlavor(sqlbuilder.PostgreSQL)
-    _, err := config.DB.Exec(ctx, query, args...)
-    return err
+    return deleteScored(ctx, query, args, exerciseStudentsQuery+` WHERE e.id = $1`, id)
 }
 
 func (r *ExerciseRepository) GetExerciseAnswers(ctx context.Context, exerciseID int, studentID int) ([]models.ExerciseAnswers, error) {
@@ -186,6 +217,7 @@ func (r *ExerciseRepository) UpdateExerciseAnswers(ctx context.Context, id int,
             ub.Assign("exercise_id", req.Exercise_ID),
             ub.Assign("answers", req.Answers),
             ub.Assign("student_id", req.Student_ID),
+            ub.Assign("status", "Active"),
         ).
         Where(ub.Equal("id", id))
 
@@ -211,171 +243,204 @@ func (r *ExerciseRepository) DeleteExerciseAnswers(ctx context.Context, id int)
     return err
 }
 
-func (r *ExerciseRepository) GetExerciseGrades(ctx context.Context, exerciseID int, studentID int) ([]models.ExerciseGrades, error) {
-	sb := sqlbuilder.NewSelectBuilder()
-	sb.Select("id", "student_id", "exercise_id", "score", "detail").
-			From("exercise_scores").
-			Where(sb.Equal("exercise_id", exerciseID)).
-			Where(sb.Equal("student_id", studentID)).
-			OrderBy("id DESC").
-			Limit(1)
-
-    query, args := sb.BuildWithFlavor(sqlbuilder.PostgreSQL)
-    rows, err := config.DB.Query(ctx, query, args...)
+func (r *ExerciseRepository) CalculateExerciseGrades(ctx context.Context, req models.CalculateExerciseGrades) (models.ExerciseGrades, error) {
+    sub, err := r.loadSubmission(ctx, req)
     if err != nil {
-        return nil, err
+        return models.ExerciseGrades{}, err
     }
-    defer rows.Close()
 
-    var list []models.ExerciseGrades
-    for rows.Next() {
-        var ex models.ExerciseGrades
-        if err := rows.Scan(&ex.ID, &ex.Student_ID, &ex.Exercise_ID, &ex.Score, &ex.Detail); err != nil {
-            return nil, err
+    // ---------------------------------------------------
+    // 4️⃣ Grade the essays, then calculate total score and build detail
+    essays := make(map[string]utils.EssayGrade)
+    for _, q := range sub.content.Questions {
+        if q.Type != models.QuestionTypeEssay {
+            continue
         }
-        list = append(list, ex)
+        grade, err := utils.GradeEssay(ctx, essayRequest(q, sub.answers[q.Key()]))
+        if err != nil {
+            return models.ExerciseGrades{}, fmt.Errorf("failed to evaluate essay: %w", err)
+        }
+        essays[q.Key()] = grade
     }
-    return list, rows.Err()
-}
+    totalScore, detail := scoreExercise(sub.content, sub.answers, essays)
 
-func (r *ExerciseRepository) GetAllExerciseGrades(ctx context.Context, studentID int) ([]models.ExerciseGrades, error) {
-	sb := sqlbuilder.NewSelectBuilder()
-	sb.Select("id", "student_id", "exercise_id", "score", "detail").
-			From("exercise_scores").
-			Where(sb.Equal("student_id", studentID))
+    // ---------------------------------------------------
+    // 5️⃣ Save the attempt, its score and detail, and close the answers in one transaction
+    tx, err := config.DB.Begin(ctx)
+    if err != nil {
+        return models.ExerciseGrades{}, err
+    }
+    defer tx.Rollback(ctx)
 
-    query, args := sb.BuildWithFlavor(sqlbuilder.PostgreSQL)
-    rows, err := config.DB.Query(ctx, query, args...)
+    savedScore, err := r.saveExerciseScore(ctx, tx, req, sub.answerBytes, sub.maxAttempts, totalScore, detail, essays, "")
     if err != nil {
-        return nil, err
+        return models.ExerciseGrades{}, err
     }
-    defer rows.Close()
 
-    var list []models.ExerciseGrades
-    for rows.Next() {
-        var ex models.ExerciseGrades
-        if err := rows.Scan(&ex.ID, &ex.Student_ID, &ex.Exercise_ID, &ex.Score, &ex.Detail); err != nil {
-            return nil, err
-        }
-        list = append(list, ex)
+    if err := tx.Commit(ctx); err != nil {
+        return models.ExerciseGrades{}, err
     }
-    return list, rows.Err()
+
+    // ---------------------------------------------------
+    // ✅ Done
+    return savedScore, nil
 }
 
-func (r *ExerciseRepository) CalculateExerciseGrades(ctx context.Context, req models.CalculateExerciseGrades) (models.ExerciseGrades, error) {
+// exerciseSubmission holds a student's ungraded answers and the exercise they answer
+type exerciseSubmission struct {
+    answers     map[string]any
+    answerBytes []byte
+    content     models.QuestionContent
+    maxAttempts int
+}
+
+// loadSubmission reads the answers and the exercise, and checks that the student may submit another attempt
+func (r *ExerciseRepository) loadSubmission(ctx context.Context, req models.CalculateExerciseGrades) (exerciseSubmission, error) {
+    var sub exerciseSubmission
+
     // ---------------------------------------------------
     // 1️⃣ Get student answers
     sbAns := sqlbuilder.NewSelectBuilder()
-    sbAns.Select("answers").
+    sbAns.Select("answers", "COALESCE(status, '')").
         From("exercise_answers").
         Where(sbAns.Equal("exercise_id", req.Exercise_ID)).
         Where(sbAns.Equal("student_id", req.Student_ID))
     queryAns, argsAns := sbAns.BuildWithFlavor(sqlbuilder.PostgreSQL)
 
-    var answerBytes []byte
-    if err := config.DB.QueryRow(ctx, queryAns, argsAns...).Scan(&answerBytes); err != nil {
-        return models.ExerciseGrades{}, fmt.Errorf("failed to get student answers: %w", err)
+    var answerStatus string
+    if err := config.DB.QueryRow(ctx, queryAns, argsAns...).Scan(&sub.answerBytes, &answerStatus); err != nil {
+        return sub, fmt.Errorf("failed to get student answers: %w", err)
     }
 
-    var studentAnswers map[string]string
-    if err := json.Unmarshal(answerBytes, &studentAnswers); err != nil {
-        return models.ExerciseGrades{}, fmt.Errorf("failed to unmarshal student answers: %w", err)
+    // Jawaban yang sudah dinilai harus diubah dulu sebelum attempt berikutnya
+    if answerStatus == "Inactive" {
+        return sub, ErrNoNewAnswers
+    }
+    if answerStatus == "Grading" {
+        return sub, ErrGradingInProgress
+    }
+
+    if err := json.Unmarshal(sub.answerBytes, &sub.answers); err != nil {
+        return sub, fmt.Errorf("failed to unmarshal student answers: %w", err)
     }
 
     // ---------------------------------------------------
-    // 2️⃣ Get exercise content and total marks
+    // 2️⃣ Get exercise content
     sbEx := sqlbuilder.NewSelectBuilder()
-    sbEx.Select("content", "total_marks").
+    sbEx.Select("content", "max_attempts").
         From("exercises").
         Where(sbEx.Equal("id", req.Exercise_ID))
     queryEx, argsEx := sbEx.BuildWithFlavor(sqlbuilder.PostgreSQL)
 
     var contentBytes []byte
-    var totalMarks int
-    if err := config.DB.QueryRow(ctx, queryEx, argsEx...).Scan(&contentBytes, &totalMarks); err != nil {
-        return models.ExerciseGrades{}, fmt.Errorf("failed to get exercise data: %w", err)
+    if err := config.DB.QueryRow(ctx, queryEx, argsEx...).Scan(&contentBytes, &sub.maxAttempts); err != nil {
+        return sub, fmt.Errorf("failed to get exercise data: %w", err)
     }
 
-    var fullContent map[string]string
-    if err := json.Unmarshal(contentBytes, &fullContent); err != nil {
-        return models.ExerciseGrades{}, fmt.Errorf("failed to unmarshal exercise content: %w", err)
+    // Check the attempt limit before grading essays
+    used, err := r.countAttempts(ctx, req.Exercise_ID, req.Student_ID)
+    if err != nil {
+        return sub, fmt.Errorf("failed to count attempts: %w", err)
+    }
+    if sub.maxAttempts > 0 && used >= sub.maxAttempts {
+        return sub, ErrMaxAttemptsReached
+    }
+
+    if err := json.Unmarshal(contentBytes, &sub.content); err != nil {
+        return sub, fmt.Errorf("failed to unmarshal exercise content: %w", err)
     }
 
     // ---------------------------------------------------
     // 3️⃣ Identify questions
-    correctAnswerKeys := make(map[string]string)
-    essayQuestions := make([]string, 0)
-
-    for key, val := range fullContent {
-        if strings.HasSuffix(key, "_answer") {
-            soalNumber := strings.TrimSuffix(key, "_answer")
-            correctAnswerKeys[soalNumber] = val
-        } else if strings.HasSuffix(key, "_essay") {
-            soalNumber := strings.TrimSuffix(key, "_essay")
-            essayQuestions = append(essayQuestions, soalNumber)
-        }
+    if len(sub.content.Questions) == 0 {
+        return sub, fmt.Errorf("no questions found for exercise id %d", req.Exercise_ID)
     }
+    return sub, nil
+}
 
-    totalQuestions := len(correctAnswerKeys) + len(essayQuestions)
-    if totalQuestions == 0 {
-        return models.ExerciseGrades{}, fmt.Errorf("no questions found for exercise id %d", req.Exercise_ID)
-    }
+// scoreExercise adds up the points of every question. Each question is worth its weight in points,
+// essays take the score, justification and rubric criteria of their grade in essays.
+func scoreExercise(content models.QuestionContent, answers map[string]any, essays map[string]utils.EssayGrade) (float64, map[string]models.QuestionResult) {
+    var totalScore float64
+    detail := make(map[string]models.QuestionResult)
 
-    marksPerQuestion := float64(totalMarks) / float64(totalQuestions)
+    for _, q := range content.Questions {
+        answer := answers[q.Key()]
 
-    // ---------------------------------------------------
-    // 4️⃣ Calculate total score and build detail
-    var totalScore float64
-    detail := make(map[string]interface{})
-
-    // MCQs
-    for soalNumber, correctAnswer := range correctAnswerKeys {
-        if studentAnswers[soalNumber] == correctAnswer {
-            totalScore += marksPerQuestion
-            detail[soalNumber] = true
-        } else {
-            detail[soalNumber] = false
+        if q.Type == models.QuestionTypeEssay {
+            result := essayResult(q, answer, essays[q.Key()])
+            totalScore += result.Score
+            detail[q.Key()] = result
+            continue
         }
+
+        result := utils.ScoreQuestion(q, answer, 0)
+        totalScore += result.Score
+        detail[q.Key()] = result
     }
+    return totalScore, detail
+}
 
-    // Essays
-    for _, soalNumber := range essayQuestions {
-        essayKey := soalNumber + "_essay"
-        questionText := fullContent[essayKey]
-        essayScore, err := utils.EvaluateEssayWithGemini(questionText, studentAnswers[soalNumber], marksPerQuestion)
-        if err != nil {
-            return models.ExerciseGrades{}, fmt.Errorf("failed to evaluate essay: %w", err)
-        }
-        totalScore += essayScore
-        detail[soalNumber] = fmt.Sprintf("%.2f/%.2f", essayScore, marksPerQuestion)
+// essayResult is the detail of a graded essay
+func essayResult(q models.Question, answer any, grade utils.EssayGrade) models.QuestionResult {
+    return models.QuestionResult{
+        Type: q.Type, Score: grade.Score, Max_Score: q.Weight, Correct: grade.Score == q.Weight, Answer: answer,
+        Justification: grade.Justification, Criteria: grade.Criteria,
+    }
+}
+
+func essayRequest(q models.Question, answer any) utils.EssayRequest {
+    return utils.EssayRequest{
+        Question:  q.Prompt,
+        Answer:    utils.AnswerText(answer),
+        MaxScore:  q.Weight,
+        Reference: q.Correct_Answer,
+        Keywords:  q.Keywords,
+        Rubric:    q.Rubric,
     }
+}
 
-    // ---------------------------------------------------
-    // 5️⃣ Save score and detail
+// saveExerciseScore stores the attempt, its score and the essay feedback, then marks the answers Inactive.
+// With answerStatus set only answers still in that status are marked, so answers changed since are kept open.
+func (r *ExerciseRepository) saveExerciseScore(ctx context.Context, tx pgx.Tx, req models.CalculateExerciseGrades, answerBytes []byte, maxAttempts int, totalScore float64, detail map[string]models.QuestionResult, essays map[string]utils.EssayGrade, answerStatus string) (models.ExerciseGrades, error) {
     detailBytes, err := json.Marshal(detail)
     if err != nil {
         return models.ExerciseGrades{}, fmt.Errorf("failed to marshal detail: %w", err)
     }
 
+    attemptID, err := r.insertAttempt(ctx, tx, req, answerBytes, maxAttempts)
+    if err != nil {
+        return models.ExerciseGrades{}, err
+    }
+
     ib := sqlbuilder.NewInsertBuilder()
     ib.InsertInto("exercise_scores").
-        Cols("student_id", "exercise_id", "score", "detail").
-        Values(req.Student_ID, req.Exercise_ID, totalScore, detailBytes).
-        Returning("id", "student_id", "exercise_id", "score", "detail")
+        Cols("student_id", "exercise_id", "score", "detail", "attempt_id").
+        Values(req.Student_ID, req.Exercise_ID, totalScore, detailBytes, attemptID).
+        Returning("id", "student_id", "exercise_id", "score", "detail", "attempt_id")
 
     queryInsert, argsInsert := ib.BuildWithFlavor(sqlbuilder.PostgreSQL)
 
     var savedScore models.ExerciseGrades
-    if err := config.DB.QueryRow(ctx, queryInsert, argsInsert...).Scan(
+    if err := tx.QueryRow(ctx, queryInsert, argsInsert...).Scan(
         &savedScore.ID,
         &savedScore.Student_ID,
         &savedScore.Exercise_ID,
         &savedScore.Score,
         &savedScore.Detail,
+        &savedScore.Attempt_ID,
     ); err != nil {
         return models.ExerciseGrades{}, fmt.Errorf("failed to insert exercise score: %w", err)
     }
 
+    if err := r.saveEssayFeedback(ctx, tx, savedScore.ID, essays); err != nil {
+        return models.ExerciseGrades{}, fmt.Errorf("failed to save essay feedback: %w", err)
+    }
+
+    if err := syncExerciseScoreFinalGrade(ctx, tx, savedScore.ID); err != nil {
+        return models.ExerciseGrades{}, fmt.Errorf("failed to update final grade: %w", err)
+    }
+
     // ---------------------------------------------------
     // 6️⃣ Mark the answer status as Inactive
     ub := sqlbuilder.NewUpdateBuilder()
@@ -383,14 +448,14 @@ func (r *ExerciseRepository) CalculateExerciseGrades(ctx context.Context, req mo
         Set(ub.Assign("status", "Inactive")).
         Where(ub.Equal("exercise_id", req.Exercise_ID)).
         Where(ub.Equal("student_id", req.Student_ID))
+    if answerStatus != "" {
+        ub.Where(ub.Equal("status", answerStatus))
+    }
 
     queryUpdate, argsUpdate := ub.BuildWithFlavor(sqlbuilder.PostgreSQL)
 
-    if _, err := config.DB.Exec(ctx, queryUpdate, argsUpdate...); err != nil {
+    if _, err := tx.Exec(ctx, queryUpdate, argsUpdate...); err != nil {
         return models.ExerciseGrades{}, fmt.Errorf("failed to update exercise_answer status: %w", err)
     }
-
-    // ---------------------------------------------------
-    // ✅ Done
     return savedScore, nil
 }
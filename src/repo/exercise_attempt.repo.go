@@ -0,0 +1,120 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"project-ppl-be/config"
+	"project-ppl-be/src/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// policyScoresQuery picks one score row per exercise following the exercise's score policy.
+// For the average policy the latest attempt is returned with the average score of all attempts.
+const policyScoresQuery = `
+	SELECT DISTINCT ON (s.exercise_id)
+		s.id, s.student_id, s.exercise_id,
+		CASE WHEN e.score_policy = 'average'
+			THEN AVG(s.score) OVER (PARTITION BY s.exercise_id)
+			ELSE s.score
+		END,
+		s.detail, s.attempt_id
+	FROM exercise_scores s
+	JOIN exercises e ON e.id = s.exercise_id
+	WHERE s.student_id = $1 AND ($2::INT = 0 OR s.exercise_id = $2)
+	ORDER BY s.exercise_id,
+		CASE WHEN e.score_policy = 'highest' THEN s.score END DESC NULLS LAST,
+		s.id DESC
+`
+
+// GetExerciseAttempts lists a student's attempts at an exercise with the score of each attempt
+func (r *ExerciseRepository) GetExerciseAttempts(ctx context.Context, exerciseID, studentID int) ([]models.ExerciseAttempt, error) {
+	rows, err := config.DB.Query(ctx, `
+		SELECT a.id, a.exercise_id, a.student_id, a.attempt_number, a.answers, a.submitted_at, s.score, s.detail
+		FROM exercise_attempts a
+		LEFT JOIN exercise_scores s ON s.attempt_id = a.id
+		WHERE a.exercise_id = $1 AND a.student_id = $2
+		ORDER BY a.attempt_number
+	`, exerciseID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := []models.ExerciseAttempt{}
+	for rows.Next() {
+		var a models.ExerciseAttempt
+		if err := rows.Scan(&a.ID, &a.Exercise_ID, &a.Student_ID, &a.Attempt_Number, &a.Answers, &a.Submitted_At, &a.Score, &a.Detail); err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
+
+// GetExerciseGrades returns the student's grade for an exercise following its score policy
+func (r *ExerciseRepository) GetExerciseGrades(ctx context.Context, exerciseID int, studentID int) ([]models.ExerciseGrades, error) {
+	return r.policyScores(ctx, studentID, exerciseID)
+}
+
+// GetAllExerciseGrades returns the student's grade for every exercise they attempted, following each score policy
+func (r *ExerciseRepository) GetAllExerciseGrades(ctx context.Context, studentID int) ([]models.ExerciseGrades, error) {
+	return r.policyScores(ctx, studentID, 0)
+}
+
+func (r *ExerciseRepository) policyScores(ctx context.Context, studentID, exerciseID int) ([]models.ExerciseGrades, error) {
+	rows, err := config.DB.Query(ctx, policyScoresQuery, studentID, exerciseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []models.ExerciseGrades
+	for rows.Next() {
+		var ex models.ExerciseGrades
+		if err := rows.Scan(&ex.ID, &ex.Student_ID, &ex.Exercise_ID, &ex.Score, &ex.Detail, &ex.Attempt_ID); err != nil {
+			return nil, err
+		}
+		list = append(list, ex)
+	}
+	return list, rows.Err()
+}
+
+func (r *ExerciseRepository) countAttempts(ctx context.Context, exerciseID, studentID int) (int, error) {
+	var count int
+	err := config.DB.QueryRow(ctx,
+		`SELECT COUNT(*) FROM exercise_attempts WHERE exercise_id = $1 AND student_id = $2`,
+		exerciseID, studentID,
+	).Scan(&count)
+	return count, err
+}
+
+// insertAttempt stores the next numbered attempt with a snapshot of the answers.
+// It holds a transaction lock on the student's attempts at the exercise, so concurrent submissions are numbered
+// one after the other and the attempt limit, checked again in the insert, cannot be exceeded.
+func (r *ExerciseRepository) insertAttempt(ctx context.Context, tx pgx.Tx, req models.CalculateExerciseGrades, answers []byte, maxAttempts int) (int, error) {
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1::INT, $2::INT)`, req.Exercise_ID, req.Student_ID); err != nil {
+		return 0, err
+	}
+
+	var attemptID int
+	err := tx.QueryRow(ctx, `
+		INSERT INTO exercise_attempts (exercise_id, student_id, attempt_number, answers)
+		SELECT $1::INT, $2::INT, COALESCE(MAX(attempt_number), 0) + 1, $3::JSONB
+		FROM exercise_attempts
+		WHERE exercise_id = $1 AND student_id = $2
+		HAVING $4 = 0 OR COUNT(*) < $4
+		RETURNING id
+	`, req.Exercise_ID, req.Student_ID, answers, maxAttempts).Scan(&attemptID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrMaxAttemptsReached
+	}
+	return attemptID, err
+}
+
+func scorePolicyOrDefault(policy string) string {
+	if policy == "" {
+		return models.ScorePolicyLatest
+	}
+	return policy
+}
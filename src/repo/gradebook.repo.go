@@ -0,0 +1,310 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"project-ppl-be/config"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type GradebookRepository struct{}
+
+// querier runs queries on the pool or inside a transaction, so final grades are updated
+// in the same transaction that writes the score
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+const finalGradeColumns = "g.class_id, g.student_id, st.name, g.exercise_average, g.exam_average, g.final_grade, g.updated_at"
+
+func scanFinalGrade(row pgx.Row, g *models.FinalGrade) error {
+	return row.Scan(&g.Class_ID, &g.Student_ID, &g.Name, &g.Exercise_Average, &g.Exam_Average, &g.Final_Grade, &g.Updated_At)
+}
+
+// GetGradebookSettings returns the gradebook settings of a class, or the defaults when it has none
+func (r *GradebookRepository) GetGradebookSettings(ctx context.Context, classID int) (models.GradebookSettings, error) {
+	return gradebookSettings(ctx, config.DB, classID)
+}
+
+// UpdateGradebookSettings saves the gradebook settings of a class and recomputes the final grade of its students
+func (r *GradebookRepository) UpdateGradebookSettings(ctx context.Context, req models.UpdateGradebookSettingsRequest) (models.GradebookSettings, error) {
+	tx, err := config.DB.Begin(ctx)
+	if err != nil {
+		return models.GradebookSettings{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	settings := models.GradebookSettings{Class_ID: req.Class_ID}
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO gradebook_settings (class_id, exercise_weight, exam_weight, drop_lowest_exercises, drop_lowest_exams, kkm)
+		VALUES ($1, $2, $3, $4, $5, COALESCE($6, $7))
+		ON CONFLICT (class_id) DO UPDATE
+		SET exercise_weight = EXCLUDED.exercise_weight, exam_weight = EXCLUDED.exam_weight,
+			drop_lowest_exercises = EXCLUDED.drop_lowest_exercises, drop_lowest_exams = EXCLUDED.drop_lowest_exams,
+			kkm = COALESCE($6, gradebook_settings.kkm), updated_at = NOW()
+		RETURNING exercise_weight, exam_weight, drop_lowest_exercises, drop_lowest_exams, kkm
+	`, req.Class_ID, req.Exercise_Weight, req.Exam_Weight, req.Drop_Lowest_Exercises, req.Drop_Lowest_Exams, req.KKM, models.DefaultKKM).Scan(
+		&settings.Exercise_Weight, &settings.Exam_Weight, &settings.Drop_Lowest_Exercises, &settings.Drop_Lowest_Exams, &settings.KKM,
+	); err != nil {
+		return models.GradebookSettings{}, err
+	}
+
+	rows, err := tx.Query(ctx, `SELECT student_id FROM assigned_students_class WHERE class_id = $1`, req.Class_ID)
+	if err != nil {
+		return models.GradebookSettings{}, err
+	}
+	studentIDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return models.GradebookSettings{}, err
+	}
+	for _, studentID := range studentIDs {
+		if err := syncFinalGrade(ctx, tx, req.Class_ID, studentID); err != nil {
+			return models.GradebookSettings{}, err
+		}
+	}
+
+	return settings, tx.Commit(ctx)
+}
+
+// GetGradebook returns the settings of a class with the final grade of every student that has one
+func (r *GradebookRepository) GetGradebook(ctx context.Context, classID int) (models.Gradebook, error) {
+	settings, err := gradebookSettings(ctx, config.DB, classID)
+	if err != nil {
+		return models.Gradebook{}, err
+	}
+
+	rows, err := config.DB.Query(ctx, `
+		SELECT `+finalGradeColumns+`
+		FROM class_final_grades g
+		JOIN students st ON st.id = g.student_id
+		WHERE g.class_id = $1
+		ORDER BY st.name, g.student_id
+	`, classID)
+	if err != nil {
+		return models.Gradebook{}, err
+	}
+	defer rows.Close()
+
+	gradebook := models.Gradebook{Settings: settings, Students: []models.FinalGrade{}}
+	for rows.Next() {
+		var g models.FinalGrade
+		if err := scanFinalGrade(rows, &g); err != nil {
+			return models.Gradebook{}, err
+		}
+		gradebook.Students = append(gradebook.Students, g)
+	}
+	return gradebook, rows.Err()
+}
+
+// GetFinalGrade returns a student's running final grade in a class
+func (r *GradebookRepository) GetFinalGrade(ctx context.Context, classID, studentID int) (models.FinalGrade, error) {
+	var g models.FinalGrade
+	err := scanFinalGrade(config.DB.QueryRow(ctx, `
+		SELECT `+finalGradeColumns+`
+		FROM class_final_grades g
+		JOIN students st ON st.id = g.student_id
+		WHERE g.class_id = $1 AND g.student_id = $2
+	`, classID, studentID), &g)
+	if err != nil {
+		return models.FinalGrade{}, err
+	}
+	return g, nil
+}
+
+func gradebookSettings(ctx context.Context, q querier, classID int) (models.GradebookSettings, error) {
+	settings := models.GradebookSettings{Class_ID: classID}
+	err := q.QueryRow(ctx, `
+		SELECT exercise_weight, exam_weight, drop_lowest_exercises, drop_lowest_exams, kkm
+		FROM gradebook_settings
+		WHERE class_id = $1
+	`, classID).Scan(&settings.Exercise_Weight, &settings.Exam_Weight, &settings.Drop_Lowest_Exercises, &settings.Drop_Lowest_Exams, &settings.KKM)
+	if errors.Is(err, pgx.ErrNoRows) {
+		settings.Exercise_Weight = models.DefaultExerciseWeight
+		settings.Exam_Weight = models.DefaultExamWeight
+		settings.KKM = models.DefaultKKM
+		return settings, nil
+	}
+	return settings, err
+}
+
+// syncExamScoreFinalGrade updates the final grade of the student and class an exam score belongs to
+func syncExamScoreFinalGrade(ctx context.Context, q querier, scoreID int) error {
+	var classID, studentID int
+	if err := q.QueryRow(ctx, `
+		SELECT e.class_id, s.student_id
+		FROM exam_scores s
+		JOIN exams e ON e.id = s.exam_id
+		WHERE s.id = $1
+	`, scoreID).Scan(&classID, &studentID); err != nil {
+		return err
+	}
+	return syncFinalGrade(ctx, q, classID, studentID)
+}
+
+// syncExerciseScoreFinalGrade updates the final grade of the student and class an exercise score belongs to
+func syncExerciseScoreFinalGrade(ctx context.Context, q querier, scoreID int) error {
+	var classID, studentID int
+	if err := q.QueryRow(ctx, `
+		SELECT m.class_id, s.student_id
+		FROM exercise_scores s
+		JOIN exercises e ON e.id = s.exercise_id
+		JOIN materials m ON m.id = e.material_id
+		WHERE s.id = $1
+	`, scoreID).Scan(&classID, &studentID); err != nil {
+		return err
+	}
+	return syncFinalGrade(ctx, q, classID, studentID)
+}
+
+// syncFinalGrade recomputes a student's final grade in a class and their curr_score,
+// the rounded average of their final grades across classes.
+// Exercises count with the score their score policy picks, exams with the latest score once it is released,
+// so students never work out a withheld score from their final grade.
+// Remedial exams count through the score they record on the original exam.
+func syncFinalGrade(ctx context.Context, q querier, classID, studentID int) error {
+	settings, err := gradebookSettings(ctx, q, classID)
+	if err != nil {
+		return err
+	}
+
+	exercisePercents, err := scorePercents(ctx, q, `
+		SELECT p.score, e.total_marks
+		FROM (`+policyScoresQuery+`) AS p (id, student_id, exercise_id, score, detail, attempt_id)
+		JOIN exercises e ON e.id = p.exercise_id
+		JOIN materials m ON m.id = e.material_id
+		WHERE m.class_id = $3
+	`, studentID, 0, classID)
+	if err != nil {
+		return err
+	}
+
+	examPercents, err := scorePercents(ctx, q, `
+		SELECT DISTINCT ON (s.exam_id) s.score, e.total_marks
+		FROM exam_scores s
+		JOIN exams e ON e.id = s.exam_id
+		WHERE e.class_id = $1 AND s.student_id = $2 AND e.remedial_of IS NULL AND `+releasedCondition+`
+		ORDER BY s.exam_id, s.id DESC
+	`, classID, studentID)
+	if err != nil {
+		return err
+	}
+
+	exerciseAverage := utils.CategoryAverage(exercisePercents, settings.Drop_Lowest_Exercises)
+	examAverage := utils.CategoryAverage(examPercents, settings.Drop_Lowest_Exams)
+	if _, err := q.Exec(ctx, `
+		INSERT INTO class_final_grades (class_id, student_id, exercise_average, exam_average, final_grade)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (class_id, student_id) DO UPDATE
+		SET exercise_average = EXCLUDED.exercise_average, exam_average = EXCLUDED.exam_average,
+			final_grade = EXCLUDED.final_grade, updated_at = NOW()
+	`, classID, studentID, exerciseAverage, examAverage, utils.WeightedFinalGrade(settings, exerciseAverage, examAverage)); err != nil {
+		return err
+	}
+
+	return syncCurrentScore(ctx, q, studentID)
+}
+
+// syncCurrentScore sets a student's curr_score to the rounded average of their final grades across classes
+func syncCurrentScore(ctx context.Context, q querier, studentID int) error {
+	_, err := q.Exec(ctx, `
+		UPDATE students
+		SET curr_score = (SELECT ROUND(AVG(final_grade))::INT FROM class_final_grades WHERE student_id = $1)
+		WHERE id = $1
+	`, studentID)
+	return err
+}
+
+// classStudent is a student whose final grade in a class has to be recomputed
+type classStudent struct {
+	classID, studentID int
+}
+
+// classStudents reads the class_id and student_id pairs a query returns
+func classStudents(ctx context.Context, q querier, query string, args ...any) ([]classStudent, error) {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (classStudent, error) {
+		var cs classStudent
+		err := row.Scan(&cs.classID, &cs.studentID)
+		return cs, err
+	})
+}
+
+// syncFinalGrades recomputes the final grade of every student in their class
+func syncFinalGrades(ctx context.Context, q querier, list []classStudent) error {
+	for _, cs := range list {
+		if err := syncFinalGrade(ctx, q, cs.classID, cs.studentID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// examStudentsQuery returns the class and student of every score of exam $1 and its remedial exams
+const examStudentsQuery = `
+	SELECT DISTINCT e.class_id, s.student_id
+	FROM exam_scores s
+	JOIN exams e ON e.id = s.exam_id
+	WHERE e.id = $1 OR e.remedial_of = $1
+`
+
+// scorePercents reads score and total_marks pairs and returns each score as a percentage.
+// Scores still being graded are left out.
+func scorePercents(ctx context.Context, q querier, query string, args ...any) ([]float64, error) {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var percents []float64
+	for rows.Next() {
+		var score *float64
+		var totalMarks int
+		if err := rows.Scan(&score, &totalMarks); err != nil {
+			return nil, err
+		}
+		if score != nil {
+			percents = append(percents, utils.Percent(*score, totalMarks))
+		}
+	}
+	return percents, rows.Err()
+}
+
+// exerciseStudentsQuery returns the class and student of exercise scores, filtered by the WHERE clause appended to it
+const exerciseStudentsQuery = `
+	SELECT DISTINCT m.class_id, s.student_id
+	FROM exercise_scores s
+	JOIN exercises e ON e.id = s.exercise_id
+	JOIN materials m ON m.id = e.material_id
+`
+
+// deleteScored runs a delete that removes scores and recomputes the final grades of the students
+// studentsQuery returns for id, all in one transaction
+func deleteScored(ctx context.Context, query string, args []any, studentsQuery string, id int) error {
+	tx, err := config.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	students, err := classStudents(ctx, tx, studentsQuery, id)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		return err
+	}
+	if err := syncFinalGrades(ctx, tx, students); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,362 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"project-ppl-be/config"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/utils"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// gradingWorkers is the number of essays graded at the same time
+	gradingWorkers = 4
+	// gradingBatchSize is the number of items claimed per run
+	gradingBatchSize = 20
+	// maxGradingAttempts is how many times an item is tried before it fails the job
+	maxGradingAttempts = 3
+	// gradingBackoff is the wait after an item's first failure, doubled after every failure after that
+	gradingBackoff = 30 * time.Second
+	// gradingLockTimeout releases items of a worker that stopped before recording a result
+	gradingLockTimeout = 10 * time.Minute
+)
+
+// EnqueueExerciseGrading queues the student's answers for grading and returns the job.
+// Every essay becomes a job item; the answers stay marked as Grading until the job finishes.
+func (r *ExerciseRepository) EnqueueExerciseGrading(ctx context.Context, req models.CalculateExerciseGrades) (models.GradingJob, error) {
+	sub, err := r.loadSubmission(ctx, req)
+	if err != nil {
+		return models.GradingJob{}, err
+	}
+
+	tx, err := config.DB.Begin(ctx)
+	if err != nil {
+		return models.GradingJob{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Menandai jawaban lebih dulu supaya dua permintaan bersamaan tidak membuat dua job
+	tag, err := tx.Exec(ctx, `
+		UPDATE exercise_answers SET status = 'Grading'
+		WHERE exercise_id = $1 AND student_id = $2 AND status IS DISTINCT FROM 'Grading' AND status IS DISTINCT FROM 'Inactive'
+	`, req.Exercise_ID, req.Student_ID)
+	if err != nil {
+		return models.GradingJob{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return models.GradingJob{}, ErrGradingInProgress
+	}
+
+	var jobID int
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO grading_jobs (exercise_id, student_id, answers, status)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, req.Exercise_ID, req.Student_ID, sub.answerBytes, models.GradingJobQueued).Scan(&jobID); err != nil {
+		return models.GradingJob{}, fmt.Errorf("failed to create grading job: %w", err)
+	}
+
+	for _, q := range sub.content.Questions {
+		if q.Type != models.QuestionTypeEssay {
+			continue
+		}
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO grading_job_items (job_id, question_key, status) VALUES ($1, $2, $3)`,
+			jobID, q.Key(), models.GradingItemPending,
+		); err != nil {
+			return models.GradingJob{}, fmt.Errorf("failed to create grading job item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.GradingJob{}, err
+	}
+	return r.GetGradingJob(ctx, jobID)
+}
+
+// GetGradingJob returns a grading job with the progress of its items and, once completed, the exercise or exam grade
+func (r *ExerciseRepository) GetGradingJob(ctx context.Context, id int) (models.GradingJob, error) {
+	var job models.GradingJob
+	var scoreID *int
+	err := config.DB.QueryRow(ctx, `
+		SELECT j.id, j.exercise_id, j.exam_score_id, j.student_id, j.status, j.error, j.created_at, j.completed_at, j.score_id,
+			COUNT(i.id),
+			COUNT(i.id) FILTER (WHERE i.status = $2),
+			COUNT(i.id) FILTER (WHERE i.status = $3)
+		FROM grading_jobs j
+		LEFT JOIN grading_job_items i ON i.job_id = j.id
+		WHERE j.id = $1
+		GROUP BY j.id
+	`, id, models.GradingItemDone, models.GradingItemFailed).Scan(
+		&job.ID, &job.Exercise_ID, &job.Exam_Score_ID, &job.Student_ID, &job.Status, &job.Error, &job.Created_At, &job.Completed_At, &scoreID,
+		&job.Total_Items, &job.Done_Items, &job.Failed_Items,
+	)
+	if err != nil {
+		return models.GradingJob{}, err
+	}
+
+	if scoreID != nil {
+		var grade models.ExerciseGrades
+		if err := config.DB.QueryRow(ctx,
+			`SELECT id, student_id, exercise_id, score, detail, attempt_id FROM exercise_scores WHERE id = $1`,
+			*scoreID,
+		).Scan(&grade.ID, &grade.Student_ID, &grade.Exercise_ID, &grade.Score, &grade.Detail, &grade.Attempt_ID); err != nil {
+			return models.GradingJob{}, err
+		}
+		job.Grade = &grade
+	}
+
+	if job.Exam_Score_ID != nil && job.Status == models.GradingJobCompleted {
+		var grade models.ExamGrades
+		var exam models.Exams
+		if err := config.DB.QueryRow(ctx,
+			`SELECT s.id, s.student_id, s.exam_id, s.score, s.detail, `+releaseColumns+` FROM exam_scores s JOIN exams e ON e.id = s.exam_id WHERE s.id = $1`,
+			*job.Exam_Score_ID,
+		).Scan(&grade.ID, &grade.Student_ID, &grade.Exam_ID, &grade.Score, &grade.Detail,
+			&exam.Release_Policy, &exam.Released_At, &exam.End_Time, &exam.Late_Grace_Minutes); err != nil {
+			return models.GradingJob{}, err
+		}
+		grade.Status = scoreStatus(exam)
+		job.Exam_Grade = &grade
+	}
+	return job, nil
+}
+
+// gradingQueue keeps the essays of grading jobs in grading_job_items. Items are claimed with SKIP LOCKED,
+// so several runs may work side by side.
+type gradingQueue struct {
+	r *ExerciseRepository
+}
+
+// ProcessGradingJobs grades claimed essay items with a bounded worker pool, then saves the grade of
+// every job whose items are all done. Failed items are retried with backoff up to maxGradingAttempts.
+// It returns the number of items graded.
+func (r *ExerciseRepository) ProcessGradingJobs(ctx context.Context) (int, error) {
+	return utils.ProcessGradingQueue(ctx, gradingQueue{r: r}, utils.GradeEssay, utils.GradingQueueConfig{
+		Workers:     gradingWorkers,
+		BatchSize:   gradingBatchSize,
+		MaxAttempts: maxGradingAttempts,
+		Backoff:     gradingBackoff,
+	})
+}
+
+func (q gradingQueue) Claim(ctx context.Context, limit int) ([]utils.GradingTask, error) {
+	rows, err := config.DB.Query(ctx, `
+		WITH claimed AS (
+			UPDATE grading_job_items
+			SET status = $1, attempts = attempts + 1, locked_at = NOW()
+			WHERE id IN (
+				SELECT id FROM grading_job_items
+				WHERE (status = $2 AND next_attempt_at <= NOW())
+					OR (status = $1 AND locked_at < NOW() - make_interval(secs => $3))
+				ORDER BY id
+				LIMIT $4
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, job_id, question_key, attempts
+		), started AS (
+			UPDATE grading_jobs SET status = $5
+			WHERE status = $6 AND id IN (SELECT job_id FROM claimed)
+		)
+		SELECT c.id, c.question_key, c.attempts, j.answers, COALESCE(e.content, x.content)
+		FROM claimed c
+		JOIN grading_jobs j ON j.id = c.job_id
+		LEFT JOIN exercises e ON e.id = j.exercise_id
+		LEFT JOIN exam_scores es ON es.id = j.exam_score_id
+		LEFT JOIN exams x ON x.id = es.exam_id
+	`, models.GradingItemRunning, models.GradingItemPending, gradingLockTimeout.Seconds(), limit,
+		models.GradingJobRunning, models.GradingJobQueued)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []utils.GradingTask
+	for rows.Next() {
+		var task utils.GradingTask
+		var key string
+		var answerBytes, contentBytes []byte
+		if err := rows.Scan(&task.ID, &key, &task.Attempts, &answerBytes, &contentBytes); err != nil {
+			return nil, err
+		}
+		var answers map[string]any
+		if err := json.Unmarshal(answerBytes, &answers); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal job answers: %w", err)
+		}
+		var content models.QuestionContent
+		if err := json.Unmarshal(contentBytes, &content); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal job content: %w", err)
+		}
+
+		task.Err = errors.New("question no longer exists")
+		for _, question := range content.Questions {
+			if question.Key() == key {
+				task.Essay, task.Err = essayRequest(question, answers[key]), nil
+				break
+			}
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+func (q gradingQueue) Done(ctx context.Context, task utils.GradingTask, grade utils.EssayGrade) error {
+	criteria, err := json.Marshal(grade.Criteria)
+	if err != nil {
+		return err
+	}
+	_, err = config.DB.Exec(ctx, `
+		UPDATE grading_job_items
+		SET status = $2, score = $3, justification = $4, criteria = $5, feedback = $6, error = NULL, locked_at = NULL
+		WHERE id = $1
+	`, task.ID, models.GradingItemDone, grade.Score, grade.Justification, criteria, grade.Feedback)
+	return err
+}
+
+func (q gradingQueue) Retry(ctx context.Context, task utils.GradingTask, cause error, status string, after time.Duration) error {
+	_, err := config.DB.Exec(ctx, `
+		UPDATE grading_job_items
+		SET status = $2, error = $3, locked_at = NULL, next_attempt_at = NOW() + make_interval(secs => $4)
+		WHERE id = $1
+	`, task.ID, status, cause.Error(), after.Seconds())
+	return err
+}
+
+func (q gradingQueue) CompleteJobs(ctx context.Context) error {
+	return q.r.completeGradingJobs(ctx)
+}
+
+// completeGradingJobs saves the grade of every open job whose items are all graded,
+// and fails the jobs with an item that ran out of attempts
+func (r *ExerciseRepository) completeGradingJobs(ctx context.Context) error {
+	for {
+		done, err := r.completeNextGradingJob(ctx)
+		if err != nil || !done {
+			return err
+		}
+	}
+}
+
+// completeNextGradingJob finishes one job and reports whether there was one to finish
+func (r *ExerciseRepository) completeNextGradingJob(ctx context.Context) (bool, error) {
+	tx, err := config.DB.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	var req models.CalculateExerciseGrades
+	var jobID, maxAttempts int
+	var exerciseID, examScoreID *int
+	var answerBytes, contentBytes []byte
+	err = tx.QueryRow(ctx, `
+		SELECT j.id, j.exercise_id, j.exam_score_id, j.student_id, j.answers,
+			COALESCE(e.content, x.content), COALESCE(e.max_attempts, 0)
+		FROM grading_jobs j
+		LEFT JOIN exercises e ON e.id = j.exercise_id
+		LEFT JOIN exam_scores es ON es.id = j.exam_score_id
+		LEFT JOIN exams x ON x.id = es.exam_id
+		WHERE j.status IN ($1, $2) AND NOT EXISTS (
+			SELECT 1 FROM grading_job_items i WHERE i.job_id = j.id AND i.status IN ($3, $4)
+		)
+		ORDER BY j.id
+		LIMIT 1
+		FOR UPDATE OF j SKIP LOCKED
+	`, models.GradingJobQueued, models.GradingJobRunning, models.GradingItemPending, models.GradingItemRunning).Scan(
+		&jobID, &exerciseID, &examScoreID, &req.Student_ID, &answerBytes, &contentBytes, &maxAttempts,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var answers map[string]any
+	if err := json.Unmarshal(answerBytes, &answers); err != nil {
+		return false, fmt.Errorf("failed to unmarshal job answers: %w", err)
+	}
+	var content models.QuestionContent
+	if err := json.Unmarshal(contentBytes, &content); err != nil {
+		return false, fmt.Errorf("failed to unmarshal job content: %w", err)
+	}
+	if exerciseID != nil {
+		req.Exercise_ID = *exerciseID
+	}
+
+	rows, err := tx.Query(ctx,
+		`SELECT question_key, status, COALESCE(score, 0), COALESCE(justification, ''), criteria, COALESCE(feedback, ''), COALESCE(error, '') FROM grading_job_items WHERE job_id = $1`,
+		jobID,
+	)
+	if err != nil {
+		return false, err
+	}
+	var items []utils.GradedItem
+	for rows.Next() {
+		var item utils.GradedItem
+		var criteria []byte
+		if err := rows.Scan(&item.Key, &item.Status, &item.Grade.Score, &item.Grade.Justification, &criteria, &item.Grade.Feedback, &item.Error); err != nil {
+			rows.Close()
+			return false, err
+		}
+		if criteria != nil {
+			if err := json.Unmarshal(criteria, &item.Grade.Criteria); err != nil {
+				rows.Close()
+				return false, fmt.Errorf("failed to unmarshal rubric scores: %w", err)
+			}
+		}
+		items = append(items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+	essays, failure := utils.CollectGradedEssays(items)
+
+	var scoreID *int
+	if examScoreID != nil {
+		// Esai ujian yang gagal dinilai tetap pending supaya guru dapat menilainya
+		exams := ExamRepository{}
+		if err := exams.applyQueuedEssays(ctx, tx, *examScoreID, content, answers, essays); err != nil {
+			return false, err
+		}
+	} else if failure == nil {
+		totalScore, detail := scoreExercise(content, answers, essays)
+		var saved models.ExerciseGrades
+		saved, failure = r.saveExerciseScore(ctx, tx, req, answerBytes, maxAttempts, totalScore, detail, essays, "Grading")
+		if failure != nil && !errors.Is(failure, ErrMaxAttemptsReached) {
+			return false, failure
+		}
+		scoreID = &saved.ID
+	}
+
+	if failure != nil {
+		// Jawaban dibuka kembali supaya siswa dapat mengirim ulang
+		if exerciseID != nil {
+			if _, err := tx.Exec(ctx,
+				`UPDATE exercise_answers SET status = 'Active' WHERE exercise_id = $1 AND student_id = $2 AND status = 'Grading'`,
+				req.Exercise_ID, req.Student_ID,
+			); err != nil {
+				return false, err
+			}
+		}
+		if _, err := tx.Exec(ctx,
+			`UPDATE grading_jobs SET status = $2, error = $3, completed_at = NOW() WHERE id = $1`,
+			jobID, models.GradingJobFailed, failure.Error(),
+		); err != nil {
+			return false, err
+		}
+	} else if _, err := tx.Exec(ctx,
+		`UPDATE grading_jobs SET status = $2, score_id = $3, completed_at = NOW() WHERE id = $1`,
+		jobID, models.GradingJobCompleted, scoreID,
+	); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
@@ -0,0 +1,70 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"project-ppl-be/config"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/utils"
+)
+
+// GetExamItemAnalysis computes the item statistics of an exam from each student's latest score
+func (r *ExamRepository) GetExamItemAnalysis(ctx context.Context, examID int) (models.ItemAnalysis, error) {
+	exam, err := r.GetExamByID(ctx, examID)
+	if err != nil {
+		return models.ItemAnalysis{}, err
+	}
+
+	attempts, err := gradedDetails(ctx, `
+		SELECT DISTINCT ON (student_id) COALESCE(score, 0), detail
+		FROM exam_scores
+		WHERE exam_id = $1 AND detail IS NOT NULL
+		ORDER BY student_id, id DESC
+	`, examID)
+	if err != nil {
+		return models.ItemAnalysis{}, err
+	}
+	return utils.AnalyzeItems(exam.Content, attempts), nil
+}
+
+// GetExerciseItemAnalysis computes the item statistics of an exercise from each student's first attempt,
+// since later attempts are made after seeing the results
+func (r *ExerciseRepository) GetExerciseItemAnalysis(ctx context.Context, exerciseID int) (models.ItemAnalysis, error) {
+	exercise, err := r.GetExerciseByID(ctx, exerciseID)
+	if err != nil {
+		return models.ItemAnalysis{}, err
+	}
+
+	attempts, err := gradedDetails(ctx, `
+		SELECT DISTINCT ON (student_id) COALESCE(score, 0), detail
+		FROM exercise_scores
+		WHERE exercise_id = $1 AND detail IS NOT NULL
+		ORDER BY student_id, id
+	`, exerciseID)
+	if err != nil {
+		return models.ItemAnalysis{}, err
+	}
+	return utils.AnalyzeItems(exercise.Content, attempts), nil
+}
+
+func gradedDetails(ctx context.Context, query string, args ...any) ([]models.GradedDetail, error) {
+	rows, err := config.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attempts []models.GradedDetail
+	for rows.Next() {
+		var attempt models.GradedDetail
+		var detailBytes []byte
+		if err := rows.Scan(&attempt.Score, &detailBytes); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(detailBytes, &attempt.Detail); err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, attempt)
+	}
+	return attempts, rows.Err()
+}
@@ -12,14 +12,18 @@ import (
 // StudentRepository struct
 type MaterialRepository struct{}
 
-// GetAllMaterials retrieves all materials from the database
-func (r *MaterialRepository) GetAllMaterials(ctx context.Context, page, pageSize int) ([]models.Material, int, error) {
+// GetAllMaterials retrieves all materials from the database, limited to one teacher's classes when teacherID is set
+func (r *MaterialRepository) GetAllMaterials(ctx context.Context, page, pageSize, teacherID int) ([]models.Material, int, error) {
 	sb := sqlbuilder.NewSelectBuilder()
 	sb.Select("id", "class_id", "title", "description", "content", "teacher_id").
 		From("materials").
 		Limit(pageSize).
 		Offset((page - 1) * pageSize) // OFFSET = (page - 1) * pageSize
 
+	if teacherID > 0 {
+		sb.Where("class_id IN (SELECT id FROM classes WHERE teacher_id = " + sb.Var(teacherID) + ")")
+	}
+
 	query, args := sb.BuildWithFlavor(sqlbuilder.PostgreSQL)
 	rows, err := config.DB.Query(ctx, query, args...)
 	if err != nil {
@@ -38,11 +42,11 @@ func (r *MaterialRepository) GetAllMaterials(ctx context.Context, page, pageSize
 	}
 
 	// Hitung total jumlah data untuk pagination
-	countQuery := "SELECT COUNT(*) FROM materials"
+	countQuery := "SELECT COUNT(*) FROM materials WHERE $1 = 0 OR class_id IN (SELECT id FROM classes WHERE teacher_id = $1)"
 
 	var total int
 
-	err = config.DB.QueryRow(ctx, countQuery).Scan(&total)
+	err = config.DB.QueryRow(ctx, countQuery, teacherID).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -167,10 +171,6 @@ func (r *MaterialRepository) DeleteMaterial(ctx context.Context, id int) error {
 	sb.DeleteFrom("materials").Where(sb.Equal("id", id))
 	query, args := sb.BuildWithFlavor(sqlbuilder.PostgreSQL)
 
-	_, err := config.DB.Exec(ctx, query, args...)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	// Nilai latihan di materi ini tidak lagi dihitung di nilai akhir
+	return deleteScored(ctx, query, args, exerciseStudentsQuery+` WHERE m.id = $1`, id)
 }
@@ -0,0 +1,236 @@
+package repo
+
+import (
+	"context"
+	"project-ppl-be/config"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/utils"
+
+	"github.com/huandu/go-sqlbuilder"
+)
+
+// ErrBankQuestionNotFound is returned when an exam or exercise references a missing bank question
+var ErrBankQuestionNotFound = utils.ErrBankQuestionNotFound
+
+// QuestionBankRepository struct
+type QuestionBankRepository struct{}
+
+var bankQuestionColumns = []string{"id", "teacher_id", "subject", "grade", "topic", "difficulty", "tags", "question", "created_at", "updated_at"}
+
+// GetBankQuestions retrieves bank questions matching the filter with pagination
+func (r *QuestionBankRepository) GetBankQuestions(ctx context.Context, page, pageSize int, filter models.BankQuestionFilter) ([]models.BankQuestion, int, error) {
+	sb := sqlbuilder.NewSelectBuilder()
+	sb.Select(bankQuestionColumns...).
+		From("question_bank").
+		OrderBy("id").Desc().
+		Limit(pageSize).
+		Offset((page - 1) * pageSize)
+
+	cb := sqlbuilder.NewSelectBuilder()
+	cb.Select("COUNT(*)").From("question_bank")
+
+	for _, b := range []*sqlbuilder.SelectBuilder{sb, cb} {
+		if filter.Subject != "" {
+			b.Where(b.Equal("subject", filter.Subject))
+		}
+		if filter.Grade > 0 {
+			b.Where(b.Equal("grade", filter.Grade))
+		}
+		if filter.Topic != "" {
+			b.Where(b.ILike("topic", "%"+filter.Topic+"%"))
+		}
+		if filter.Difficulty != "" {
+			b.Where(b.Equal("difficulty", filter.Difficulty))
+		}
+		if filter.Tag != "" {
+			b.Where(b.Var(filter.Tag) + " = ANY(tags)")
+		}
+	}
+
+	query, args := sb.BuildWithFlavor(sqlbuilder.PostgreSQL)
+	rows, err := config.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	questions := []models.BankQuestion{}
+	for rows.Next() {
+		var q models.BankQuestion
+		err := rows.Scan(&q.ID, &q.Teacher_ID, &q.Subject, &q.Grade, &q.Topic, &q.Difficulty, &q.Tags, &q.Question, &q.Created_At, &q.Updated_At)
+		if err != nil {
+			return nil, 0, err
+		}
+		questions = append(questions, q)
+	}
+
+	// Hitung total jumlah data untuk pagination
+	countQuery, countArgs := cb.BuildWithFlavor(sqlbuilder.PostgreSQL)
+	var total int
+	if err := config.DB.QueryRow(ctx, countQuery, countArgs...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	return questions, total, nil
+}
+
+// GetBankQuestionByID retrieves a single bank question
+func (r *QuestionBankRepository) GetBankQuestionByID(ctx context.Context, id int) (models.BankQuestion, error) {
+	sb := sqlbuilder.NewSelectBuilder()
+	sb.Select(bankQuestionColumns...).
+		From("question_bank").
+		Where(sb.Equal("id", id))
+
+	query, args := sb.BuildWithFlavor(sqlbuilder.PostgreSQL)
+	var q models.BankQuestion
+	err := config.DB.QueryRow(ctx, query, args...).Scan(
+		&q.ID, &q.Teacher_ID, &q.Subject, &q.Grade, &q.Topic, &q.Difficulty, &q.Tags, &q.Question, &q.Created_At, &q.Updated_At,
+	)
+	if err != nil {
+		return models.BankQuestion{}, err
+	}
+	return q, nil
+}
+
+// CreateBankQuestion inserts a new question into the bank
+func (r *QuestionBankRepository) CreateBankQuestion(ctx context.Context, req models.CreateBankQuestionRequest) (models.BankQuestion, error) {
+	ib := sqlbuilder.NewInsertBuilder()
+	ib.InsertInto("question_bank").
+		Cols("teacher_id", "subject", "grade", "topic", "difficulty", "tags", "question").
+		Values(req.Teacher_ID, req.Subject, req.Grade, req.Topic, req.Difficulty, tagsOrEmpty(req.Tags), req.Question).
+		Returning(bankQuestionColumns...)
+
+	query, args := ib.BuildWithFlavor(sqlbuilder.PostgreSQL)
+	var q models.BankQuestion
+	err := config.DB.QueryRow(ctx, query, args...).Scan(
+		&q.ID, &q.Teacher_ID, &q.Subject, &q.Grade, &q.Topic, &q.Difficulty, &q.Tags, &q.Question, &q.Created_At, &q.Updated_At,
+	)
+	if err != nil {
+		return models.BankQuestion{}, err
+	}
+	return q, nil
+}
+
+// UpdateBankQuestion updates a bank question. Exams and exercises that already use it keep their copy.
+func (r *QuestionBankRepository) UpdateBankQuestion(ctx context.Context, id int, req models.CreateBankQuestionRequest) (models.BankQuestion, error) {
+	ub := sqlbuilder.NewUpdateBuilder()
+	ub.Update("question_bank").
+		Set(
+			ub.Assign("teacher_id", req.Teacher_ID),
+			ub.Assign("subject", req.Subject),
+			ub.Assign("grade", req.Grade),
+			ub.Assign("topic", req.Topic),
+			ub.Assign("difficulty", req.Difficulty),
+			ub.Assign("tags", tagsOrEmpty(req.Tags)),
+			ub.Assign("question", req.Question),
+			"updated_at = NOW()",
+		).
+		Where(ub.Equal("id", id))
+
+	query, args := ub.BuildWithFlavor(sqlbuilder.PostgreSQL)
+	query += " RETURNING id, teacher_id, subject, grade, topic, difficulty, tags, question, created_at, updated_at"
+
+	var q models.BankQuestion
+	err := config.DB.QueryRow(ctx, query, args...).Scan(
+		&q.ID, &q.Teacher_ID, &q.Subject, &q.Grade, &q.Topic, &q.Difficulty, &q.Tags, &q.Question, &q.Created_At, &q.Updated_At,
+	)
+	if err != nil {
+		return models.BankQuestion{}, err
+	}
+	return q, nil
+}
+
+// DeleteBankQuestion deletes a bank question and its usage history
+func (r *QuestionBankRepository) DeleteBankQuestion(ctx context.Context, id int) error {
+	_, err := config.DB.Exec(ctx, `DELETE FROM question_bank WHERE id = $1`, id)
+	return err
+}
+
+// AppendBankQuestions copies the referenced bank questions to the end of content, numbering them after
+// the highest existing question number. Each copy keeps the ID of its bank question for the usage history.
+func (r *QuestionBankRepository) AppendBankQuestions(ctx context.Context, content *models.QuestionContent, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := config.DB.Query(ctx, `SELECT id, question FROM question_bank WHERE id = ANY($1)`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	bank := make(map[int]models.Question, len(ids))
+	for rows.Next() {
+		var id int
+		var q models.Question
+		if err := rows.Scan(&id, &q); err != nil {
+			return err
+		}
+		bank[id] = q
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return utils.AppendBankQuestions(content, ids, bank)
+}
+
+// GetUsages lists the exams and exercises a bank question has appeared in
+func (r *QuestionBankRepository) GetUsages(ctx context.Context, questionID int) ([]models.BankQuestionUsage, error) {
+	rows, err := config.DB.Query(ctx, `
+		SELECT u.id, u.question_id, u.exam_id, u.exercise_id, COALESCE(ex.title, e.title, ''), u.question_number, u.created_at
+		FROM question_bank_usages u
+		LEFT JOIN exams ex ON ex.id = u.exam_id
+		LEFT JOIN exercises e ON e.id = u.exercise_id
+		WHERE u.question_id = $1
+		ORDER BY u.created_at DESC
+	`, questionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usages := []models.BankQuestionUsage{}
+	for rows.Next() {
+		var u models.BankQuestionUsage
+		if err := rows.Scan(&u.ID, &u.Question_ID, &u.Exam_ID, &u.Exercise_ID, &u.Title, &u.Question_Number, &u.Created_At); err != nil {
+			return nil, err
+		}
+		usages = append(usages, u)
+	}
+	return usages, rows.Err()
+}
+
+// syncUsages records the bank questions used in the content of an exam or exercise and forgets the ones
+// that were removed. Usages follow the bank question ID kept on each question, so renumbering keeps them.
+func syncUsages(ctx context.Context, q querier, column string, ownerID int, content models.QuestionContent) error {
+	usages := utils.BankQuestionUsages(content)
+	questionIDs := make([]int, 0, len(usages))
+	for questionID := range usages {
+		questionIDs = append(questionIDs, questionID)
+	}
+
+	_, err := q.Exec(ctx, `DELETE FROM question_bank_usages WHERE `+column+` = $1 AND NOT (question_id = ANY($2))`, ownerID, questionIDs)
+	if err != nil {
+		return err
+	}
+
+	// Soal bank yang sudah dihapus dilewati
+	for questionID, number := range usages {
+		if _, err := q.Exec(ctx, `
+			INSERT INTO question_bank_usages (question_id, `+column+`, question_number)
+			SELECT id, $2, $3 FROM question_bank WHERE id = $1
+			ON CONFLICT (question_id, `+column+`) DO UPDATE SET question_number = EXCLUDED.question_number
+		`, questionID, ownerID, number); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func tagsOrEmpty(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
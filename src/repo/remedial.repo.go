@@ -0,0 +1,240 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"project-ppl-be/config"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/utils"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// remedialReason is recorded in the score history when a remedial score replaces an exam score
+const remedialReason = "remedial exam"
+
+func remedialPolicyOrDefault(policy string) string {
+	if policy == "" {
+		return models.RemedialCapAtKKM
+	}
+	return policy
+}
+
+// examKKM returns the passing percentage of an exam: its own KKM, else its class's, else the default
+func examKKM(ctx context.Context, q querier, examID int) (float64, error) {
+	var kkm float64
+	err := q.QueryRow(ctx, `
+		SELECT COALESCE(e.kkm, gs.kkm, $2)
+		FROM exams e
+		LEFT JOIN gradebook_settings gs ON gs.class_id = e.class_id
+		WHERE e.id = $1
+	`, examID, models.DefaultKKM).Scan(&kkm)
+	return kkm, err
+}
+
+// GetBelowKKM lists the students whose latest score on an exam is below its KKM, lowest first
+func (r *ExamRepository) GetBelowKKM(ctx context.Context, examID int) (models.BelowKKM, error) {
+	kkm, err := examKKM(ctx, config.DB, examID)
+	if err != nil {
+		return models.BelowKKM{}, err
+	}
+
+	results, err := latestExamResults(ctx, `e.id = $1`, examID)
+	if err != nil {
+		return models.BelowKKM{}, err
+	}
+
+	below := models.BelowKKM{Exam_ID: examID, KKM: kkm, Students: []models.StudentScore{}}
+	for _, result := range results {
+		if result.student.Percent < kkm {
+			below.Students = append(below.Students, result.student)
+		}
+	}
+	sort.Slice(below.Students, func(i, j int) bool { return below.Students[i].Percent < below.Students[j].Percent })
+	return below, nil
+}
+
+// assignRemedialParticipants gives a remedial exam to the listed students, or when none are listed
+// to every student below the KKM of the original exam. Students outside the class are skipped.
+func (r *ExamRepository) assignRemedialParticipants(ctx context.Context, q querier, examID, originalID int, studentIDs []int) error {
+	if len(studentIDs) == 0 {
+		below, err := r.GetBelowKKM(ctx, originalID)
+		if err != nil {
+			return err
+		}
+		for _, student := range below.Students {
+			studentIDs = append(studentIDs, student.Student_ID)
+		}
+	}
+
+	// Hanya siswa di kelas ujian yang bisa diberi remedial
+	for _, studentID := range studentIDs {
+		if _, err := q.Exec(ctx, `
+			INSERT INTO exam_participants (exam_id, student_id)
+			SELECT e.id, a.student_id
+			FROM exams e
+			JOIN assigned_students_class a ON a.class_id = e.class_id
+			WHERE e.id = $1 AND a.student_id = $2
+			ON CONFLICT DO NOTHING
+		`, examID, studentID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveUnassignedRemedials drops the remedial exams the student is not assigned to
+func (r *ExamRepository) RemoveUnassignedRemedials(ctx context.Context, exams []models.Exams, studentID int) ([]models.Exams, error) {
+	rows, err := config.DB.Query(ctx, `SELECT exam_id FROM exam_participants WHERE student_id = $1`, studentID)
+	if err != nil {
+		return nil, err
+	}
+	assignedIDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, err
+	}
+	assigned := make(map[int]bool, len(assignedIDs))
+	for _, id := range assignedIDs {
+		assigned[id] = true
+	}
+
+	visible := []models.Exams{}
+	for _, exam := range exams {
+		if exam.Remedial_Of == nil || assigned[exam.ID] {
+			visible = append(visible, exam)
+		}
+	}
+	return visible, nil
+}
+
+// syncExamScore updates what depends on an exam score after its total is recomputed from the detail.
+// A remedial score recorded on it is applied again on top of the new total, a remedial score is recorded
+// on its original exam, and the final grade follows.
+func syncExamScore(ctx context.Context, q querier, scoreID int) error {
+	var remedialScoreID *int
+	if err := q.QueryRow(ctx, `
+		UPDATE exam_scores
+		SET pre_remedial_score = CASE WHEN remedial_score_id IS NULL THEN pre_remedial_score ELSE score END
+		WHERE id = $1
+		RETURNING remedial_score_id
+	`, scoreID).Scan(&remedialScoreID); err != nil {
+		return err
+	}
+
+	if remedialScoreID != nil {
+		if err := syncRemedialScore(ctx, q, *remedialScoreID); err != nil {
+			return err
+		}
+	} else if err := syncRemedialScore(ctx, q, scoreID); err != nil {
+		return err
+	}
+	return syncExamScoreFinalGrade(ctx, q, scoreID)
+}
+
+// syncRemedialScores records the latest remedial scores of a remedial exam, or of the remedial exams
+// of an original exam, on the original exam again, e.g. after its remedial policy or KKM changed
+func syncRemedialScores(ctx context.Context, q querier, examID int) error {
+	rows, err := q.Query(ctx, `
+		SELECT MAX(s.id)
+		FROM exam_scores s
+		JOIN exams e ON e.id = s.exam_id
+		WHERE (e.id = $1 OR e.remedial_of = $1) AND e.remedial_of IS NOT NULL
+		GROUP BY s.exam_id, s.student_id
+		ORDER BY 1
+	`, examID)
+	if err != nil {
+		return err
+	}
+	scoreIDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return err
+	}
+	for _, scoreID := range scoreIDs {
+		if err := syncRemedialScore(ctx, q, scoreID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncRemedialScore records a remedial score on the original exam once the remedial exam's scores are released.
+// Scores of regular exams, older attempts and scores still being graded are left alone.
+func syncRemedialScore(ctx context.Context, q querier, scoreID int) error {
+	var remedial models.ExamGrades
+	var score *float64
+	var latest bool
+	var exam models.Exams
+	if err := q.QueryRow(ctx, `
+		SELECT s.id, s.student_id, s.exam_id, s.score, e.remedial_of, e.remedial_policy, `+releaseColumns+`,
+			s.id = (SELECT MAX(l.id) FROM exam_scores l WHERE l.exam_id = s.exam_id AND l.student_id = s.student_id)
+		FROM exam_scores s
+		JOIN exams e ON e.id = s.exam_id
+		WHERE s.id = $1
+	`, scoreID).Scan(&remedial.ID, &remedial.Student_ID, &remedial.Exam_ID, &score, &exam.Remedial_Of, &exam.Remedial_Policy,
+		&exam.Release_Policy, &exam.Released_At, &exam.End_Time, &exam.Late_Grace_Minutes, &latest); err != nil {
+		return err
+	}
+	// Nilai remedial baru menggantikan nilai asli setelah dirilis, agar siswa tidak melihatnya lebih awal
+	if exam.Remedial_Of == nil || score == nil || !latest || !exam.ScoresReleased(time.Now()) {
+		return nil
+	}
+	remedial.Score = *score
+	return applyRemedialScore(ctx, q, remedial, *exam.Remedial_Of, exam.Remedial_Policy)
+}
+
+// applyRemedialScore records a remedial score on the student's latest score of the original exam following
+// the remedial policy. The score before the first remedial is kept, so retaking the remedial never compounds.
+func applyRemedialScore(ctx context.Context, q querier, remedial models.ExamGrades, originalID int, policy string) error {
+	var remedialMarks, originalMarks int
+	if err := q.QueryRow(ctx,
+		`SELECT r.total_marks, o.total_marks FROM exams r, exams o WHERE r.id = $1 AND o.id = $2`,
+		remedial.Exam_ID, originalID,
+	).Scan(&remedialMarks, &originalMarks); err != nil {
+		return err
+	}
+	kkm, err := examKKM(ctx, q, originalID)
+	if err != nil {
+		return err
+	}
+
+	var scoreID int
+	var score, original float64
+	var appliedID *int
+	err = q.QueryRow(ctx, `
+		SELECT id, COALESCE(score, 0), COALESCE(pre_remedial_score, score, 0), remedial_score_id
+		FROM exam_scores
+		WHERE exam_id = $1 AND student_id = $2
+		ORDER BY id DESC
+		LIMIT 1
+		FOR UPDATE
+	`, originalID, remedial.Student_ID).Scan(&scoreID, &score, &original, &appliedID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Siswa tanpa nilai ujian asli tidak punya nilai yang bisa diganti
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	percent := utils.RemedialPercent(policy, utils.Percent(original, originalMarks), utils.Percent(remedial.Score, remedialMarks), kkm)
+	recorded := percent * float64(originalMarks) / 100
+	if appliedID != nil && *appliedID == remedial.ID && recorded == score {
+		return nil
+	}
+
+	if _, err := q.Exec(ctx, `
+		UPDATE exam_scores
+		SET pre_remedial_score = COALESCE(pre_remedial_score, score), score = $2, remedial_score_id = $3
+		WHERE id = $1
+	`, scoreID, recorded, remedial.ID); err != nil {
+		return err
+	}
+	if _, err := q.Exec(ctx, `
+		INSERT INTO score_history (exam_score_id, old_score, new_score, old_detail, reason)
+		SELECT id, $2, $3, detail, $4 FROM exam_scores WHERE id = $1
+	`, scoreID, score, recorded, remedialReason); err != nil {
+		return err
+	}
+	return syncExamScoreFinalGrade(ctx, q, scoreID)
+}
@@ -0,0 +1,173 @@
+package repo
+
+import (
+	"context"
+	"project-ppl-be/config"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/utils"
+	"sort"
+)
+
+// examResult is a student's latest score on an exam
+type examResult struct {
+	classID    int
+	examID     int
+	totalMarks int
+	student    models.StudentScore
+}
+
+// latestExamResults returns the latest score of every student on the exams matching the condition on exams e
+func latestExamResults(ctx context.Context, condition string, args ...any) ([]examResult, error) {
+	rows, err := config.DB.Query(ctx, `
+		SELECT DISTINCT ON (s.exam_id, s.student_id)
+			e.class_id, e.id, e.total_marks, st.id, st.name, st.nis, COALESCE(s.score, 0)
+		FROM exam_scores s
+		JOIN exams e ON e.id = s.exam_id
+		JOIN students st ON st.id = s.student_id
+		WHERE `+condition+`
+		ORDER BY s.exam_id, s.student_id, s.id DESC
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []examResult
+	for rows.Next() {
+		var r examResult
+		if err := rows.Scan(&r.classID, &r.examID, &r.totalMarks, &r.student.Student_ID, &r.student.Name, &r.student.NIS, &r.student.Score); err != nil {
+			return nil, err
+		}
+		r.student.Percent = utils.Percent(r.student.Score, r.totalMarks)
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+// GetExamReport returns the score distribution of an exam. Scores pass at passingScore percent.
+func (r *ExamRepository) GetExamReport(ctx context.Context, examID int, passingScore float64) (models.ExamReport, error) {
+	exam, err := r.GetExamByID(ctx, examID)
+	if err != nil {
+		return models.ExamReport{}, err
+	}
+
+	results, err := latestExamResults(ctx, `e.id = $1`, examID)
+	if err != nil {
+		return models.ExamReport{}, err
+	}
+
+	report := models.ExamReport{
+		Exam_ID:       exam.ID,
+		Title:         exam.Title,
+		Class_ID:      exam.Class_ID,
+		Total_Marks:   exam.Total_Marks,
+		Passing_Score: passingScore,
+		Students:      make([]models.StudentScore, len(results)),
+	}
+	percents := make([]float64, len(results))
+	for i, result := range results {
+		result.student.Passed = result.student.Percent >= passingScore
+		report.Students[i] = result.student
+		percents[i] = result.student.Percent
+	}
+	sort.Slice(report.Students, func(i, j int) bool { return report.Students[i].Name < report.Students[j].Name })
+	report.Statistics = utils.ScoreStatistics(percents, passingScore)
+	return report, nil
+}
+
+// GetClassReport returns the performance of a class over all its exams and compares it with
+// the other classes of the same grade. Students pass when their average reaches passingScore percent.
+func (r *ClassRepository) GetClassReport(ctx context.Context, classID int, passingScore float64) (models.ClassReport, error) {
+	report := models.ClassReport{Class_ID: classID, Passing_Score: passingScore, Exams: []models.ExamSummary{}, Students: []models.StudentAverage{}}
+	if err := config.DB.QueryRow(ctx, `SELECT name, COALESCE(grade, 0) FROM classes WHERE id = $1`, classID).Scan(&report.Name, &report.Grade); err != nil {
+		return models.ClassReport{}, err
+	}
+
+	rows, err := config.DB.Query(ctx, `SELECT id, title FROM exams WHERE class_id = $1 AND remedial_of IS NULL ORDER BY start_time, id`, classID)
+	if err != nil {
+		return models.ClassReport{}, err
+	}
+	for rows.Next() {
+		var exam models.ExamSummary
+		if err := rows.Scan(&exam.Exam_ID, &exam.Title); err != nil {
+			rows.Close()
+			return models.ClassReport{}, err
+		}
+		report.Exams = append(report.Exams, exam)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return models.ClassReport{}, err
+	}
+
+	// Kelas lain dengan tingkat yang sama ikut dihitung untuk perbandingan
+	results, err := latestExamResults(ctx, `e.class_id IN (SELECT id FROM classes WHERE COALESCE(grade, 0) = $1) AND e.remedial_of IS NULL`, report.Grade)
+	if err != nil {
+		return models.ClassReport{}, err
+	}
+
+	examPercents := make(map[int][]float64)
+	classStudents := make(map[int]map[int]*models.StudentAverage)
+	for _, result := range results {
+		if result.classID == classID {
+			examPercents[result.examID] = append(examPercents[result.examID], result.student.Percent)
+		}
+
+		students, ok := classStudents[result.classID]
+		if !ok {
+			students = make(map[int]*models.StudentAverage)
+			classStudents[result.classID] = students
+		}
+		student, ok := students[result.student.Student_ID]
+		if !ok {
+			student = &models.StudentAverage{Student_ID: result.student.Student_ID, Name: result.student.Name, NIS: result.student.NIS}
+			students[result.student.Student_ID] = student
+		}
+		// Rata-rata dijumlah dulu lalu dibagi setelah semua ujian terbaca
+		student.Exams_Taken++
+		student.Average += result.student.Percent
+	}
+
+	for i, exam := range report.Exams {
+		report.Exams[i].Statistics = utils.ScoreStatistics(examPercents[exam.Exam_ID], passingScore)
+	}
+
+	averages := func(students map[int]*models.StudentAverage) []float64 {
+		values := make([]float64, 0, len(students))
+		for _, student := range students {
+			values = append(values, student.Average)
+		}
+		return values
+	}
+	for _, students := range classStudents {
+		for _, student := range students {
+			student.Average /= float64(student.Exams_Taken)
+			student.Passed = student.Average >= passingScore
+		}
+	}
+
+	for _, student := range classStudents[classID] {
+		report.Students = append(report.Students, *student)
+	}
+	sort.Slice(report.Students, func(i, j int) bool { return report.Students[i].Name < report.Students[j].Name })
+	report.Statistics = utils.ScoreStatistics(averages(classStudents[classID]), passingScore)
+
+	classes, err := config.DB.Query(ctx, `SELECT id, name FROM classes WHERE COALESCE(grade, 0) = $1 ORDER BY name, id`, report.Grade)
+	if err != nil {
+		return models.ClassReport{}, err
+	}
+	defer classes.Close()
+	for classes.Next() {
+		var comparison models.ClassComparison
+		if err := classes.Scan(&comparison.Class_ID, &comparison.Name); err != nil {
+			return models.ClassReport{}, err
+		}
+		stats := utils.ScoreStatistics(averages(classStudents[comparison.Class_ID]), passingScore)
+		comparison.Students = stats.Count
+		comparison.Mean = stats.Mean
+		comparison.Median = stats.Median
+		comparison.Pass_Rate = stats.Pass_Rate
+		report.Comparison = append(report.Comparison, comparison)
+	}
+	return report, classes.Err()
+}
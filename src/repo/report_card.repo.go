@@ -0,0 +1,172 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"project-ppl-be/config"
+	"project-ppl-be/src/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ReportCardRepository struct{}
+
+// ErrRemarksWrittenByOther is returned when a teacher changes remarks another user wrote on a report card
+var ErrRemarksWrittenByOther = errors.New("the remarks on this report card were written by someone else")
+
+const reportCardNoteColumns = "id, student_id, semester, remarks, sick_days, permitted_days, absent_days, written_by, updated_at"
+
+// GetReportCard builds a student's report card for a semester
+func (r *ReportCardRepository) GetReportCard(ctx context.Context, studentID int, semester string) (models.ReportCard, error) {
+	student, err := (&StudentRepository{}).GetStudentByID(ctx, studentID)
+	if err != nil {
+		return models.ReportCard{}, err
+	}
+
+	cards, err := reportCards(ctx, []models.Student{student}, semester)
+	if err != nil {
+		return models.ReportCard{}, err
+	}
+	return cards[0], nil
+}
+
+// GetReportCardsByGrade builds the report cards of every student in a grade level, ordered by name.
+// With a teacherID only the students the teacher teaches are included.
+func (r *ReportCardRepository) GetReportCardsByGrade(ctx context.Context, grade int, semester string, teacherID int) ([]models.ReportCard, error) {
+	rows, err := config.DB.Query(ctx, `
+		SELECT s.id, s.name, s.nis, s.phone_number, s.grade, s.curr_score, s.status, s.profile_picture_url, s.user_id
+		FROM students s
+		WHERE s.grade = $1
+			AND ($2 = 0 OR EXISTS (
+				SELECT 1 FROM assigned_students_class a
+				JOIN classes c ON c.id = a.class_id
+				WHERE a.student_id = s.id AND c.teacher_id = $2
+			))
+		ORDER BY s.name, s.id
+	`, grade, teacherID)
+	if err != nil {
+		return nil, err
+	}
+	students, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Student, error) {
+		var s models.Student
+		err := row.Scan(&s.ID, &s.Name, &s.NIS, &s.Phone_Number, &s.Grade, &s.Current_Score, &s.Status, &s.Profile_Picture_URL, &s.User_ID)
+		return s, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reportCards(ctx, students, semester)
+}
+
+// UpdateReportCardNote saves the remarks and attendance on a student's report card for a semester.
+// Fields left out keep their saved value. Remarks written by another user can only be changed by an admin;
+// trying returns ErrRemarksWrittenByOther.
+func (r *ReportCardRepository) UpdateReportCardNote(ctx context.Context, req models.UpdateReportCardNoteRequest, userID int, isAdmin bool) (models.ReportCardNote, error) {
+	var n models.ReportCardNote
+	err := config.DB.QueryRow(ctx, `
+		INSERT INTO report_card_notes (student_id, semester, remarks, sick_days, permitted_days, absent_days, written_by)
+		VALUES ($1, $2, COALESCE($3::TEXT, ''), $4::INT, $5::INT, $6::INT, CASE WHEN $3::TEXT IS NULL THEN NULL ELSE $7::INT END)
+		ON CONFLICT (student_id, semester) DO UPDATE
+		SET remarks = COALESCE($3::TEXT, report_card_notes.remarks),
+			sick_days = COALESCE($4::INT, report_card_notes.sick_days),
+			permitted_days = COALESCE($5::INT, report_card_notes.permitted_days),
+			absent_days = COALESCE($6::INT, report_card_notes.absent_days),
+			written_by = CASE WHEN $3::TEXT IS NULL THEN report_card_notes.written_by ELSE $7::INT END,
+			updated_at = NOW()
+		WHERE $3::TEXT IS NULL OR $8::BOOLEAN OR report_card_notes.written_by IS NULL OR report_card_notes.written_by = $7::INT
+		RETURNING `+reportCardNoteColumns,
+		req.Student_ID, req.Semester, req.Remarks, req.Sick_Days, req.Permitted_Days, req.Absent_Days, userID, isAdmin,
+	).Scan(&n.ID, &n.Student_ID, &n.Semester, &n.Remarks, &n.Sick_Days, &n.Permitted_Days, &n.Absent_Days, &n.Written_By, &n.Updated_At)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Baris yang ada tidak diperbarui karena catatannya ditulis orang lain
+		return models.ReportCardNote{}, ErrRemarksWrittenByOther
+	} else if err != nil {
+		return models.ReportCardNote{}, err
+	}
+	return n, nil
+}
+
+// reportCards fills in the classes, final grades and notes of the students' report cards
+func reportCards(ctx context.Context, students []models.Student, semester string) ([]models.ReportCard, error) {
+	now := time.Now()
+	cards := make([]models.ReportCard, len(students))
+	index := make(map[int]int, len(students))
+	studentIDs := make([]int, len(students))
+	for i, student := range students {
+		cards[i] = models.ReportCard{Student: student, Semester: semester, Classes: []models.ReportCardClass{}, Generated_At: now}
+		index[student.ID] = i
+		studentIDs[i] = student.ID
+	}
+	if len(students) == 0 {
+		return cards, nil
+	}
+
+	classes, err := studentClasses(ctx, studentIDs)
+	if err != nil {
+		return nil, err
+	}
+	for studentID, list := range classes {
+		cards[index[studentID]].Classes = list
+	}
+
+	rows, err := config.DB.Query(ctx, `
+		SELECT student_id, remarks, COALESCE(sick_days, 0), COALESCE(permitted_days, 0), COALESCE(absent_days, 0),
+			sick_days IS NOT NULL OR permitted_days IS NOT NULL OR absent_days IS NOT NULL
+		FROM report_card_notes
+		WHERE student_id = ANY($1) AND semester = $2
+	`, studentIDs, semester)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var studentID int
+		var remarks string
+		var attendance models.AttendanceSummary
+		var recorded bool
+		if err := rows.Scan(&studentID, &remarks, &attendance.Sick_Days, &attendance.Permitted_Days, &attendance.Absent_Days, &recorded); err != nil {
+			return nil, err
+		}
+		card := &cards[index[studentID]]
+		card.Remarks = remarks
+		// Kehadiran hanya dicetak kalau sudah diisi
+		if recorded {
+			card.Attendance = &attendance
+		}
+	}
+	return cards, rows.Err()
+}
+
+// studentClasses returns the classes each student is assigned to, ordered by name, with their final grade and KKM
+func studentClasses(ctx context.Context, studentIDs []int) (map[int][]models.ReportCardClass, error) {
+	rows, err := config.DB.Query(ctx, `
+		SELECT a.student_id, c.id, c.name, t.name, g.exercise_average, g.exam_average, g.final_grade, COALESCE(gs.kkm, $2)
+		FROM assigned_students_class a
+		JOIN classes c ON c.id = a.class_id
+		JOIN teachers t ON t.id = c.teacher_id
+		LEFT JOIN class_final_grades g ON g.class_id = c.id AND g.student_id = a.student_id
+		LEFT JOIN gradebook_settings gs ON gs.class_id = c.id
+		WHERE a.student_id = ANY($1)
+		ORDER BY c.name, c.id
+	`, studentIDs, models.DefaultKKM)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	classes := make(map[int][]models.ReportCardClass)
+	for rows.Next() {
+		var studentID int
+		var class models.ReportCardClass
+		if err := rows.Scan(&studentID, &class.Class_ID, &class.Name, &class.Teacher_Name,
+			&class.Exercise_Average, &class.Exam_Average, &class.Final_Grade, &class.KKM); err != nil {
+			return nil, err
+		}
+		class.Passed = class.Final_Grade != nil && *class.Final_Grade >= class.KKM
+		classes[studentID] = append(classes[studentID], class)
+	}
+	return classes, rows.Err()
+}
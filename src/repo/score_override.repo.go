@@ -0,0 +1,134 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"project-ppl-be/config"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Errors returned when a teacher overrides a question score
+var (
+	ErrQuestionNotInScore = utils.ErrQuestionNotInScore
+	ErrScoreAboveMax      = utils.ErrScoreAboveMax
+)
+
+// scoreTable is a score table whose question scores teachers can override
+type scoreTable struct {
+	name           string
+	overrideColumn string
+	// detailScore is the total the detail adds up to, before a remedial score replaced it
+	detailScore string
+	// floorAtZero keeps the total at 0 or more, as with negative marking on exams
+	floorAtZero bool
+	// syncScore updates what depends on a score in the table after its total is recomputed from the detail
+	syncScore func(ctx context.Context, q querier, scoreID int) error
+}
+
+var (
+	examScoreTable     = scoreTable{name: "exam_scores", overrideColumn: "exam_score_id", detailScore: "COALESCE(pre_remedial_score, score)", floorAtZero: true, syncScore: syncExamScore}
+	exerciseScoreTable = scoreTable{name: "exercise_scores", overrideColumn: "exercise_score_id", detailScore: "score", syncScore: syncExerciseScoreFinalGrade}
+)
+
+// OverrideExamScore replaces the score of one question in a graded exam, recomputes the total and records the change
+func (r *ExamRepository) OverrideExamScore(ctx context.Context, req models.OverrideScoreRequest, userID int) (models.ExamGrades, error) {
+	var grade models.ExamGrades
+	err := overrideQuestionScore(ctx, examScoreTable, req, userID, "id, student_id, exam_id, score, detail", func(row pgx.Row) error {
+		return row.Scan(&grade.ID, &grade.Student_ID, &grade.Exam_ID, &grade.Score, &grade.Detail)
+	})
+	return grade, err
+}
+
+// OverrideExerciseScore replaces the score of one question in a graded exercise, recomputes the total and records the change
+func (r *ExerciseRepository) OverrideExerciseScore(ctx context.Context, req models.OverrideScoreRequest, userID int) (models.ExerciseGrades, error) {
+	var grade models.ExerciseGrades
+	err := overrideQuestionScore(ctx, exerciseScoreTable, req, userID, "id, student_id, exercise_id, score, detail, attempt_id", func(row pgx.Row) error {
+		return row.Scan(&grade.ID, &grade.Student_ID, &grade.Exercise_ID, &grade.Score, &grade.Detail, &grade.Attempt_ID)
+	})
+	return grade, err
+}
+
+// GetExamScoreOverrides lists the overrides made to an exam score, oldest first
+func (r *ExamRepository) GetExamScoreOverrides(ctx context.Context, scoreID int) ([]models.ScoreOverride, error) {
+	return scoreOverrides(ctx, examScoreTable, scoreID)
+}
+
+// GetExerciseScoreOverrides lists the overrides made to an exercise score, oldest first
+func (r *ExerciseRepository) GetExerciseScoreOverrides(ctx context.Context, scoreID int) ([]models.ScoreOverride, error) {
+	return scoreOverrides(ctx, exerciseScoreTable, scoreID)
+}
+
+func overrideQuestionScore(ctx context.Context, table scoreTable, req models.OverrideScoreRequest, userID int, returning string, scan func(pgx.Row) error) error {
+	tx, err := config.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var detailBytes []byte
+	if err := tx.QueryRow(ctx, `SELECT detail FROM `+table.name+` WHERE id = $1 FOR UPDATE`, req.Score_ID).Scan(&detailBytes); err != nil {
+		return err
+	}
+
+	var detail map[string]models.QuestionResult
+	if detailBytes != nil {
+		if err := json.Unmarshal(detailBytes, &detail); err != nil {
+			return err
+		}
+	}
+	previous, total, err := utils.OverrideQuestionScore(detail, req, table.floorAtZero)
+	if err != nil {
+		return err
+	}
+	result := detail[req.Question_Key]
+
+	detailBytes, err = json.Marshal(detail)
+	if err != nil {
+		return err
+	}
+	if err := scan(tx.QueryRow(ctx,
+		`UPDATE `+table.name+` SET score = $2, detail = $3 WHERE id = $1 RETURNING `+returning,
+		req.Score_ID, total, detailBytes,
+	)); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO score_overrides (`+table.overrideColumn+`, question_key, auto_score, previous_score, new_score, comment, overridden_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, req.Score_ID, req.Question_Key, *result.Auto_Score, previous, result.Score, req.Comment, userID); err != nil {
+		return err
+	}
+
+	if err := table.syncScore(ctx, tx, req.Score_ID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func scoreOverrides(ctx context.Context, table scoreTable, scoreID int) ([]models.ScoreOverride, error) {
+	rows, err := config.DB.Query(ctx, `
+		SELECT id, `+table.overrideColumn+`, question_key, auto_score, previous_score, new_score, comment, overridden_by, created_at
+		FROM score_overrides
+		WHERE `+table.overrideColumn+` = $1
+		ORDER BY created_at, id
+	`, scoreID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	overrides := []models.ScoreOverride{}
+	for rows.Next() {
+		var o models.ScoreOverride
+		if err := rows.Scan(&o.ID, &o.Score_ID, &o.Question_Key, &o.Auto_Score, &o.Previous_Score, &o.New_Score, &o.Comment, &o.Overridden_By, &o.Created_At); err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, o)
+	}
+	return overrides, rows.Err()
+}
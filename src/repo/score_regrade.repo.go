@@ -0,0 +1,139 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"project-ppl-be/config"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/utils"
+)
+
+// regradeReason is recorded in the score history of every regraded score
+const regradeReason = "answer key changed"
+
+// PreviewExamRegrade reports how the stored scores of an exam would change under new content
+func (r *ExamRepository) PreviewExamRegrade(ctx context.Context, examID int, content models.QuestionContent, negativeMarking float64) ([]models.ScoreChange, int, error) {
+	return regradeScores(ctx, config.DB, examScoreTable, "exam_id", examID, content, negativeMarking, nil)
+}
+
+// PreviewExerciseRegrade reports how the stored scores of an exercise would change under new content
+func (r *ExerciseRepository) PreviewExerciseRegrade(ctx context.Context, exerciseID int, content models.QuestionContent) ([]models.ScoreChange, int, error) {
+	return regradeScores(ctx, config.DB, exerciseScoreTable, "exercise_id", exerciseID, content, 0, nil)
+}
+
+// GetExamScoreHistory lists the previous versions of an exam score, oldest first
+func (r *ExamRepository) GetExamScoreHistory(ctx context.Context, scoreID int) ([]models.ScoreHistory, error) {
+	return scoreHistory(ctx, examScoreTable, scoreID)
+}
+
+// GetExerciseScoreHistory lists the previous versions of an exercise score, oldest first
+func (r *ExerciseRepository) GetExerciseScoreHistory(ctx context.Context, scoreID int) ([]models.ScoreHistory, error) {
+	return scoreHistory(ctx, exerciseScoreTable, scoreID)
+}
+
+// regradeScores scores the stored details of one exam or exercise again and returns the scores whose total changes
+// with the number of scores checked. Changes are only written when appliedBy is set, and q must then be the
+// transaction that saves the new content so the scores never disagree with it.
+func regradeScores(ctx context.Context, q querier, table scoreTable, parentColumn string, parentID int, content models.QuestionContent, negativeMarking float64, appliedBy *int) ([]models.ScoreChange, int, error) {
+	query := `SELECT id, student_id, COALESCE(` + table.detailScore + `, 0), detail FROM ` + table.name + ` WHERE ` + parentColumn + ` = $1 AND detail IS NOT NULL ORDER BY id`
+	if appliedBy != nil {
+		query += ` FOR UPDATE`
+	}
+	rows, err := q.Query(ctx, query, parentID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	type storedScore struct {
+		change      models.ScoreChange
+		detailBytes []byte
+		detail      map[string]models.QuestionResult
+	}
+	var regraded []storedScore
+	total := 0
+	for rows.Next() {
+		var s storedScore
+		if err := rows.Scan(&s.change.Score_ID, &s.change.Student_ID, &s.change.Old_Score, &s.detailBytes); err != nil {
+			rows.Close()
+			return nil, 0, err
+		}
+		total++
+
+		var old map[string]models.QuestionResult
+		if err := json.Unmarshal(s.detailBytes, &old); err != nil {
+			rows.Close()
+			return nil, 0, err
+		}
+		s.change.New_Score, s.detail = utils.RegradeDetail(content, old, negativeMarking)
+		if table.floorAtZero {
+			s.change.New_Score = math.Max(s.change.New_Score, 0)
+		}
+
+		if s.change.New_Score != s.change.Old_Score || utils.DetailChanged(old, s.detail) {
+			regraded = append(regraded, s)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// Detail saja bisa berubah (mis. max_score) tanpa mengubah total
+	changes := []models.ScoreChange{}
+	for _, s := range regraded {
+		if s.change.New_Score != s.change.Old_Score {
+			changes = append(changes, s.change)
+		}
+	}
+	if appliedBy == nil {
+		return changes, total, nil
+	}
+
+	for _, s := range regraded {
+		detailBytes, err := json.Marshal(s.detail)
+		if err != nil {
+			return nil, 0, err
+		}
+		if _, err := q.Exec(ctx,
+			`UPDATE `+table.name+` SET score = $2, detail = $3 WHERE id = $1`,
+			s.change.Score_ID, s.change.New_Score, detailBytes,
+		); err != nil {
+			return nil, 0, err
+		}
+		if _, err := q.Exec(ctx, `
+			INSERT INTO score_history (`+table.overrideColumn+`, old_score, new_score, old_detail, reason, changed_by)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, s.change.Score_ID, s.change.Old_Score, s.change.New_Score, s.detailBytes, regradeReason, *appliedBy); err != nil {
+			return nil, 0, err
+		}
+		if err := table.syncScore(ctx, q, s.change.Score_ID); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return changes, total, nil
+}
+
+func scoreHistory(ctx context.Context, table scoreTable, scoreID int) ([]models.ScoreHistory, error) {
+	rows, err := config.DB.Query(ctx, `
+		SELECT id, `+table.overrideColumn+`, old_score, new_score, old_detail, reason, changed_by, created_at
+		FROM score_history
+		WHERE `+table.overrideColumn+` = $1
+		ORDER BY created_at, id
+	`, scoreID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []models.ScoreHistory{}
+	for rows.Next() {
+		var h models.ScoreHistory
+		if err := rows.Scan(&h.ID, &h.Score_ID, &h.Old_Score, &h.New_Score, &h.Old_Detail, &h.Reason, &h.Changed_By, &h.Created_At); err != nil {
+			return nil, err
+		}
+		history = append(history, h)
+	}
+	return history, rows.Err()
+}
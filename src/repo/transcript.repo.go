@@ -0,0 +1,128 @@
+package repo
+
+import (
+	"context"
+	"project-ppl-be/config"
+	"project-ppl-be/src/models"
+	"project-ppl-be/src/utils"
+	"time"
+)
+
+// GetTranscript returns a student's academic record over every class they are assigned to.
+// Scores are listed oldest first: every exercise attempt, and for every exam its latest score, which is the one
+// that counts. Exam scores that are not released yet are left empty.
+func (r *StudentRepository) GetTranscript(ctx context.Context, studentID int) (models.Transcript, error) {
+	student, err := r.GetStudentByID(ctx, studentID)
+	if err != nil {
+		return models.Transcript{}, err
+	}
+
+	transcript := models.Transcript{
+		Schema_Version: models.TranscriptSchemaVersion,
+		Generated_At:   time.Now(),
+		Student: models.TranscriptStudent{
+			ID:     student.ID,
+			Name:   student.Name,
+			NIS:    student.NIS,
+			Grade:  student.Grade,
+			Status: student.Status,
+		},
+		Classes: []models.TranscriptClass{},
+	}
+
+	classes, err := studentClasses(ctx, []int{studentID})
+	if err != nil {
+		return models.Transcript{}, err
+	}
+	index := make(map[int]int)
+	var finalGrades []float64
+	for _, class := range classes[studentID] {
+		index[class.Class_ID] = len(transcript.Classes)
+		transcript.Classes = append(transcript.Classes, models.TranscriptClass{
+			Class_ID:         class.Class_ID,
+			Name:             class.Name,
+			Teacher_Name:     class.Teacher_Name,
+			Exercise_Average: class.Exercise_Average,
+			Exam_Average:     class.Exam_Average,
+			Final_Grade:      class.Final_Grade,
+			KKM:              class.KKM,
+			Passed:           class.Passed,
+			Exercises:        []models.TranscriptScore{},
+			Exams:            []models.TranscriptScore{},
+		})
+		if class.Final_Grade != nil {
+			finalGrades = append(finalGrades, *class.Final_Grade)
+		}
+	}
+	transcript.Cumulative_Average = utils.CategoryAverage(finalGrades, 0)
+
+	// Nilai dari kelas yang sudah tidak diikuti siswa tidak masuk transkrip
+	err = transcriptScores(ctx, `
+		SELECT m.class_id, s.id, e.id, e.title, a.attempt_number, NULL::INT, s.score, NULL::DOUBLE PRECISION, e.total_marks,
+			COALESCE(a.submitted_at, s.created_at), TRUE
+		FROM exercise_scores s
+		JOIN exercises e ON e.id = s.exercise_id
+		JOIN materials m ON m.id = e.material_id
+		LEFT JOIN exercise_attempts a ON a.id = s.attempt_id
+		WHERE s.student_id = $1
+		ORDER BY COALESCE(a.submitted_at, s.created_at), s.id
+	`, studentID, func(classID int, score models.TranscriptScore) {
+		if i, ok := index[classID]; ok {
+			transcript.Classes[i].Exercises = append(transcript.Classes[i].Exercises, score)
+		}
+	})
+	if err != nil {
+		return models.Transcript{}, err
+	}
+
+	err = transcriptScores(ctx, `
+		SELECT class_id, score_id, exam_id, title, NULL::INT, remedial_of, score, pre_remedial_score, total_marks, recorded_at, released
+		FROM (
+			SELECT DISTINCT ON (s.exam_id) e.class_id, s.id AS score_id, e.id AS exam_id, e.title, e.remedial_of, s.score,
+				s.pre_remedial_score, e.total_marks, s.created_at AS recorded_at, `+releasedCondition+` AS released
+			FROM exam_scores s
+			JOIN exams e ON e.id = s.exam_id
+			WHERE s.student_id = $1
+			ORDER BY s.exam_id, s.id DESC
+		) latest
+		ORDER BY recorded_at, score_id
+	`, studentID, func(classID int, score models.TranscriptScore) {
+		if i, ok := index[classID]; ok {
+			transcript.Classes[i].Exams = append(transcript.Classes[i].Exams, score)
+		}
+	})
+	if err != nil {
+		return models.Transcript{}, err
+	}
+
+	return transcript, nil
+}
+
+// transcriptScores reads class_id, score_id, item_id, title, attempt, remedial_of, score, pre_remedial_score,
+// total_marks, recorded_at and released rows and passes every score to add with its class
+func transcriptScores(ctx context.Context, query string, studentID int, add func(classID int, score models.TranscriptScore)) error {
+	rows, err := config.DB.Query(ctx, query, studentID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var classID int
+		var released bool
+		var s models.TranscriptScore
+		if err := rows.Scan(&classID, &s.Score_ID, &s.Item_ID, &s.Title, &s.Attempt, &s.Remedial_Of, &s.Score, &s.Pre_Remedial_Score,
+			&s.Total_Marks, &s.Recorded_At, &released); err != nil {
+			return err
+		}
+		if !released {
+			s.Score, s.Pre_Remedial_Score = nil, nil
+		}
+		if s.Score != nil {
+			percent := utils.Percent(*s.Score, s.Total_Marks)
+			s.Percent = &percent
+		}
+		add(classID, s)
+	}
+	return rows.Err()
+}
@@ -13,6 +13,7 @@ import (
 	users "project-ppl-be/src/api/v1/users"
 	exercises "project-ppl-be/src/api/v1/exercises"
 	exams "project-ppl-be/src/api/v1/exams"
+	questionbank "project-ppl-be/src/api/v1/questionbank"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -31,6 +32,8 @@ func SetupRouter() *gin.Engine {
 		v1Group.GET("/ping", v1.PingHandler)
 
 		v1Group.POST("/auth", auth.AuthHandler)
+		v1Group.POST("/auth/refresh", auth.RefreshHandler)
+		v1Group.POST("/auth/logout", auth.LogoutHandler)
 
 		// USERS
 		usersGroup := v1Group.Group("/users")
@@ -52,6 +55,10 @@ func SetupRouter() *gin.Engine {
 		studentAccessGroup := v1Group.Group("/students")
 		studentAccessGroup.Use(middleware.AuthMiddleware())
 		studentAccessGroup.GET("/details", students.StudentGetByIDHandler)
+		studentAccessGroup.GET("/report-card", students.ReportCardGetHandler)
+		studentAccessGroup.PATCH("/report-card/notes", middleware.TeacherMiddleware(), students.ReportCardNoteUpdateHandler)
+		studentAccessGroup.GET("/report-cards", middleware.TeacherMiddleware(), students.ReportCardsByGradeHandler)
+		studentAccessGroup.GET("/transcript", middleware.TeacherMiddleware(), students.TranscriptGetHandler)
 
 		// TEACHERS
 		teachersGroup := v1Group.Group("/teachers")
@@ -67,11 +74,15 @@ func SetupRouter() *gin.Engine {
 		classesGroup.GET("", classes.ClassGetHandler)
 		classesGroup.GET("/class-id", classes.GetClassIDHandler)
 		classesGroup.GET("/details", classes.ClassGetByIdHandler)
-		classesGroup.POST("/assign-students", classes.ClassAssignStudentsHandler)
-		classesGroup.DELETE("/unassign-students", classes.ClassUnassignStudentsHandler)
-		classesGroup.POST("", classes.ClassPostHandler)
-		classesGroup.PATCH("", classes.ClassUpdateHandler)
-		classesGroup.DELETE("", classes.ClassDeleteHandler)
+		classesGroup.POST("/assign-students", middleware.TeacherMiddleware(), classes.ClassAssignStudentsHandler)
+		classesGroup.DELETE("/unassign-students", middleware.TeacherMiddleware(), classes.ClassUnassignStudentsHandler)
+		classesGroup.POST("", middleware.TeacherMiddleware(), classes.ClassPostHandler)
+		classesGroup.PATCH("", middleware.TeacherMiddleware(), classes.ClassUpdateHandler)
+		classesGroup.DELETE("", middleware.TeacherMiddleware(), classes.ClassDeleteHandler)
+		classesGroup.GET("/report", middleware.TeacherMiddleware(), classes.ClassReportGetHandler)
+		classesGroup.GET("/gradebook", middleware.TeacherMiddleware(), classes.GradebookGetHandler)
+		classesGroup.PATCH("/gradebook", middleware.TeacherMiddleware(), classes.GradebookSettingsUpdateHandler)
+		classesGroup.GET("/final-grade", classes.FinalGradeGetHandler)
 
 		// CLASSES FOR STUDENTS
 		classesForStudentsGroup := v1Group.Group("/classes")
@@ -96,12 +107,22 @@ func SetupRouter() *gin.Engine {
 		exercisesGroup.Use(middleware.AuthMiddleware())
 		exercisesGroup.GET("", exercises.ExercisesGetByMaterialHandler)
 		exercisesGroup.GET("/student", exercises.ExercisesGetByMaterialForStudentHandler)
-		exercisesGroup.POST("", exercises.ExercisesPostHandler)
+		exercisesGroup.POST("", middleware.TeacherMiddleware(), exercises.ExercisesPostHandler)
 		exercisesGroup.POST("/calculate-grade", exercises.CalculateGradePostHandler)
+		exercisesGroup.POST("/calculate-grade-async", exercises.CalculateGradeAsyncPostHandler)
+		exercisesGroup.GET("/grading-job", exercises.GradingJobGetHandler)
 		exercisesGroup.GET("/get-grade", exercises.ExerciseGradesGetHandler)
-		exercisesGroup.PATCH("", exercises.ExercisesUpdateHandler)
-		exercisesGroup.DELETE("", exercises.ExercisesDeleteHandler)
+		exercisesGroup.PATCH("", middleware.TeacherMiddleware(), exercises.ExercisesUpdateHandler)
+		exercisesGroup.DELETE("", middleware.TeacherMiddleware(), exercises.ExercisesDeleteHandler)
 		exercisesGroup.GET("/get-all-grade", exercises.ExerciseAllGradesGetHandler)
+		exercisesGroup.GET("/attempts", exercises.ExerciseAttemptsGetHandler)
+		exercisesGroup.GET("/graded-attempt", exercises.GradedAttemptGetHandler)
+		exercisesGroup.PATCH("/feedback", middleware.TeacherMiddleware(), exercises.EssayFeedbackUpdateHandler)
+		exercisesGroup.POST("/feedback/release", middleware.TeacherMiddleware(), exercises.EssayFeedbackReleaseHandler)
+		exercisesGroup.PATCH("/override-score", middleware.TeacherMiddleware(), exercises.ExerciseScoreOverrideHandler)
+		exercisesGroup.GET("/score-overrides", middleware.TeacherMiddleware(), exercises.ExerciseScoreOverridesGetHandler)
+		exercisesGroup.GET("/score-history", middleware.TeacherMiddleware(), exercises.ExerciseScoreHistoryGetHandler)
+		exercisesGroup.GET("/item-analysis", middleware.TeacherMiddleware(), exercises.ExerciseItemAnalysisGetHandler)
 
 		// EXERCISE ANSWERS
 		exerciseAnswersGroup := v1Group.Group("/exercises-answers")
@@ -115,13 +136,23 @@ func SetupRouter() *gin.Engine {
 		examsGroup := v1Group.Group("/exams")
 		examsGroup.Use(middleware.AuthMiddleware())
 		examsGroup.GET("", exams.ExamsGetByClassHandler)
-		examsGroup.GET("/student", exams.ExamsGetByClassForStudentHandler)
-		examsGroup.POST("", exams.ExamsPostHandler)
+		examsGroup.GET("/student", exams.ExamsGetForStudentHandler)
+		examsGroup.POST("", middleware.TeacherMiddleware(), exams.ExamsPostHandler)
 		examsGroup.POST("/calculate-grade", exams.CalculateGradePostHandler)
 		examsGroup.GET("/get-grade", exams.ExamGradesGetHandler)
-		examsGroup.PATCH("", exams.ExamsUpdateHandler)
-		examsGroup.DELETE("", exams.ExamsDeleteHandler)
+		examsGroup.PATCH("", middleware.TeacherMiddleware(), exams.ExamsUpdateHandler)
+		examsGroup.DELETE("", middleware.TeacherMiddleware(), exams.ExamsDeleteHandler)
 		examsGroup.GET("/get-all-grade", exams.ExamsAllGradesGetHandler)
+		examsGroup.POST("/start-attempt", exams.StartExamAttemptHandler)
+		examsGroup.GET("/attempt", exams.ExamAttemptGetHandler)
+		examsGroup.PATCH("/override-score", middleware.TeacherMiddleware(), exams.ExamScoreOverrideHandler)
+		examsGroup.GET("/score-overrides", middleware.TeacherMiddleware(), exams.ExamScoreOverridesGetHandler)
+		examsGroup.GET("/score-history", middleware.TeacherMiddleware(), exams.ExamScoreHistoryGetHandler)
+		examsGroup.GET("/item-analysis", middleware.TeacherMiddleware(), exams.ExamItemAnalysisGetHandler)
+		examsGroup.GET("/report", middleware.TeacherMiddleware(), exams.ExamReportGetHandler)
+		examsGroup.GET("/below-kkm", middleware.TeacherMiddleware(), exams.BelowKKMGetHandler)
+		examsGroup.POST("/release", middleware.TeacherMiddleware(), exams.ExamScoresReleaseHandler)
+		examsGroup.GET("/review", exams.ExamReviewGetHandler)
 
 		// EXERCISE ANSWERS
 		examAnswersGroup := v1Group.Group("/exams-answers")
@@ -130,6 +161,18 @@ func SetupRouter() *gin.Engine {
 		examAnswersGroup.POST("", exams.ExamAnswersPostHandler)
 		examAnswersGroup.PATCH("", exams.ExamAnswersUpdateHandler)
 		examAnswersGroup.DELETE("", exams.ExamAnswersDeleteHandler)
+		examAnswersGroup.PATCH("/autosave", exams.ExamAnswerAutosaveHandler)
+		examAnswersGroup.POST("/submit", exams.ExamAnswersSubmitHandler)
+
+		// QUESTION BANK
+		questionBankGroup := v1Group.Group("/question-bank")
+		questionBankGroup.Use(middleware.AuthMiddleware(), middleware.TeacherMiddleware())
+		questionBankGroup.GET("", questionbank.BankQuestionsGetHandler)
+		questionBankGroup.GET("/details", questionbank.BankQuestionGetByIDHandler)
+		questionBankGroup.GET("/usages", questionbank.BankQuestionUsagesGetHandler)
+		questionBankGroup.POST("", questionbank.BankQuestionPostHandler)
+		questionBankGroup.PATCH("", questionbank.BankQuestionUpdateHandler)
+		questionBankGroup.DELETE("", questionbank.BankQuestionDeleteHandler)
 
 		// DISCUSSIONS
 		discussionsGroup := v1Group.Group("/discussions")
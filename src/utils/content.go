@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"encoding/json"
+	"project-ppl-be/src/models"
+	"sort"
+)
+
+// StripAnswerKeys returns a copy of exam or exercise content without the fields a student
+// must not see before grading: correct answers of every question type, essay keywords, rubrics and explanations.
+// Matching pairs are replaced by the left items and the right items in sorted order.
+func StripAnswerKeys(content models.QuestionContent) models.QuestionContent {
+	questions := make([]models.Question, len(content.Questions))
+	for i, q := range content.Questions {
+		q.Correct_Answer = ""
+		q.Correct_Answers = nil
+		q.Numeric_Answer = nil
+		q.Explanation = ""
+		q.Keywords = nil
+		q.Rubric = nil
+
+		if len(q.Pairs) > 0 {
+			q.Left_Items = make([]string, len(q.Pairs))
+			q.Right_Items = make([]string, len(q.Pairs))
+			for j, pair := range q.Pairs {
+				q.Left_Items[j] = pair.Left
+				q.Right_Items[j] = pair.Right
+			}
+			sort.Strings(q.Right_Items)
+			q.Pairs = nil
+		}
+
+		// Jumlah kotak isian tetap terlihat, jawabannya tidak
+		if len(q.Blanks) > 0 {
+			q.Blanks = make([]models.Blank, len(q.Blanks))
+		}
+		questions[i] = q
+	}
+	content.Questions = questions
+	return content
+}
+
+// SanitizeExamsForRole strips answer keys from every exam when the caller is a student
+func SanitizeExamsForRole(role string, exams []models.Exams) []models.Exams {
+	if role != "student" {
+		return exams
+	}
+	sanitized := make([]models.Exams, len(exams))
+	for i, exam := range exams {
+		exam.Content = StripAnswerKeys(exam.Content)
+		sanitized[i] = exam
+	}
+	return sanitized
+}
+
+// SanitizeExercisesForRole strips answer keys from every exercise when the caller is a student
+func SanitizeExercisesForRole(role string, exercises []models.Exercises) []models.Exercises {
+	if role != "student" {
+		return exercises
+	}
+	sanitized := make([]models.Exercises, len(exercises))
+	for i, exercise := range exercises {
+		exercise.Content = StripAnswerKeys(exercise.Content)
+		sanitized[i] = exercise
+	}
+	return sanitized
+}
+
+// ReviewQuestions pairs every question, answer key included, with the student's result on it
+func ReviewQuestions(content models.QuestionContent, detail map[string]models.QuestionResult) []models.ReviewQuestion {
+	questions := make([]models.ReviewQuestion, len(content.Questions))
+	for i, q := range content.Questions {
+		result, ok := detail[q.Key()]
+		if !ok {
+			result.Max_Score = q.Weight
+		}
+		// Kata kunci esai hanya untuk penilai
+		q.Keywords = nil
+		questions[i] = models.ReviewQuestion{
+			Question:      q,
+			Answer:        result.Answer,
+			Score:         result.Score,
+			Max_Score:     result.Max_Score,
+			Correct:       result.Correct,
+			Pending:       result.Pending,
+			Justification: result.Justification,
+			Comment:       result.Comment,
+			Feedback:      result.Feedback,
+		}
+	}
+	return questions
+}
+
+// WithholdUnreleasedGrades hides the score and detail of every exam grade that is not released yet
+// when the caller is a student, so they only see that their answers were submitted
+func WithholdUnreleasedGrades(role string, grades []models.ExamGrades) []models.ExamGrades {
+	if role != "student" {
+		return grades
+	}
+	withheld := make([]models.ExamGrades, len(grades))
+	for i, grade := range grades {
+		if grade.Status != models.ScoreStatusReleased {
+			grade = models.ExamGrades{ID: grade.ID, Exam_ID: grade.Exam_ID, Student_ID: grade.Student_ID, Status: grade.Status}
+		}
+		withheld[i] = grade
+	}
+	return withheld
+}
+
+// WithholdJustifications removes the grader's justifications, overall and per rubric criterion, from the
+// detail of every exercise grade when the caller is a student. They are for teachers auditing the score.
+func WithholdJustifications(role string, grades []models.ExerciseGrades) []models.ExerciseGrades {
+	if role != "student" {
+		return grades
+	}
+	withheld := make([]models.ExerciseGrades, len(grades))
+	for i, grade := range grades {
+		grade.Detail = withoutJustifications(grade.Detail)
+		withheld[i] = grade
+	}
+	return withheld
+}
+
+// WithholdAttemptJustifications is WithholdJustifications for exercise attempts
+func WithholdAttemptJustifications(role string, attempts []models.ExerciseAttempt) []models.ExerciseAttempt {
+	if role != "student" {
+		return attempts
+	}
+	withheld := make([]models.ExerciseAttempt, len(attempts))
+	for i, attempt := range attempts {
+		attempt.Detail = withoutJustifications(attempt.Detail)
+		withheld[i] = attempt
+	}
+	return withheld
+}
+
+// withoutJustifications returns a copy of a score detail without justifications.
+// A detail that cannot be read as question results is withheld entirely.
+func withoutJustifications(detail any) any {
+	if detail == nil {
+		return nil
+	}
+	data, err := json.Marshal(detail)
+	if err != nil {
+		return nil
+	}
+	var results map[string]models.QuestionResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil
+	}
+	for key, result := range results {
+		result.Justification = ""
+		for j := range result.Criteria {
+			result.Criteria[j].Justification = ""
+		}
+		results[key] = result
+	}
+	return results
+}
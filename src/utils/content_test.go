@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"project-ppl-be/src/models"
+	"testing"
+)
+
+func sampleContent() models.QuestionContent {
+	return models.QuestionContent{
+		Version: models.QuestionSchemaVersion,
+		Questions: []models.Question{
+			{
+				Number: 1, Type: models.QuestionTypeMultipleChoice, Prompt: "2 + 2 = ?", Weight: 1,
+				Options:        []models.QuestionOption{{Key: "a", Text: "3"}, {Key: "b", Text: "4"}},
+				Correct_Answer: "b", Explanation: "Two plus two is four",
+			},
+			{
+				Number: 2, Type: models.QuestionTypeEssay, Prompt: "Explain photosynthesis", Weight: 2,
+				Explanation: "Mention light, water and carbon dioxide",
+				Keywords:    []string{"light", "water", "carbon dioxide"},
+				Rubric: []models.RubricCriterion{
+					{Name: "Process", Weight: 100, Levels: []models.RubricLevel{{Label: "Complete", Description: "Names every input", Credit: 1}}},
+				},
+			},
+		},
+	}
+}
+
+func TestStripAnswerKeys(t *testing.T) {
+	content := sampleContent()
+	got := StripAnswerKeys(content)
+
+	if len(got.Questions) != len(content.Questions) {
+		t.Fatalf("got %d questions, want %d", len(got.Questions), len(content.Questions))
+	}
+	for _, q := range got.Questions {
+		if q.Correct_Answer != "" || q.Explanation != "" || q.Keywords != nil {
+			t.Errorf("question %d still has answer data: %+v", q.Number, q)
+		}
+	}
+	if got.Questions[1].Rubric != nil {
+		t.Errorf("essay rubric was returned: %+v", got.Questions[1].Rubric)
+	}
+	if got.Questions[0].Prompt != "2 + 2 = ?" || len(got.Questions[0].Options) != 2 {
+		t.Errorf("question fields needed to answer were removed: %+v", got.Questions[0])
+	}
+	if content.Questions[0].Correct_Answer != "b" {
+		t.Errorf("original content was modified")
+	}
+}
+
+func TestSanitizeExamsForRole(t *testing.T) {
+	exams := []models.Exams{{ID: 1, Content: sampleContent()}}
+
+	for _, role := range []string{"teacher", "admin"} {
+		got := SanitizeExamsForRole(role, exams)
+		if got[0].Content.Questions[0].Correct_Answer != "b" {
+			t.Errorf("role %s: answer key was stripped, want it kept", role)
+		}
+	}
+
+	got := SanitizeExamsForRole("student", exams)
+	if got[0].Content.Questions[0].Correct_Answer != "" {
+		t.Errorf("role student: answer key was returned")
+	}
+	if exams[0].Content.Questions[0].Correct_Answer != "b" {
+		t.Errorf("original exam content was modified")
+	}
+}
+
+func TestSanitizeExercisesForRole(t *testing.T) {
+	exercises := []models.Exercises{{ID: 1, Content: sampleContent()}}
+
+	got := SanitizeExercisesForRole("student", exercises)
+	for _, q := range got[0].Content.Questions {
+		if q.Correct_Answer != "" || q.Explanation != "" || q.Keywords != nil {
+			t.Errorf("role student: question %d still has answer data", q.Number)
+		}
+	}
+
+	got = SanitizeExercisesForRole("teacher", exercises)
+	if got[0].Content.Questions[1].Explanation == "" {
+		t.Errorf("role teacher: explanation was stripped, want it kept")
+	}
+}
+
+func TestStripAnswerKeysHidesTypedAnswers(t *testing.T) {
+	expected := 9.81
+	content := models.QuestionContent{
+		Version: models.QuestionSchemaVersion,
+		Questions: []models.Question{
+			{Number: 1, Type: models.QuestionTypeNumeric, Prompt: "g = ?", Weight: 1, Numeric_Answer: &expected, Tolerance: 0.01},
+			{Number: 2, Type: models.QuestionTypeMatching, Prompt: "Match", Weight: 1, Pairs: []models.MatchingPair{
+				{Left: "Jakarta", Right: "Indonesia"}, {Left: "Berlin", Right: "Germany"},
+			}},
+			{Number: 3, Type: models.QuestionTypeFillBlank, Prompt: "___ and ___", Weight: 1, Blanks: []models.Blank{
+				{Accepted: []string{"salt"}}, {Accepted: []string{"pepper"}},
+			}},
+		},
+	}
+
+	got := StripAnswerKeys(content)
+
+	if got.Questions[0].Numeric_Answer != nil {
+		t.Errorf("numeric answer was returned")
+	}
+	matching := got.Questions[1]
+	if matching.Pairs != nil || len(matching.Left_Items) != 2 || matching.Right_Items[0] != "Germany" {
+		t.Errorf("matching pairs were not replaced by sorted items: %+v", matching)
+	}
+	blanks := got.Questions[2].Blanks
+	if len(blanks) != 2 || blanks[0].Accepted != nil {
+		t.Errorf("blanks were not emptied: %+v", blanks)
+	}
+	if content.Questions[2].Blanks[0].Accepted == nil {
+		t.Errorf("original content was modified")
+	}
+}
+
+func TestReviewQuestionsShowsEssayFeedback(t *testing.T) {
+	detail := map[string]models.QuestionResult{
+		"1": {Type: models.QuestionTypeMultipleChoice, Answer: "b", Score: 1, Max_Score: 1, Correct: true},
+		"2": {Type: models.QuestionTypeEssay, Answer: "Plants use light", Score: 1, Max_Score: 2, Justification: "Misses water", Feedback: "Mention water and carbon dioxide"},
+	}
+
+	got := ReviewQuestions(sampleContent(), detail)
+	if len(got) != 2 {
+		t.Fatalf("got %d questions, want 2", len(got))
+	}
+	if essay := got[1]; essay.Feedback != "Mention water and carbon dioxide" || essay.Justification != "Misses water" || essay.Keywords != nil {
+		t.Errorf("essay review = %+v, want the feedback without the keywords", essay)
+	}
+}
+
+func TestWithholdJustifications(t *testing.T) {
+	detail := map[string]models.QuestionResult{
+		"2": {Type: models.QuestionTypeEssay, Score: 1, Max_Score: 2, Justification: "Misses water", Feedback: "Mention water",
+			Criteria: []models.CriterionScore{{Name: "Process", Score: 1, Max_Score: 2, Justification: "Only light"}}},
+	}
+	grades := []models.ExerciseGrades{{ID: 1, Score: 50, Detail: detail}}
+
+	if got := WithholdJustifications("teacher", grades); got[0].Detail.(map[string]models.QuestionResult)["2"].Criteria[0].Justification != "Only light" {
+		t.Errorf("role teacher: justification was withheld, want it kept")
+	}
+
+	got := WithholdJustifications("student", grades)
+	essay := got[0].Detail.(map[string]models.QuestionResult)["2"]
+	if essay.Justification != "" || essay.Criteria[0].Justification != "" {
+		t.Errorf("role student: justification was returned: %+v", essay)
+	}
+	if essay.Feedback != "Mention water" || essay.Criteria[0].Score != 1 {
+		t.Errorf("role student: essay result lost more than the justification: %+v", essay)
+	}
+	if detail["2"].Criteria[0].Justification != "Only light" {
+		t.Errorf("original detail was modified")
+	}
+}
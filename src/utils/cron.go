@@ -6,12 +6,30 @@ import (
 	"fmt"
 )
 
-func StartCron(db *pgxpool.Pool) {
-	c := cron.New()
+// CronJob is a job scheduled by StartCron in addition to the exam status update
+type CronJob struct {
+	Name string
+	Spec string
+	Run  func() error
+}
+
+// StartCron runs the exam status update every minute and the other jobs on their own schedule.
+// A run that is still busy when the next one is due makes the next one skip, so runs never overlap.
+func StartCron(db *pgxpool.Pool, jobs ...CronJob) {
+	c := cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
 	c.AddFunc("* * * * *", func() {
 		if err := UpdateAllExamStatus(db); err != nil {
 			fmt.Println("Error updating exam status:", err)
 		}
 	})
+	for _, job := range jobs {
+		if _, err := c.AddFunc(job.Spec, func() {
+			if err := job.Run(); err != nil {
+				fmt.Println("Error running "+job.Name+":", err)
+			}
+		}); err != nil {
+			fmt.Println("Error scheduling "+job.Name+":", err)
+		}
+	}
 	c.Start()
 }
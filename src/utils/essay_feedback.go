@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"project-ppl-be/src/models"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// EssayFeedbackDrafts turns the grader's feedback on the essays of a new score into unreleased drafts,
+// ordered by question. Essays without feedback get no draft.
+func EssayFeedbackDrafts(scoreID int, essays map[string]EssayGrade) []models.EssayFeedback {
+	drafts := []models.EssayFeedback{}
+	for key, grade := range essays {
+		if grade.Feedback == "" {
+			continue
+		}
+		drafts = append(drafts, models.EssayFeedback{Score_ID: scoreID, Question_Key: key, Generated: grade.Feedback, Feedback: grade.Feedback})
+	}
+	sort.Slice(drafts, func(i, j int) bool { return questionKeyLess(drafts[i].Question_Key, drafts[j].Question_Key) })
+	return drafts
+}
+
+// VisibleEssayFeedback returns the feedback of a score the caller may see. Teachers see the drafts;
+// students see nothing until the feedback is released and never the grader's original text or the editor.
+func VisibleEssayFeedback(feedback []models.EssayFeedback, releasedAt *time.Time, withDrafts bool) []models.EssayFeedback {
+	if withDrafts {
+		return feedback
+	}
+	visible := []models.EssayFeedback{}
+	if releasedAt == nil {
+		return visible
+	}
+	for _, f := range feedback {
+		f.Generated = ""
+		f.Edited_By = nil
+		visible = append(visible, f)
+	}
+	return visible
+}
+
+// questionKeyLess orders question keys by their number
+func questionKeyLess(a, b string) bool {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	if errA != nil || errB != nil {
+		return a < b
+	}
+	return na < nb
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"project-ppl-be/src/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEssayFeedbackDrafts(t *testing.T) {
+	drafts := EssayFeedbackDrafts(7, map[string]EssayGrade{
+		"10": {Score: 2, Feedback: "Add an example"},
+		"2":  {Score: 3, Feedback: "Explain the cause"},
+		"3":  {Score: 5},
+	})
+
+	want := []models.EssayFeedback{
+		{Score_ID: 7, Question_Key: "2", Generated: "Explain the cause", Feedback: "Explain the cause"},
+		{Score_ID: 7, Question_Key: "10", Generated: "Add an example", Feedback: "Add an example"},
+	}
+	if !reflect.DeepEqual(drafts, want) {
+		t.Errorf("EssayFeedbackDrafts() = %+v, want %+v", drafts, want)
+	}
+	if drafts := EssayFeedbackDrafts(7, nil); drafts == nil || len(drafts) != 0 {
+		t.Errorf("EssayFeedbackDrafts(nil) = %v, want an empty list", drafts)
+	}
+}
+
+func TestVisibleEssayFeedback(t *testing.T) {
+	teacher := 4
+	released := time.Date(2024, 11, 4, 9, 0, 0, 0, time.UTC)
+	// Guru menyunting umpan balik dari penilai sebelum dirilis
+	feedback := []models.EssayFeedback{
+		{ID: 1, Score_ID: 7, Question_Key: "2", Generated: "Explain the cause", Feedback: "Explain why the war started", Edited_By: &teacher},
+		{ID: 2, Score_ID: 7, Question_Key: "3", Generated: "Good", Feedback: "Good"},
+	}
+
+	if got := VisibleEssayFeedback(feedback, nil, true); !reflect.DeepEqual(got, feedback) {
+		t.Errorf("teacher before release = %+v, want the drafts", got)
+	}
+	if got := VisibleEssayFeedback(feedback, nil, false); got == nil || len(got) != 0 {
+		t.Errorf("student before release = %+v, want no feedback", got)
+	}
+
+	got := VisibleEssayFeedback(feedback, &released, false)
+	if len(got) != 2 || got[0].Feedback != "Explain why the war started" || got[1].Feedback != "Good" {
+		t.Fatalf("student after release = %+v, want the edited feedback", got)
+	}
+	for _, f := range got {
+		if f.Generated != "" || f.Edited_By != nil {
+			t.Errorf("student sees the draft details of question %s: %+v", f.Question_Key, f)
+		}
+	}
+	if feedback[0].Generated == "" || feedback[0].Edited_By == nil {
+		t.Error("VisibleEssayFeedback changed the stored feedback")
+	}
+
+	if got := VisibleEssayFeedback(feedback, &released, true); !reflect.DeepEqual(got, feedback) {
+		t.Errorf("teacher after release = %+v, want everything", got)
+	}
+}
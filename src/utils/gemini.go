@@ -2,47 +2,129 @@ package utils
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
-	"os"
+	"strings"
+	"time"
 )
 
-func EvaluateEssayWithGemini(question, studentAnswer string, maxScore float64) (float64, error) {
-	apiURL := os.Getenv("GEMINI_API_URL") + "?key=" + os.Getenv("GEMINI_API_KEY")
+// GeminiGrader grades essays with the Gemini generateContent API.
+// Each call times out after Timeout and is retried up to MaxRetries times with exponential backoff
+// when the request fails or Gemini answers 429 or 5xx.
+type GeminiGrader struct {
+	URL        string
+	APIKey     string
+	Timeout    time.Duration
+	MaxRetries int
+	Backoff    time.Duration
+	Client     *http.Client
+}
 
-	prompt := fmt.Sprintf(`Evaluate the following essay answer:
-Question: "%s"
-Student Answer: "%s"
+// geminiResponseSchema makes Gemini answer with a JSON object instead of free text.
+// With a rubric Gemini also scores every criterion.
+func geminiResponseSchema(rubric bool) map[string]any {
+	properties := map[string]any{
+		"score":         map[string]any{"type": "NUMBER"},
+		"justification": map[string]any{"type": "STRING"},
+		"feedback":      map[string]any{"type": "STRING"},
+	}
+	required := []string{"score", "justification", "feedback"}
 
-Assign a score from 0 to %.0f based on how well the answer satisfies the question.
-Give a full score if highly relevant, partial if somewhat relevant, low if irrelevant.
-Return only the score as a plain number.`,
-		question, studentAnswer, maxScore)
-
-	payload := map[string]interface{}{
-		"contents": []map[string]interface{}{
-			{
-				"parts": []map[string]string{
-					{"text": prompt},
+	if rubric {
+		properties["criteria"] = map[string]any{
+			"type": "ARRAY",
+			"items": map[string]any{
+				"type": "OBJECT",
+				"properties": map[string]any{
+					"name":          map[string]any{"type": "STRING"},
+					"level":         map[string]any{"type": "STRING"},
+					"score":         map[string]any{"type": "NUMBER"},
+					"justification": map[string]any{"type": "STRING"},
 				},
+				"required": []string{"name", "score", "justification"},
 			},
+		}
+		required = append(required, "criteria")
+	}
+
+	return map[string]any{"type": "OBJECT", "properties": properties, "required": required}
+}
+
+func (g *GeminiGrader) GradeEssay(ctx context.Context, req EssayRequest) (EssayGrade, error) {
+	if g.URL == "" || g.APIKey == "" {
+		return EssayGrade{}, errors.New("GEMINI_API_URL and GEMINI_API_KEY must be set")
+	}
+
+	payload := map[string]any{
+		"contents": []map[string]any{
+			{"parts": []map[string]string{{"text": geminiPrompt(req)}}},
+		},
+		"generationConfig": map[string]any{
+			"responseMimeType": "application/json",
+			"responseSchema":   geminiResponseSchema(len(req.Rubric) > 0),
 		},
 	}
-	bodyBytes, _ := json.Marshal(payload)
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return EssayGrade{}, fmt.Errorf("failed to create Gemini request: %w", err)
+	}
 
-	req, err := http.NewRequest("POST", apiURL, bytes.NewReader(bodyBytes))
+	backoff := g.Backoff
+	for attempt := 0; ; attempt++ {
+		grade, retry, err := g.call(ctx, body)
+		if err == nil {
+			return grade, nil
+		}
+		if !retry || attempt >= g.MaxRetries {
+			return EssayGrade{}, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return EssayGrade{}, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// call sends one request to Gemini and reports whether a failure is worth retrying
+func (g *GeminiGrader) call(ctx context.Context, body []byte) (EssayGrade, bool, error) {
+	timeout := g.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.URL, bytes.NewReader(body))
 	if err != nil {
-		return 0, fmt.Errorf("failed to create Gemini request: %w", err)
+		return EssayGrade{}, false, fmt.Errorf("failed to create Gemini request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	// Kunci API dikirim lewat header agar tidak ikut tercetak di pesan error URL
+	req.Header.Set("x-goog-api-key", g.APIKey)
 
-	resp, err := http.DefaultClient.Do(req)
+	client := g.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
 	if err != nil {
-		return 0, fmt.Errorf("failed to call Gemini API: %w", err)
+		return EssayGrade{}, true, fmt.Errorf("failed to call Gemini API: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return EssayGrade{}, retry, fmt.Errorf("Gemini API returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
 	var result struct {
 		Candidates []struct {
 			Content struct {
@@ -53,25 +135,52 @@ Return only the score as a plain number.`,
 		} `json:"candidates"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return 0, fmt.Errorf("failed to parse Gemini response: %w", err)
+		return EssayGrade{}, true, fmt.Errorf("failed to parse Gemini response: %w", err)
 	}
 
 	if len(result.Candidates) == 0 || len(result.Candidates[0].Content.Parts) == 0 {
-		return 0, fmt.Errorf("no candidates returned from Gemini")
+		return EssayGrade{}, true, fmt.Errorf("no candidates returned from Gemini")
 	}
 
-	var score float64
-	_, err = fmt.Sscan(result.Candidates[0].Content.Parts[0].Text, &score)
-	if err != nil {
-		return 0, fmt.Errorf("failed to parse score: %w", err)
+	var grade EssayGrade
+	if err := json.Unmarshal([]byte(result.Candidates[0].Content.Parts[0].Text), &grade); err != nil {
+		return EssayGrade{}, true, fmt.Errorf("failed to parse score: %w", err)
 	}
+	return grade, false, nil
+}
+
+const geminiFeedbackInstruction = `As the feedback, write two or three sentences to the student about what their answer was missing and how to improve it.`
 
-	// Ensure score is within range
-	if score > maxScore {
-		score = maxScore
+func geminiPrompt(req EssayRequest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Evaluate the following essay answer:\nQuestion: %q\nStudent Answer: %q\n", req.Question, req.Answer)
+	if req.Reference != "" {
+		fmt.Fprintf(&b, "Reference Answer: %q\n", req.Reference)
 	}
-	if score < 0 {
-		score = 0
+	if len(req.Keywords) > 0 {
+		fmt.Fprintf(&b, "Key points a good answer mentions: %s\n", strings.Join(req.Keywords, ", "))
+	}
+
+	if len(req.Rubric) == 0 {
+		fmt.Fprintf(&b, `
+Assign a score from 0 to %g based on how well the answer satisfies the question.
+Give a full score if highly relevant, partial if somewhat relevant, low if irrelevant.
+Explain the score in one or two sentences as the justification.
+`+geminiFeedbackInstruction, req.MaxScore)
+		return b.String()
+	}
+
+	b.WriteString("\nGrade the answer on each rubric criterion:\n")
+	for _, criterion := range req.Rubric {
+		fmt.Fprintf(&b, "- %s (0 to %g points)\n", criterion.Name, req.MaxScore*criterion.Weight/100)
+		for _, level := range criterion.Levels {
+			fmt.Fprintf(&b, "  - %s (%g%% of the points): %s\n", level.Label, level.Credit*100, level.Description)
+		}
 	}
-	return score, nil
+	fmt.Fprintf(&b, `
+For every criterion give its name, the level the answer reaches, the points within that criterion's range
+and a one sentence justification. The score is the sum of the criterion points, at most %g.
+Explain the overall score in one or two sentences as the justification.
+`+geminiFeedbackInstruction, req.MaxScore)
+	return b.String()
 }
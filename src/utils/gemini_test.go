@@ -0,0 +1,197 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testGeminiKey = "test-secret-key"
+
+// geminiServer answers every request with respond and counts the requests it received
+func geminiServer(t *testing.T, respond func(w http.ResponseWriter, r *http.Request, call int32)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r, calls.Add(1))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func writeGeminiGrade(w http.ResponseWriter, text string) {
+	json.NewEncoder(w).Encode(map[string]any{
+		"candidates": []map[string]any{
+			{"content": map[string]any{"parts": []map[string]string{{"text": text}}}},
+		},
+	})
+}
+
+func testGeminiGrader(url string) *GeminiGrader {
+	return &GeminiGrader{URL: url, APIKey: testGeminiKey, Timeout: time.Second, MaxRetries: 2, Backoff: time.Millisecond}
+}
+
+func TestGeminiGraderSendsKeyInHeader(t *testing.T) {
+	server, _ := geminiServer(t, func(w http.ResponseWriter, r *http.Request, call int32) {
+		if got := r.Header.Get("x-goog-api-key"); got != testGeminiKey {
+			t.Errorf("x-goog-api-key = %q, want the API key", got)
+		}
+		if strings.Contains(r.URL.RawQuery, testGeminiKey) {
+			t.Errorf("query %q contains the API key", r.URL.RawQuery)
+		}
+		writeGeminiGrade(w, `{"score": 3, "justification": "Relevant", "feedback": "Mention chlorophyll"}`)
+	})
+
+	grade, err := testGeminiGrader(server.URL).GradeEssay(context.Background(), EssayRequest{Question: "Photosynthesis?", Answer: "Light", MaxScore: 5})
+	if err != nil {
+		t.Fatalf("GradeEssay() error = %v", err)
+	}
+	if grade.Score != 3 || grade.Justification != "Relevant" || grade.Feedback != "Mention chlorophyll" {
+		t.Errorf("GradeEssay() = %+v", grade)
+	}
+}
+
+func TestGeminiGraderRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		respond   func(w http.ResponseWriter, call int32)
+		wantCalls int32
+		wantErr   string
+	}{
+		{
+			name: "server error then success",
+			respond: func(w http.ResponseWriter, call int32) {
+				if call < 3 {
+					http.Error(w, "overloaded", http.StatusServiceUnavailable)
+					return
+				}
+				writeGeminiGrade(w, `{"score": 2, "justification": "ok", "feedback": "ok"}`)
+			},
+			wantCalls: 3,
+		},
+		{
+			name: "rate limited until out of retries",
+			respond: func(w http.ResponseWriter, call int32) {
+				http.Error(w, "slow down", http.StatusTooManyRequests)
+			},
+			wantCalls: 3,
+			wantErr:   "429",
+		},
+		{
+			name: "client error is not retried",
+			respond: func(w http.ResponseWriter, call int32) {
+				http.Error(w, "bad request", http.StatusBadRequest)
+			},
+			wantCalls: 1,
+			wantErr:   "400",
+		},
+		{
+			name: "body that is not JSON",
+			respond: func(w http.ResponseWriter, call int32) {
+				w.Write([]byte("<html>oops</html>"))
+			},
+			wantCalls: 3,
+			wantErr:   "failed to parse Gemini response",
+		},
+		{
+			name: "no candidates",
+			respond: func(w http.ResponseWriter, call int32) {
+				w.Write([]byte(`{"candidates": []}`))
+			},
+			wantCalls: 3,
+			wantErr:   "no candidates",
+		},
+		{
+			name: "candidate text that is not a grade",
+			respond: func(w http.ResponseWriter, call int32) {
+				writeGeminiGrade(w, "The answer deserves 4 points.")
+			},
+			wantCalls: 3,
+			wantErr:   "failed to parse score",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := geminiServer(t, func(w http.ResponseWriter, r *http.Request, call int32) {
+				tt.respond(w, call)
+			})
+
+			_, err := testGeminiGrader(server.URL).GradeEssay(context.Background(), EssayRequest{Answer: "Light", MaxScore: 5})
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("GradeEssay() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("GradeEssay() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("Gemini called %d times, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestGeminiGraderBacksOffExponentially(t *testing.T) {
+	server, _ := geminiServer(t, func(w http.ResponseWriter, r *http.Request, call int32) {
+		http.Error(w, "overloaded", http.StatusInternalServerError)
+	})
+	grader := testGeminiGrader(server.URL)
+	grader.Backoff = 20 * time.Millisecond
+
+	start := time.Now()
+	if _, err := grader.GradeEssay(context.Background(), EssayRequest{Answer: "Light", MaxScore: 5}); err == nil {
+		t.Fatal("GradeEssay() error = nil, want the server error")
+	}
+	// Dua kali menunggu: 20ms lalu 40ms
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("retries took %v, want at least 60ms of backoff", elapsed)
+	}
+}
+
+func TestGeminiGraderTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server, calls := geminiServer(t, func(w http.ResponseWriter, r *http.Request, call int32) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	defer close(release)
+
+	grader := testGeminiGrader(server.URL)
+	grader.Timeout = 20 * time.Millisecond
+	grader.MaxRetries = 1
+
+	_, err := grader.GradeEssay(context.Background(), EssayRequest{Answer: "Light", MaxScore: 5})
+	if err == nil || !strings.Contains(err.Error(), "failed to call Gemini API") {
+		t.Fatalf("GradeEssay() error = %v, want a timeout", err)
+	}
+	if strings.Contains(err.Error(), testGeminiKey) {
+		t.Errorf("error %q leaks the API key", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("Gemini called %d times, want 2", got)
+	}
+}
+
+func TestGeminiGraderStopsWhenCanceled(t *testing.T) {
+	server, calls := geminiServer(t, func(w http.ResponseWriter, r *http.Request, call int32) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	})
+	grader := testGeminiGrader(server.URL)
+	grader.Backoff = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := grader.GradeEssay(ctx, EssayRequest{Answer: "Light", MaxScore: 5}); err != context.DeadlineExceeded {
+		t.Errorf("GradeEssay() error = %v, want context.DeadlineExceeded", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Gemini called %d times, want 1", got)
+	}
+}
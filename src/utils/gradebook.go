@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"math"
+	"project-ppl-be/src/models"
+	"sort"
+)
+
+// CategoryAverage averages the percentages of a category after leaving out the dropLowest lowest ones.
+// At least one percentage is always kept; it returns nil when there are none.
+func CategoryAverage(percents []float64, dropLowest int) *float64 {
+	if len(percents) == 0 {
+		return nil
+	}
+	sorted := make([]float64, len(percents))
+	copy(sorted, percents)
+	sort.Float64s(sorted)
+
+	dropLowest = min(dropLowest, len(sorted)-1)
+	average := mean(sorted[dropLowest:])
+	return &average
+}
+
+// WeightedFinalGrade weighs the category averages with the class's settings. A category without scores yet
+// leaves its weight out, so the grade is a running one; it returns nil when neither category has scores.
+func WeightedFinalGrade(settings models.GradebookSettings, exerciseAverage, examAverage *float64) *float64 {
+	var total, weights float64
+	if exerciseAverage != nil {
+		total += *exerciseAverage * settings.Exercise_Weight
+		weights += settings.Exercise_Weight
+	}
+	if examAverage != nil {
+		total += *examAverage * settings.Exam_Weight
+		weights += settings.Exam_Weight
+	}
+	if weights == 0 {
+		return nil
+	}
+	grade := total / weights
+	return &grade
+}
+
+// RemedialPercent is the percentage recorded on an exam after a remedial exam, following the remedial policy.
+// It never drops below the original percentage.
+func RemedialPercent(policy string, original, remedial, kkm float64) float64 {
+	var recorded float64
+	switch policy {
+	case models.RemedialHighest:
+		recorded = remedial
+	case models.RemedialAverage:
+		recorded = (original + remedial) / 2
+	default:
+		recorded = math.Min(remedial, kkm)
+	}
+	return math.Max(original, recorded)
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"project-ppl-be/src/models"
+	"testing"
+)
+
+func TestCategoryAverage(t *testing.T) {
+	if got := CategoryAverage(nil, 1); got != nil {
+		t.Errorf("no scores = %v, want nil", *got)
+	}
+	if got := CategoryAverage([]float64{40, 90, 80}, 1); got == nil || *got != 85 {
+		t.Errorf("drop lowest = %v, want 85", got)
+	}
+	// Nilai terakhir tidak pernah dibuang
+	if got := CategoryAverage([]float64{60}, 2); got == nil || *got != 60 {
+		t.Errorf("drop more than scored = %v, want 60", got)
+	}
+}
+
+func TestWeightedFinalGrade(t *testing.T) {
+	settings := models.GradebookSettings{Exercise_Weight: 30, Exam_Weight: 70}
+	exercises, exams := 80.0, 90.0
+
+	if got := WeightedFinalGrade(settings, &exercises, &exams); got == nil || *got != 87 {
+		t.Errorf("both categories = %v, want 87", got)
+	}
+	if got := WeightedFinalGrade(settings, &exercises, nil); got == nil || *got != 80 {
+		t.Errorf("exercises only = %v, want 80", got)
+	}
+	if got := WeightedFinalGrade(settings, nil, nil); got != nil {
+		t.Errorf("no scores = %v, want nil", *got)
+	}
+}
+
+func TestRemedialPercent(t *testing.T) {
+	tests := []struct {
+		policy             string
+		original, remedial float64
+		want               float64
+	}{
+		{models.RemedialCapAtKKM, 50, 90, 75},
+		{models.RemedialCapAtKKM, 50, 60, 60},
+		{models.RemedialCapAtKKM, 50, 40, 50},
+		{models.RemedialHighest, 50, 90, 90},
+		{models.RemedialAverage, 50, 90, 70},
+		{models.RemedialAverage, 60, 20, 60},
+	}
+	for _, tt := range tests {
+		if got := RemedialPercent(tt.policy, tt.original, tt.remedial, 75); got != tt.want {
+			t.Errorf("RemedialPercent(%s, %g, %g) = %g, want %g", tt.policy, tt.original, tt.remedial, got, tt.want)
+		}
+	}
+}
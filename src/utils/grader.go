@@ -0,0 +1,229 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"project-ppl-be/src/models"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Essay grader names accepted by ESSAY_GRADER
+const (
+	EssayGraderGemini = "gemini"
+	EssayGraderLocal  = "local"
+)
+
+// EssayRequest is a single essay answer to be graded
+type EssayRequest struct {
+	Question string
+	Answer   string
+	MaxScore float64
+	// Reference is the model answer written by the teacher, if any
+	Reference string
+	// Keywords are the key terms a good answer mentions
+	Keywords []string
+	// Rubric splits the max score into criteria graded separately
+	Rubric []models.RubricCriterion
+}
+
+// EssayGrade is the result of grading an essay answer. With a rubric the score is the sum of the criteria.
+// Justification is meant for teachers, Feedback for the student.
+type EssayGrade struct {
+	Score         float64                 `json:"score"`
+	Justification string                  `json:"justification"`
+	Criteria      []models.CriterionScore `json:"criteria,omitempty"`
+	// Feedback tells the student what was missing and how to improve
+	Feedback string `json:"feedback"`
+}
+
+// EssayGrader grades essay answers
+type EssayGrader interface {
+	GradeEssay(ctx context.Context, req EssayRequest) (EssayGrade, error)
+}
+
+// Grader is the essay grader configured by ConfigureEssayGrader
+var Grader EssayGrader
+
+// ConfigureEssayGrader selects the essay grader from ESSAY_GRADER ("gemini" by default, or "local")
+// and stores it in Grader. The Gemini grader reads GEMINI_API_URL, GEMINI_API_KEY,
+// GEMINI_TIMEOUT_SECONDS and GEMINI_MAX_RETRIES.
+func ConfigureEssayGrader() EssayGrader {
+	grader, err := NewEssayGrader(os.Getenv("ESSAY_GRADER"))
+	if err != nil {
+		log.Fatalf("Failed to configure essay grader: %v", err)
+	}
+	Grader = grader
+	return grader
+}
+
+// NewEssayGrader creates the essay grader with the given name
+func NewEssayGrader(name string) (EssayGrader, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", EssayGraderGemini:
+		return &GeminiGrader{
+			URL:        os.Getenv("GEMINI_API_URL"),
+			APIKey:     os.Getenv("GEMINI_API_KEY"),
+			Timeout:    time.Duration(envInt("GEMINI_TIMEOUT_SECONDS", 30)) * time.Second,
+			MaxRetries: envInt("GEMINI_MAX_RETRIES", 3),
+			Backoff:    time.Second,
+		}, nil
+	case EssayGraderLocal:
+		return LocalGrader{}, nil
+	default:
+		return nil, fmt.Errorf("unknown essay grader %q", name)
+	}
+}
+
+// GradeEssay grades an essay with the configured grader and keeps the score within 0 and the max score
+func GradeEssay(ctx context.Context, req EssayRequest) (EssayGrade, error) {
+	if Grader == nil {
+		return EssayGrade{}, errors.New("essay grader is not configured")
+	}
+	grade, err := Grader.GradeEssay(ctx, req)
+	if err != nil {
+		return EssayGrade{}, err
+	}
+	if len(req.Rubric) > 0 {
+		return applyRubric(req, grade), nil
+	}
+	grade.Criteria = nil
+	grade.Score = math.Max(0, math.Min(grade.Score, req.MaxScore))
+	return grade, nil
+}
+
+// applyRubric keeps one score per rubric criterion, in rubric order and within the criterion points,
+// and makes the essay score their sum. A criterion the grader left out scores 0.
+func applyRubric(req EssayRequest, grade EssayGrade) EssayGrade {
+	given := make(map[string]models.CriterionScore, len(grade.Criteria))
+	for _, score := range grade.Criteria {
+		given[strings.ToLower(strings.TrimSpace(score.Name))] = score
+	}
+
+	criteria := make([]models.CriterionScore, len(req.Rubric))
+	var total float64
+	for i, criterion := range req.Rubric {
+		score := given[strings.ToLower(strings.TrimSpace(criterion.Name))]
+		score.Name = criterion.Name
+		score.Max_Score = req.MaxScore * criterion.Weight / 100
+		score.Score = math.Max(0, math.Min(score.Score, score.Max_Score))
+		total += score.Score
+		criteria[i] = score
+	}
+
+	grade.Criteria = criteria
+	grade.Score = total
+	return grade
+}
+
+// LocalGrader is a deterministic grader for development and tests. It gives one share of the
+// max score per keyword found in the answer, or, without keywords, scores the share of the
+// reference answer's words that the answer uses. Every rubric criterion gets the same share.
+type LocalGrader struct{}
+
+func (LocalGrader) GradeEssay(ctx context.Context, req EssayRequest) (EssayGrade, error) {
+	credit, justification, feedback := localCredit(req)
+
+	grade := EssayGrade{Score: req.MaxScore * credit, Justification: justification, Feedback: feedback}
+	for _, criterion := range req.Rubric {
+		grade.Criteria = append(grade.Criteria, models.CriterionScore{
+			Name:  criterion.Name,
+			Score: req.MaxScore * criterion.Weight / 100 * credit,
+			Level: rubricLevel(criterion.Levels, credit),
+		})
+	}
+	return grade, nil
+}
+
+// localCredit returns the share of the max score the answer earns, why, and feedback for the student
+func localCredit(req EssayRequest) (float64, string, string) {
+	answer := wordSet(req.Answer)
+
+	if len(req.Keywords) > 0 {
+		var found, missing []string
+		for _, keyword := range req.Keywords {
+			if containsWords(answer, keyword) {
+				found = append(found, keyword)
+			} else {
+				missing = append(missing, keyword)
+			}
+		}
+		feedback := "Your answer covers all the key points."
+		if len(missing) > 0 {
+			feedback = "Your answer does not mention: " + strings.Join(missing, ", ") + ". Explain how these relate to the question to improve it."
+		}
+		return float64(len(found)) / float64(len(req.Keywords)),
+			fmt.Sprintf("Mentions %d of %d keywords: %s", len(found), len(req.Keywords), strings.Join(found, ", ")),
+			feedback
+	}
+
+	reference := wordSet(req.Reference)
+	if len(reference) == 0 {
+		return 0, "No keywords or reference answer to grade against", ""
+	}
+
+	var shared int
+	for word := range reference {
+		if answer[word] {
+			shared++
+		}
+	}
+	credit := float64(shared) / float64(len(reference))
+	feedback := "Your answer matches the expected answer well."
+	if credit < 1 {
+		feedback = fmt.Sprintf("Your answer covers about %.0f%% of the expected answer. Add more of its main ideas and explain them in your own words.", credit*100)
+	}
+	return credit, fmt.Sprintf("Uses %d of %d words of the reference answer", shared, len(reference)), feedback
+}
+
+// rubricLevel returns the label of the highest level whose credit the answer reaches
+func rubricLevel(levels []models.RubricLevel, credit float64) string {
+	var label string
+	best := -1.0
+	for _, level := range levels {
+		if level.Credit <= credit && level.Credit > best {
+			label, best = level.Label, level.Credit
+		}
+	}
+	return label
+}
+
+// wordSet returns the lower case words of a text
+func wordSet(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
+
+// containsWords reports whether every word of a keyword, which may be a phrase, is in the set
+func containsWords(set map[string]bool, keyword string) bool {
+	words := wordSet(keyword)
+	if len(words) == 0 {
+		return false
+	}
+	for word := range words {
+		if !set[word] {
+			return false
+		}
+	}
+	return true
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
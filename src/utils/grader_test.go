@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"context"
+	"project-ppl-be/src/models"
+	"testing"
+)
+
+func TestLocalGrader(t *testing.T) {
+	tests := []struct {
+		name string
+		req  EssayRequest
+		want float64
+	}{
+		{
+			name: "keywords",
+			req: EssayRequest{
+				Answer:   "Plants use light and water to make sugar.",
+				MaxScore: 3,
+				Keywords: []string{"light", "water", "carbon dioxide"},
+			},
+			want: 2,
+		},
+		{
+			name: "reference answer",
+			req: EssayRequest{
+				Answer:    "The mitochondria makes energy",
+				MaxScore:  4,
+				Reference: "Mitochondria produce energy for the cell",
+			},
+			want: 2,
+		},
+		{
+			name: "nothing to grade against",
+			req:  EssayRequest{Answer: "Anything", MaxScore: 5},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grade, err := LocalGrader{}.GradeEssay(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if grade.Score != tt.want {
+				t.Errorf("got score %v, want %v (%s)", grade.Score, tt.want, grade.Justification)
+			}
+		})
+	}
+}
+
+func TestNewEssayGraderRejectsUnknownName(t *testing.T) {
+	if _, err := NewEssayGrader("openai"); err == nil {
+		t.Errorf("expected an error for an unknown grader")
+	}
+}
+
+func TestGradeEssayScoresRubricCriteria(t *testing.T) {
+	Grader = LocalGrader{}
+	defer func() { Grader = nil }()
+
+	grade, err := GradeEssay(context.Background(), EssayRequest{
+		Answer:   "Plants need light and water",
+		MaxScore: 10,
+		Keywords: []string{"light", "water"},
+		Rubric: []models.RubricCriterion{
+			{Name: "Content", Weight: 40, Levels: []models.RubricLevel{{Label: "Good", Credit: 1}, {Label: "Poor", Credit: 0}}},
+			{Name: "Argument", Weight: 30},
+			{Name: "Language", Weight: 30},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(grade.Criteria) != 3 {
+		t.Fatalf("got %d criteria, want 3", len(grade.Criteria))
+	}
+	content := grade.Criteria[0]
+	if content.Score != 4 || content.Max_Score != 4 || content.Level != "Good" {
+		t.Errorf("content criterion = %+v, want 4/4 at level Good", content)
+	}
+	if grade.Score != 10 {
+		t.Errorf("got score %v, want the sum of the criteria 10", grade.Score)
+	}
+}
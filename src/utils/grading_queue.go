@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project-ppl-be/src/models"
+	"sync"
+	"time"
+)
+
+// GradingTask is one claimed essay of a grading job. Attempts counts this try.
+type GradingTask struct {
+	ID       int
+	Attempts int
+	Essay    EssayRequest
+	// Err is set when the essay cannot be graded at all, e.g. because its question was removed
+	Err error
+}
+
+// GradingQueue stores the essays waiting to be graded. Claim hands every task to a single caller
+// until the task is recorded with Done or Retry.
+type GradingQueue interface {
+	Claim(ctx context.Context, limit int) ([]GradingTask, error)
+	Done(ctx context.Context, task GradingTask, grade EssayGrade) error
+	Retry(ctx context.Context, task GradingTask, cause error, status string, after time.Duration) error
+	// CompleteJobs saves the grade of every job whose essays are all graded
+	CompleteJobs(ctx context.Context) error
+}
+
+// GradingQueueConfig bounds how much ProcessGradingQueue does per run
+type GradingQueueConfig struct {
+	// Workers is the number of essays graded at the same time
+	Workers int
+	// BatchSize is the number of essays claimed per run
+	BatchSize int
+	// MaxAttempts is how many times an essay is tried before it fails its job
+	MaxAttempts int
+	// Backoff is the wait after the first failure; it doubles with every failure after that
+	Backoff time.Duration
+}
+
+// ProcessGradingQueue claims a batch of essays, grades them with at most cfg.Workers at a time
+// and records every grade or failure, then completes the jobs that are fully graded.
+// It returns the number of essays claimed.
+func ProcessGradingQueue(ctx context.Context, queue GradingQueue, grade func(context.Context, EssayRequest) (EssayGrade, error), cfg GradingQueueConfig) (int, error) {
+	tasks, err := queue.Claim(ctx, cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+		sem  = make(chan struct{}, max(cfg.Workers, 1))
+	)
+	for _, task := range tasks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := gradeTask(ctx, queue, grade, cfg, task); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("grading job item %d: %w", task.ID, err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := queue.CompleteJobs(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return len(tasks), errors.Join(errs...)
+}
+
+// gradeTask grades one essay and records the grade, or the error and when to try again
+func gradeTask(ctx context.Context, queue GradingQueue, grade func(context.Context, EssayRequest) (EssayGrade, error), cfg GradingQueueConfig, task GradingTask) error {
+	gradeErr := task.Err
+	if gradeErr == nil {
+		var result EssayGrade
+		result, gradeErr = grade(ctx, task.Essay)
+		if gradeErr == nil {
+			return queue.Done(ctx, task, result)
+		}
+	}
+
+	status, after := GradingRetry(task.Attempts, cfg.MaxAttempts, cfg.Backoff)
+	return queue.Retry(ctx, task, gradeErr, status, after)
+}
+
+// GradingRetry returns the status of an essay that failed on its attempts-th try and how long to wait
+// before trying again. The wait doubles with every failure; after maxAttempts tries the essay fails.
+func GradingRetry(attempts, maxAttempts int, backoff time.Duration) (string, time.Duration) {
+	if attempts >= maxAttempts {
+		return models.GradingItemFailed, 0
+	}
+	return models.GradingItemPending, backoff << max(attempts-1, 0)
+}
+
+// GradedItem is the recorded result of one essay of a grading job
+type GradedItem struct {
+	Key    string
+	Status string
+	Grade  EssayGrade
+	Error  string
+}
+
+// CollectGradedEssays returns the grades of the graded essays of a job by question key,
+// and an error naming the first essay that ran out of attempts
+func CollectGradedEssays(items []GradedItem) (map[string]EssayGrade, error) {
+	essays := make(map[string]EssayGrade)
+	var failure error
+	for _, item := range items {
+		if item.Status == models.GradingItemFailed {
+			if failure == nil {
+				failure = fmt.Errorf("question %s: %s", item.Key, item.Error)
+			}
+			continue
+		}
+		essays[item.Key] = item.Grade
+	}
+	return essays, failure
+}
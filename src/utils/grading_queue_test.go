@@ -0,0 +1,233 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"project-ppl-be/src/models"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeGradingQueue keeps tasks in memory. Claim hands out pending tasks whose wait is over and
+// marks them running, so a task is never claimed twice while it is being graded.
+type fakeGradingQueue struct {
+	mu        sync.Mutex
+	now       time.Time
+	tasks     []*fakeGradingTask
+	completed int
+}
+
+type fakeGradingTask struct {
+	task    GradingTask
+	status  string
+	nextAt  time.Time
+	grade   EssayGrade
+	lastErr string
+	waits   []time.Duration
+}
+
+func newFakeGradingQueue(answers ...string) *fakeGradingQueue {
+	q := &fakeGradingQueue{now: time.Now()}
+	for i, answer := range answers {
+		q.tasks = append(q.tasks, &fakeGradingTask{
+			task:   GradingTask{ID: i + 1, Essay: EssayRequest{Answer: answer, MaxScore: 5}},
+			status: models.GradingItemPending,
+		})
+	}
+	return q
+}
+
+func (q *fakeGradingQueue) Claim(ctx context.Context, limit int) ([]GradingTask, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var claimed []GradingTask
+	for _, t := range q.tasks {
+		if len(claimed) == limit {
+			break
+		}
+		if t.status == models.GradingItemPending && !t.nextAt.After(q.now) {
+			t.status = models.GradingItemRunning
+			t.task.Attempts++
+			claimed = append(claimed, t.task)
+		}
+	}
+	return claimed, nil
+}
+
+func (q *fakeGradingQueue) Done(ctx context.Context, task GradingTask, grade EssayGrade) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	t := q.tasks[task.ID-1]
+	t.status, t.grade = models.GradingItemDone, grade
+	return nil
+}
+
+func (q *fakeGradingQueue) Retry(ctx context.Context, task GradingTask, cause error, status string, after time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	t := q.tasks[task.ID-1]
+	t.status, t.lastErr, t.nextAt = status, cause.Error(), q.now.Add(after)
+	t.waits = append(t.waits, after)
+	return nil
+}
+
+func (q *fakeGradingQueue) CompleteJobs(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, t := range q.tasks {
+		if t.status == models.GradingItemPending || t.status == models.GradingItemRunning {
+			return nil
+		}
+	}
+	q.completed++
+	return nil
+}
+
+var testQueueConfig = GradingQueueConfig{Workers: 2, BatchSize: 3, MaxAttempts: 3, Backoff: 30 * time.Second}
+
+func TestProcessGradingQueueClaimsABatch(t *testing.T) {
+	queue := newFakeGradingQueue("a", "b", "c", "d", "e")
+	var running, busiest atomic.Int32
+	grade := func(ctx context.Context, req EssayRequest) (EssayGrade, error) {
+		n := running.Add(1)
+		for {
+			old := busiest.Load()
+			if n <= old || busiest.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		running.Add(-1)
+		return EssayGrade{Score: 4, Feedback: "good " + req.Answer}, nil
+	}
+
+	claimed, err := ProcessGradingQueue(context.Background(), queue, grade, testQueueConfig)
+	if err != nil {
+		t.Fatalf("ProcessGradingQueue() error = %v", err)
+	}
+	if claimed != 3 {
+		t.Errorf("claimed %d essays, want the batch size 3", claimed)
+	}
+	if got := busiest.Load(); got > 2 {
+		t.Errorf("%d essays graded at once, want at most 2 workers", got)
+	}
+	for i, want := range []string{models.GradingItemDone, models.GradingItemDone, models.GradingItemDone, models.GradingItemPending, models.GradingItemPending} {
+		if got := queue.tasks[i].status; got != want {
+			t.Errorf("task %d status = %s, want %s", i+1, got, want)
+		}
+	}
+	if got := queue.tasks[0].grade.Feedback; got != "good a" {
+		t.Errorf("task 1 feedback = %q, want the grade of its own answer", got)
+	}
+	if queue.completed != 0 {
+		t.Error("jobs completed while essays are still pending")
+	}
+
+	// Run berikutnya mengambil sisanya lalu menyelesaikan job
+	if claimed, err := ProcessGradingQueue(context.Background(), queue, grade, testQueueConfig); err != nil || claimed != 2 {
+		t.Fatalf("second run claimed %d, error = %v, want 2", claimed, err)
+	}
+	if queue.completed != 1 {
+		t.Errorf("jobs completed %d times, want once after every essay is graded", queue.completed)
+	}
+}
+
+func TestProcessGradingQueueRetriesWithBackoff(t *testing.T) {
+	queue := newFakeGradingQueue("a")
+	failing := func(ctx context.Context, req EssayRequest) (EssayGrade, error) {
+		return EssayGrade{}, errors.New("Gemini API returned 503")
+	}
+
+	for run := 1; run <= 3; run++ {
+		if _, err := ProcessGradingQueue(context.Background(), queue, failing, testQueueConfig); err != nil {
+			t.Fatalf("run %d error = %v", run, err)
+		}
+		// Tugas belum bisa diambil lagi sebelum waktu tunggunya habis
+		if claimed, _ := ProcessGradingQueue(context.Background(), queue, failing, testQueueConfig); claimed != 0 {
+			t.Fatalf("run %d: task claimed again before its backoff ended", run)
+		}
+		queue.now = queue.now.Add(time.Hour)
+	}
+
+	task := queue.tasks[0]
+	if task.status != models.GradingItemFailed || task.task.Attempts != 3 {
+		t.Errorf("task = %s after %d attempts, want Failed after 3", task.status, task.task.Attempts)
+	}
+	if task.lastErr != "Gemini API returned 503" {
+		t.Errorf("recorded error = %q", task.lastErr)
+	}
+	if want := []time.Duration{30 * time.Second, time.Minute, 0}; !reflect.DeepEqual(task.waits, want) {
+		t.Errorf("waits = %v, want %v", task.waits, want)
+	}
+	if queue.completed == 0 {
+		t.Error("job was not completed once its essay failed")
+	}
+}
+
+func TestProcessGradingQueueSkipsUngradableTasks(t *testing.T) {
+	queue := newFakeGradingQueue("a")
+	queue.tasks[0].task.Err = errors.New("question no longer exists")
+	called := false
+	grade := func(ctx context.Context, req EssayRequest) (EssayGrade, error) {
+		called = true
+		return EssayGrade{}, nil
+	}
+
+	if _, err := ProcessGradingQueue(context.Background(), queue, grade, testQueueConfig); err != nil {
+		t.Fatalf("ProcessGradingQueue() error = %v", err)
+	}
+	if called {
+		t.Error("graded an essay whose question no longer exists")
+	}
+	if got := queue.tasks[0]; got.status != models.GradingItemPending || got.lastErr != "question no longer exists" {
+		t.Errorf("task = %s %q, want it retried with the error", got.status, got.lastErr)
+	}
+}
+
+func TestGradingRetry(t *testing.T) {
+	tests := []struct {
+		attempts int
+		status   string
+		after    time.Duration
+	}{
+		{1, models.GradingItemPending, 30 * time.Second},
+		{2, models.GradingItemPending, time.Minute},
+		{3, models.GradingItemPending, 2 * time.Minute},
+		{4, models.GradingItemFailed, 0},
+		{5, models.GradingItemFailed, 0},
+	}
+	for _, tt := range tests {
+		status, after := GradingRetry(tt.attempts, 4, 30*time.Second)
+		if status != tt.status || after != tt.after {
+			t.Errorf("GradingRetry(%d) = %s %v, want %s %v", tt.attempts, status, after, tt.status, tt.after)
+		}
+	}
+}
+
+func TestCollectGradedEssays(t *testing.T) {
+	essays, err := CollectGradedEssays([]GradedItem{
+		{Key: "1", Status: models.GradingItemDone, Grade: EssayGrade{Score: 3, Feedback: "ok"}},
+		{Key: "2", Status: models.GradingItemDone, Grade: EssayGrade{Score: 1}},
+	})
+	if err != nil {
+		t.Fatalf("CollectGradedEssays() error = %v", err)
+	}
+	if len(essays) != 2 || essays["1"].Score != 3 || essays["1"].Feedback != "ok" || essays["2"].Score != 1 {
+		t.Errorf("CollectGradedEssays() = %+v", essays)
+	}
+
+	essays, err = CollectGradedEssays([]GradedItem{
+		{Key: "1", Status: models.GradingItemDone, Grade: EssayGrade{Score: 3}},
+		{Key: "2", Status: models.GradingItemFailed, Error: "timeout"},
+		{Key: "3", Status: models.GradingItemFailed, Error: "429"},
+	})
+	if err == nil || err.Error() != "question 2: timeout" {
+		t.Errorf("CollectGradedEssays() error = %v, want the first failed question", err)
+	}
+	if _, ok := essays["2"]; ok || essays["1"].Score != 3 {
+		t.Errorf("CollectGradedEssays() = %+v, want only the graded essays", essays)
+	}
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"math"
+	"project-ppl-be/src/models"
+	"sort"
+)
+
+// discriminationGroup is the share of students in the upper and lower groups of the discrimination index
+const discriminationGroup = 0.27
+
+// AnalyzeItems computes the item statistics of every question from one graded attempt per student
+func AnalyzeItems(content models.QuestionContent, attempts []models.GradedDetail) models.ItemAnalysis {
+	ranked := make([]models.GradedDetail, len(attempts))
+	copy(ranked, attempts)
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Score > ranked[j].Score })
+
+	groupSize := int(math.Round(float64(len(ranked)) * discriminationGroup))
+	if len(ranked) >= 2 && groupSize == 0 {
+		groupSize = 1
+	}
+
+	analysis := models.ItemAnalysis{Students: len(ranked), Questions: make([]models.ItemStatistics, len(content.Questions))}
+	for i, q := range content.Questions {
+		stats := models.ItemStatistics{Number: q.Number, Type: q.Type, Prompt: q.Prompt, Max_Score: q.Weight}
+
+		// Soal yang tidak dijawab dihitung salah; esai yang belum dinilai dilewati
+		var credits, scores []float64
+		picks := make(map[string]int)
+		for _, attempt := range ranked {
+			result, ok := attempt.Detail[q.Key()]
+			if !ok || result.Pending {
+				continue
+			}
+			credits = append(credits, questionCredit(result, q.Weight))
+			scores = append(scores, result.Score)
+			if AnswerText(result.Answer) == "" {
+				stats.Omitted++
+				continue
+			}
+			stats.Responses++
+			picks[AnswerText(result.Answer)]++
+		}
+
+		if len(credits) > 0 {
+			difficulty := mean(credits)
+			average := mean(scores)
+			stats.Difficulty = &difficulty
+			stats.Average_Score = &average
+		}
+		if groupSize > 0 && len(ranked) >= 2*groupSize {
+			discrimination := groupCredit(ranked[:groupSize], q) - groupCredit(ranked[len(ranked)-groupSize:], q)
+			stats.Discrimination = &discrimination
+		}
+
+		if q.Type == models.QuestionTypeMultipleChoice {
+			stats.Distractors = make([]models.OptionFrequency, len(q.Options))
+			for j, option := range q.Options {
+				frequency := models.OptionFrequency{Key: option.Key, Text: option.Text, Correct: option.Key == q.Correct_Answer, Count: picks[option.Key]}
+				if stats.Responses > 0 {
+					frequency.Share = float64(frequency.Count) / float64(stats.Responses)
+				}
+				stats.Distractors[j] = frequency
+			}
+		}
+
+		analysis.Questions[i] = stats
+	}
+	return analysis
+}
+
+// questionCredit is the share of a question's points a result earned, between 0 and 1
+func questionCredit(result models.QuestionResult, weight float64) float64 {
+	if weight <= 0 {
+		return 0
+	}
+	return math.Min(math.Max(result.Score/weight, 0), 1)
+}
+
+// groupCredit is the average credit of a group of students on a question; unanswered counts as 0
+func groupCredit(group []models.GradedDetail, q models.Question) float64 {
+	var credits []float64
+	for _, attempt := range group {
+		result, ok := attempt.Detail[q.Key()]
+		if ok && result.Pending {
+			continue
+		}
+		credits = append(credits, questionCredit(result, q.Weight))
+	}
+	return mean(credits)
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
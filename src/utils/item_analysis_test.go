@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"project-ppl-be/src/models"
+	"testing"
+)
+
+func TestAnalyzeItems(t *testing.T) {
+	content := sampleContent()
+	attempt := func(total float64, choice string, mcScore, essayScore float64) models.GradedDetail {
+		return models.GradedDetail{Score: total, Detail: map[string]models.QuestionResult{
+			"1": {Type: models.QuestionTypeMultipleChoice, Answer: choice, Score: mcScore, Max_Score: 1, Correct: mcScore == 1},
+			"2": {Type: models.QuestionTypeEssay, Answer: "An essay", Score: essayScore, Max_Score: 2},
+		}}
+	}
+	attempts := []models.GradedDetail{
+		attempt(1, "a", 0, 1),
+		attempt(3, "b", 1, 2),
+		attempt(0, "", 0, 0),
+		attempt(2, "b", 1, 1),
+	}
+
+	got := AnalyzeItems(content, attempts)
+	if got.Students != 4 {
+		t.Fatalf("Students = %d, want 4", got.Students)
+	}
+
+	mc := got.Questions[0]
+	if mc.Responses != 3 || mc.Omitted != 1 {
+		t.Errorf("responses/omitted = %d/%d, want 3/1", mc.Responses, mc.Omitted)
+	}
+	if mc.Difficulty == nil || *mc.Difficulty != 0.5 {
+		t.Errorf("difficulty = %v, want 0.5", mc.Difficulty)
+	}
+	// Kelompok atas dan bawah masing-masing satu siswa
+	if mc.Discrimination == nil || *mc.Discrimination != 1 {
+		t.Errorf("discrimination = %v, want 1", mc.Discrimination)
+	}
+	if len(mc.Distractors) != 2 || mc.Distractors[0].Count != 1 || mc.Distractors[1].Count != 2 || !mc.Distractors[1].Correct {
+		t.Errorf("distractors = %+v, want a picked once and b, the correct option, twice", mc.Distractors)
+	}
+
+	essay := got.Questions[1]
+	if essay.Average_Score == nil || *essay.Average_Score != 1 {
+		t.Errorf("essay average = %v, want 1", essay.Average_Score)
+	}
+	if essay.Distractors != nil {
+		t.Errorf("essay distractors = %+v, want none", essay.Distractors)
+	}
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"project-ppl-be/src/models"
+)
+
+// ErrBankQuestionNotFound is returned when an exam or exercise references a missing bank question
+var ErrBankQuestionNotFound = errors.New("bank question not found")
+
+// AppendBankQuestions copies the bank questions with the given IDs to the end of content, in the order
+// of ids and numbered after the highest existing question number. Each copy remembers its bank question.
+func AppendBankQuestions(content *models.QuestionContent, ids []int, bank map[int]models.Question) error {
+	next := 0
+	for _, q := range content.Questions {
+		next = max(next, q.Number)
+	}
+
+	for _, id := range ids {
+		q, ok := bank[id]
+		if !ok {
+			return fmt.Errorf("%w: %d", ErrBankQuestionNotFound, id)
+		}
+		next++
+		q.Number = next
+		q.Bank_Question_ID = &id
+		content.Questions = append(content.Questions, q)
+	}
+	return nil
+}
+
+// BankQuestionUsages maps every bank question used in content to the number it currently has.
+// A bank question used more than once is recorded with its lowest number.
+func BankQuestionUsages(content models.QuestionContent) map[int]int {
+	usages := make(map[int]int)
+	for _, q := range content.Questions {
+		if q.Bank_Question_ID == nil {
+			continue
+		}
+		if number, ok := usages[*q.Bank_Question_ID]; !ok || q.Number < number {
+			usages[*q.Bank_Question_ID] = q.Number
+		}
+	}
+	return usages
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"errors"
+	"project-ppl-be/src/models"
+	"reflect"
+	"testing"
+)
+
+func TestAppendBankQuestions(t *testing.T) {
+	content := models.QuestionContent{Questions: []models.Question{
+		{Number: 1, Type: models.QuestionTypeEssay, Prompt: "Explain"},
+		{Number: 4, Type: models.QuestionTypeTrueFalse, Correct_Answer: "true"},
+	}}
+	bank := map[int]models.Question{
+		7: {Number: 1, Type: models.QuestionTypeMultipleChoice, Prompt: "2 + 2?", Correct_Answer: "4"},
+		9: {Number: 3, Type: models.QuestionTypeEssay, Prompt: "Describe"},
+	}
+
+	if err := AppendBankQuestions(&content, []int{9, 7}, bank); err != nil {
+		t.Fatalf("AppendBankQuestions() error = %v", err)
+	}
+	if len(content.Questions) != 4 {
+		t.Fatalf("got %d questions, want 4", len(content.Questions))
+	}
+	for i, want := range []struct{ number, bankID int }{{5, 9}, {6, 7}} {
+		q := content.Questions[2+i]
+		if q.Number != want.number || q.Bank_Question_ID == nil || *q.Bank_Question_ID != want.bankID {
+			t.Errorf("question %d = number %d from %v, want number %d from %d", 2+i, q.Number, q.Bank_Question_ID, want.number, want.bankID)
+		}
+	}
+	if bank[7].Bank_Question_ID != nil || bank[7].Number != 1 {
+		t.Error("AppendBankQuestions changed the bank question")
+	}
+
+	err := AppendBankQuestions(&content, []int{7, 8}, bank)
+	if !errors.Is(err, ErrBankQuestionNotFound) {
+		t.Errorf("missing bank question error = %v, want ErrBankQuestionNotFound", err)
+	}
+}
+
+func TestBankQuestionUsages(t *testing.T) {
+	seven, nine := 7, 9
+	content := models.QuestionContent{Questions: []models.Question{
+		{Number: 1, Type: models.QuestionTypeEssay},
+		{Number: 2, Bank_Question_ID: &nine},
+		{Number: 3, Bank_Question_ID: &seven},
+		{Number: 4, Bank_Question_ID: &nine},
+	}}
+	if got := BankQuestionUsages(content); !reflect.DeepEqual(got, map[int]int{7: 3, 9: 2}) {
+		t.Errorf("BankQuestionUsages() = %v, want map[7:3 9:2]", got)
+	}
+
+	// Soal yang dinomori ulang tetap tercatat dari soal bank yang sama
+	content.Questions = content.Questions[2:]
+	for i := range content.Questions {
+		content.Questions[i].Number = i + 1
+	}
+	if got := BankQuestionUsages(content); !reflect.DeepEqual(got, map[int]int{7: 1, 9: 2}) {
+		t.Errorf("BankQuestionUsages() after renumbering = %v, want map[7:1 9:2]", got)
+	}
+}
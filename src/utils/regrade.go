@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"encoding/json"
+	"math"
+	"project-ppl-be/src/models"
+	"reflect"
+	"sort"
+)
+
+// scoringFields are the question fields that decide a score
+type scoringFields struct {
+	Type            string
+	Weight          float64
+	Partial_Credit  bool
+	Correct_Answer  string
+	Correct_Answers []string
+	Numeric_Answer  *float64
+	Tolerance       float64
+	Tolerance_Type  string
+	Pairs           []models.MatchingPair
+	Blanks          []models.Blank
+}
+
+func scoringFieldsOf(q models.Question) scoringFields {
+	return scoringFields{
+		Type:            q.Type,
+		Weight:          q.Weight,
+		Partial_Credit:  q.PartialCreditEnabled(),
+		Correct_Answer:  q.Correct_Answer,
+		Correct_Answers: q.Correct_Answers,
+		Numeric_Answer:  q.Numeric_Answer,
+		Tolerance:       q.Tolerance,
+		Tolerance_Type:  q.Tolerance_Type,
+		Pairs:           q.Pairs,
+		Blanks:          q.Blanks,
+	}
+}
+
+// ScoringChanges returns the numbers of the questions scored differently in the new content:
+// their answer key, weight, partial credit or type changed, or they were added or removed.
+func ScoringChanges(old, new models.QuestionContent) []int {
+	before := make(map[int]scoringFields, len(old.Questions))
+	for _, q := range old.Questions {
+		before[q.Number] = scoringFieldsOf(q)
+	}
+
+	var changed []int
+	for _, q := range new.Questions {
+		fields, existed := before[q.Number]
+		if !existed || !reflect.DeepEqual(fields, scoringFieldsOf(q)) {
+			changed = append(changed, q.Number)
+		}
+		delete(before, q.Number)
+	}
+	for number := range before {
+		changed = append(changed, number)
+	}
+	sort.Ints(changed)
+	return changed
+}
+
+// RegradeDetail scores the answers stored in a score detail again against new content and returns the new total and detail.
+// Essays and questions a teacher overrode keep their score, capped at the new weight; an override records the new auto score.
+// Questions no longer in the content are dropped.
+func RegradeDetail(content models.QuestionContent, detail map[string]models.QuestionResult, negativeMarking float64) (float64, map[string]models.QuestionResult) {
+	var total float64
+	regraded := make(map[string]models.QuestionResult, len(content.Questions))
+
+	for _, q := range content.Questions {
+		old, had := detail[q.Key()]
+
+		var result models.QuestionResult
+		if IsAutoScored(q.Type) {
+			result = ScoreQuestion(q, old.Answer, negativeMarking)
+			if had && old.Auto_Score != nil {
+				auto := result.Score
+				result.Auto_Score = &auto
+				result.Score = math.Min(old.Score, q.Weight)
+				result.Correct = result.Score == q.Weight
+				result.Comment = old.Comment
+			}
+		} else {
+			result = old
+			result.Type = q.Type
+			result.Max_Score = q.Weight
+			result.Score = math.Min(old.Score, q.Weight)
+			result.Correct = result.Score == q.Weight
+		}
+
+		total += result.Score
+		regraded[q.Key()] = result
+	}
+	return total, regraded
+}
+
+// DetailChanged reports whether two score details differ once stored as JSON
+func DetailChanged(a, b map[string]models.QuestionResult) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	return errA != nil || errB != nil || string(aJSON) != string(bJSON)
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"project-ppl-be/src/models"
+	"reflect"
+	"testing"
+)
+
+func TestScoringChanges(t *testing.T) {
+	old := models.QuestionContent{Questions: []models.Question{
+		{Number: 1, Type: models.QuestionTypeMultipleChoice, Prompt: "2 + 2?", Correct_Answer: "3", Weight: 1},
+		{Number: 2, Type: models.QuestionTypeEssay, Prompt: "Explain", Weight: 5},
+		{Number: 3, Type: models.QuestionTypeTrueFalse, Correct_Answer: "true", Weight: 1},
+	}}
+	new := models.QuestionContent{Questions: []models.Question{
+		{Number: 1, Type: models.QuestionTypeMultipleChoice, Prompt: "What is 2 + 2?", Correct_Answer: "4", Weight: 1},
+		{Number: 2, Type: models.QuestionTypeEssay, Prompt: "Explain briefly", Weight: 5},
+	}}
+
+	if got := ScoringChanges(old, new); !reflect.DeepEqual(got, []int{1, 3}) {
+		t.Errorf("ScoringChanges() = %v, want [1 3]", got)
+	}
+}
+
+func TestRegradeDetail(t *testing.T) {
+	teacher := 1.0
+	content := models.QuestionContent{Questions: []models.Question{
+		{Number: 1, Type: models.QuestionTypeMultipleChoice, Correct_Answer: "4", Weight: 2},
+		{Number: 2, Type: models.QuestionTypeEssay, Weight: 3},
+		{Number: 3, Type: models.QuestionTypeMultipleChoice, Correct_Answer: "B", Weight: 2},
+	}}
+	detail := map[string]models.QuestionResult{
+		"1": {Type: models.QuestionTypeMultipleChoice, Answer: "4", Score: 0, Max_Score: 2},
+		"2": {Type: models.QuestionTypeEssay, Answer: "Because", Score: 4, Max_Score: 5},
+		"3": {Type: models.QuestionTypeMultipleChoice, Answer: "B", Score: 1, Max_Score: 2, Auto_Score: &teacher, Comment: "half credit"},
+		"4": {Type: models.QuestionTypeTrueFalse, Answer: "true", Score: 1, Max_Score: 1},
+	}
+
+	total, regraded := RegradeDetail(content, detail, 0)
+	if total != 6 {
+		t.Errorf("total = %g, want 6", total)
+	}
+	if got := regraded["1"].Score; got != 2 {
+		t.Errorf("corrected answer key score = %g, want 2", got)
+	}
+	if got := regraded["2"]; got.Score != 3 || got.Max_Score != 3 {
+		t.Errorf("essay = %g/%g, want 3/3", got.Score, got.Max_Score)
+	}
+	if got := regraded["3"]; got.Score != 1 || got.Auto_Score == nil || *got.Auto_Score != 2 || got.Comment != "half credit" {
+		t.Errorf("overridden question = %+v, want the teacher's score kept with a new auto score", got)
+	}
+	if _, ok := regraded["4"]; ok {
+		t.Error("removed question is still in the detail")
+	}
+}
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"project-ppl-be/src/models"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultPassingScore is the passing percentage used when a report does not set one
+const DefaultPassingScore = 75
+
+// histogramBucketSize is the width of every histogram bucket in percentage points
+const histogramBucketSize = 10
+
+// ScoreStatistics summarises percentages between 0 and 100. A percentage passes when it reaches passingScore.
+func ScoreStatistics(percents []float64, passingScore float64) models.ScoreStatistics {
+	stats := models.ScoreStatistics{Count: len(percents), Histogram: make([]models.HistogramBucket, 100/histogramBucketSize)}
+	for i := range stats.Histogram {
+		stats.Histogram[i] = models.HistogramBucket{From: float64(i * histogramBucketSize), To: float64((i + 1) * histogramBucketSize)}
+	}
+	if len(percents) == 0 {
+		return stats
+	}
+
+	sorted := make([]float64, len(percents))
+	copy(sorted, percents)
+	sort.Float64s(sorted)
+
+	stats.Min = sorted[0]
+	stats.Max = sorted[len(sorted)-1]
+	stats.Mean = mean(sorted)
+	if n := len(sorted); n%2 == 1 {
+		stats.Median = sorted[n/2]
+	} else {
+		stats.Median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+
+	var squares float64
+	passed := 0
+	for _, p := range sorted {
+		squares += (p - stats.Mean) * (p - stats.Mean)
+		if p >= passingScore {
+			passed++
+		}
+
+		bucket := int(p / histogramBucketSize)
+		bucket = min(max(bucket, 0), len(stats.Histogram)-1)
+		stats.Histogram[bucket].Count++
+	}
+	stats.Std_Dev = math.Sqrt(squares / float64(len(sorted)))
+	stats.Pass_Rate = float64(passed) / float64(len(sorted))
+	return stats
+}
+
+// Percent converts a score to a percentage of the total marks
+func Percent(score float64, totalMarks int) float64 {
+	if totalMarks <= 0 {
+		return 0
+	}
+	return score * 100 / float64(totalMarks)
+}
+
+// ExamReportCSV lays out an exam report as CSV records: one row per student, then the statistics
+func ExamReportCSV(report models.ExamReport) [][]string {
+	records := [][]string{{"student_id", "nis", "name", "score", "percent", "passed"}}
+	for _, s := range report.Students {
+		records = append(records, []string{strconv.Itoa(s.Student_ID), CSVText(s.NIS), CSVText(s.Name), formatFloat(s.Score), formatFloat(s.Percent), strconv.FormatBool(s.Passed)})
+	}
+	return append(records, statisticsCSV(report.Statistics, report.Passing_Score)...)
+}
+
+// ClassReportCSV lays out a class report as CSV records: one row per student, the statistics,
+// one row per exam and the comparison with the classes of the same grade
+func ClassReportCSV(report models.ClassReport) [][]string {
+	records := [][]string{{"student_id", "nis", "name", "exams_taken", "average", "passed"}}
+	for _, s := range report.Students {
+		records = append(records, []string{strconv.Itoa(s.Student_ID), CSVText(s.NIS), CSVText(s.Name), strconv.Itoa(s.Exams_Taken), formatFloat(s.Average), strconv.FormatBool(s.Passed)})
+	}
+	records = append(records, statisticsCSV(report.Statistics, report.Passing_Score)...)
+
+	records = append(records, []string{}, []string{"exam_id", "title", "count", "mean", "median", "std_dev", "pass_rate"})
+	for _, e := range report.Exams {
+		records = append(records, []string{strconv.Itoa(e.Exam_ID), CSVText(e.Title), strconv.Itoa(e.Statistics.Count), formatFloat(e.Statistics.Mean),
+			formatFloat(e.Statistics.Median), formatFloat(e.Statistics.Std_Dev), formatFloat(e.Statistics.Pass_Rate)})
+	}
+
+	records = append(records, []string{}, []string{"class_id", "class", "students", "mean", "median", "pass_rate"})
+	for _, cl := range report.Comparison {
+		records = append(records, []string{strconv.Itoa(cl.Class_ID), CSVText(cl.Name), strconv.Itoa(cl.Students), formatFloat(cl.Mean), formatFloat(cl.Median), formatFloat(cl.Pass_Rate)})
+	}
+	return records
+}
+
+func statisticsCSV(stats models.ScoreStatistics, passingScore float64) [][]string {
+	records := [][]string{
+		{},
+		{"statistic", "value"},
+		{"count", strconv.Itoa(stats.Count)},
+		{"mean", formatFloat(stats.Mean)},
+		{"median", formatFloat(stats.Median)},
+		{"std_dev", formatFloat(stats.Std_Dev)},
+		{"min", formatFloat(stats.Min)},
+		{"max", formatFloat(stats.Max)},
+		{"passing_score", formatFloat(passingScore)},
+		{"pass_rate", formatFloat(stats.Pass_Rate)},
+	}
+	for _, bucket := range stats.Histogram {
+		records = append(records, []string{fmt.Sprintf("%g-%g", bucket.From, bucket.To), strconv.Itoa(bucket.Count)})
+	}
+	return records
+}
+
+// CSVText keeps a text cell from being run as a formula when the CSV is opened in a spreadsheet
+// by prefixing it with ' when it starts with =, +, -, @, a tab or a carriage return
+func CSVText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
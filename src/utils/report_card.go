@@ -0,0 +1,393 @@
+package utils
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"project-ppl-be/src/models"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+//go:embed templates/report_card.json
+var defaultReportCardTemplate []byte
+
+// Section types of a report card layout
+const (
+	SectionHeading    = "heading"
+	SectionText       = "text"
+	SectionFields     = "fields"
+	SectionTable      = "table"
+	SectionSignatures = "signatures"
+)
+
+// ReportCardLayout describes how report cards are printed. Every text, label and value is a
+// text/template executed against models.ReportCard; table values are executed against each class
+// with its row number as .No. The grade and date functions format grades and times.
+type ReportCardLayout struct {
+	Page_Size   string              `json:"page_size"`
+	Orientation string              `json:"orientation"`
+	Margin      float64             `json:"margin"`
+	Font        string              `json:"font"`
+	Font_Size   float64             `json:"font_size"`
+	Sections    []ReportCardSection `json:"sections"`
+
+	templates *template.Template
+}
+
+// ReportCardSection is one block of the page. Sections whose When renders empty are skipped.
+type ReportCardSection struct {
+	Type    string             `json:"type"`
+	Title   string             `json:"title"`
+	Text    string             `json:"text"`
+	Size    float64            `json:"size"`
+	Align   string             `json:"align"`
+	When    string             `json:"when"`
+	Empty   string             `json:"empty"`
+	Fields  []ReportCardField  `json:"fields"`
+	Columns []ReportCardColumn `json:"columns"`
+}
+
+// ReportCardField is a labelled value, or a signature block with the label above the signing space
+type ReportCardField struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// ReportCardColumn is a column of the class table. Columns without a width share the space left.
+type ReportCardColumn struct {
+	Header string  `json:"header"`
+	Width  float64 `json:"width"`
+	Align  string  `json:"align"`
+	Value  string  `json:"value"`
+}
+
+// reportCardRow is what table values are executed against
+type reportCardRow struct {
+	No int
+	models.ReportCardClass
+}
+
+var reportCardFuncs = template.FuncMap{
+	"grade": formatGrade,
+	"date": func(t time.Time, layout string) string {
+		return t.Format(layout)
+	},
+	"upper": strings.ToUpper,
+}
+
+// ReportCardTemplate is the layout configured by ConfigureReportCardTemplate
+var ReportCardTemplate *ReportCardLayout
+
+// ConfigureReportCardTemplate loads the report card layout from the JSON file in REPORT_CARD_TEMPLATE,
+// or the built-in layout when it is not set, and stores it in ReportCardTemplate
+func ConfigureReportCardTemplate() *ReportCardLayout {
+	data := defaultReportCardTemplate
+	if path := os.Getenv("REPORT_CARD_TEMPLATE"); path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			log.Fatalf("Failed to read report card template: %v", err)
+		}
+	}
+
+	layout, err := ParseReportCardLayout(data)
+	if err != nil {
+		log.Fatalf("Failed to configure report card template: %v", err)
+	}
+	ReportCardTemplate = layout
+	return layout
+}
+
+// ParseReportCardLayout parses a JSON layout and checks it by printing a sample report card
+func ParseReportCardLayout(data []byte) (*ReportCardLayout, error) {
+	var layout ReportCardLayout
+	if err := json.Unmarshal(data, &layout); err != nil {
+		return nil, fmt.Errorf("invalid layout: %w", err)
+	}
+
+	if layout.Page_Size == "" {
+		layout.Page_Size = "A4"
+	}
+	switch strings.ToLower(layout.Orientation) {
+	case "", "portrait", "p":
+		layout.Orientation = "P"
+	case "landscape", "l":
+		layout.Orientation = "L"
+	default:
+		return nil, fmt.Errorf("unknown orientation %q", layout.Orientation)
+	}
+	if layout.Margin <= 0 {
+		layout.Margin = 15
+	}
+	if layout.Font == "" {
+		layout.Font = "Helvetica"
+	}
+	if layout.Font_Size <= 0 {
+		layout.Font_Size = 10
+	}
+	if len(layout.Sections) == 0 {
+		return nil, errors.New("layout has no sections")
+	}
+
+	layout.templates = template.New("report card").Funcs(reportCardFuncs)
+	for i, section := range layout.Sections {
+		texts := map[string]string{"title": section.Title, "text": section.Text, "when": section.When, "empty": section.Empty}
+		switch section.Type {
+		case SectionHeading, SectionText:
+		case SectionFields, SectionSignatures:
+			if len(section.Fields) == 0 {
+				return nil, fmt.Errorf("section %d: %s section has no fields", i+1, section.Type)
+			}
+			for j, field := range section.Fields {
+				texts["label"+strconv.Itoa(j)] = field.Label
+				texts["value"+strconv.Itoa(j)] = field.Value
+			}
+		case SectionTable:
+			if len(section.Columns) == 0 {
+				return nil, fmt.Errorf("section %d: table has no columns", i+1)
+			}
+			for j, column := range section.Columns {
+				if column.Width < 0 {
+					return nil, fmt.Errorf("section %d: column %q has a negative width", i+1, column.Header)
+				}
+				texts["column"+strconv.Itoa(j)] = column.Value
+			}
+		default:
+			return nil, fmt.Errorf("section %d: unknown type %q", i+1, section.Type)
+		}
+
+		for name, text := range texts {
+			if _, err := layout.templates.New(sectionTemplate(i, name)).Parse(text); err != nil {
+				return nil, fmt.Errorf("section %d: %w", i+1, err)
+			}
+		}
+	}
+
+	// Nama field yang salah baru ketahuan saat template dijalankan
+	if err := layout.Render(io.Discard, []models.ReportCard{sampleReportCard()}); err != nil {
+		return nil, err
+	}
+	return &layout, nil
+}
+
+// RenderReportCards writes the report cards as one PDF with the configured layout,
+// each card starting on a new page
+func RenderReportCards(w io.Writer, cards []models.ReportCard) error {
+	if ReportCardTemplate == nil {
+		return errors.New("report card template is not configured")
+	}
+	return ReportCardTemplate.Render(w, cards)
+}
+
+// Render writes the report cards as one PDF, each card starting on a new page
+func (l *ReportCardLayout) Render(w io.Writer, cards []models.ReportCard) error {
+	pdf := gofpdf.New(l.Orientation, "mm", l.Page_Size, "")
+	pdf.SetMargins(l.Margin, l.Margin, l.Margin)
+	pdf.SetAutoPageBreak(true, l.Margin)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	for _, card := range cards {
+		pdf.AddPage()
+		for i, section := range l.Sections {
+			if err := l.renderSection(pdf, tr, i, section, card); err != nil {
+				return fmt.Errorf("section %d: %w", i+1, err)
+			}
+		}
+	}
+	return pdf.Output(w)
+}
+
+func (l *ReportCardLayout) renderSection(pdf *gofpdf.Fpdf, tr func(string) string, i int, section ReportCardSection, card models.ReportCard) error {
+	exec := func(name string, data any) (string, error) {
+		var buf bytes.Buffer
+		err := l.templates.ExecuteTemplate(&buf, sectionTemplate(i, name), data)
+		return tr(buf.String()), err
+	}
+
+	when, err := exec("when", card)
+	if err != nil {
+		return err
+	}
+	if section.When != "" && strings.TrimSpace(when) == "" {
+		return nil
+	}
+
+	lineHeight := l.Font_Size * 0.5
+	width, _ := pdf.GetPageSize()
+	width -= 2 * l.Margin
+
+	title, err := exec("title", card)
+	if err != nil {
+		return err
+	}
+	if title != "" {
+		pdf.SetFont(l.Font, "B", l.Font_Size+1)
+		pdf.CellFormat(0, lineHeight+1, title, "", 1, "L", false, 0, "")
+	}
+	pdf.SetFont(l.Font, "", l.Font_Size)
+
+	switch section.Type {
+	case SectionHeading:
+		text, err := exec("text", card)
+		if err != nil {
+			return err
+		}
+		size := section.Size
+		if size <= 0 {
+			size = l.Font_Size + 4
+		}
+		pdf.SetFont(l.Font, "B", size)
+		pdf.MultiCell(0, size*0.5, text, "", alignOr(section.Align, "C"), false)
+
+	case SectionText:
+		text, err := exec("text", card)
+		if err != nil {
+			return err
+		}
+		pdf.MultiCell(0, lineHeight, text, "", alignOr(section.Align, "L"), false)
+
+	case SectionFields:
+		for j := range section.Fields {
+			label, err := exec("label"+strconv.Itoa(j), card)
+			if err != nil {
+				return err
+			}
+			value, err := exec("value"+strconv.Itoa(j), card)
+			if err != nil {
+				return err
+			}
+			pdf.CellFormat(40, lineHeight, label, "", 0, "L", false, 0, "")
+			pdf.CellFormat(4, lineHeight, ":", "", 0, "L", false, 0, "")
+			pdf.MultiCell(0, lineHeight, value, "", "L", false)
+		}
+
+	case SectionTable:
+		widths := columnWidths(section.Columns, width)
+		pdf.SetFont(l.Font, "B", l.Font_Size)
+		pdf.SetFillColor(230, 230, 230)
+		for j, column := range section.Columns {
+			pdf.CellFormat(widths[j], lineHeight+2, tr(column.Header), "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont(l.Font, "", l.Font_Size)
+
+		if len(card.Classes) == 0 {
+			empty, err := exec("empty", card)
+			if err != nil {
+				return err
+			}
+			pdf.CellFormat(width, lineHeight+2, empty, "1", 1, "C", false, 0, "")
+		}
+		for n, class := range card.Classes {
+			row := reportCardRow{No: n + 1, ReportCardClass: class}
+			for j, column := range section.Columns {
+				value, err := exec("column"+strconv.Itoa(j), row)
+				if err != nil {
+					return err
+				}
+				pdf.CellFormat(widths[j], lineHeight+2, value, "1", 0, alignOr(column.Align, "L"), false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+
+	case SectionSignatures:
+		columnWidth := width / float64(len(section.Fields))
+		top := pdf.GetY() + lineHeight
+		bottom := top
+		for j := range section.Fields {
+			label, err := exec("label"+strconv.Itoa(j), card)
+			if err != nil {
+				return err
+			}
+			value, err := exec("value"+strconv.Itoa(j), card)
+			if err != nil {
+				return err
+			}
+			x := l.Margin + float64(j)*columnWidth
+			pdf.SetXY(x, top)
+			pdf.MultiCell(columnWidth, lineHeight, label, "", "C", false)
+			// Ruang kosong untuk tanda tangan
+			pdf.SetXY(x, pdf.GetY()+lineHeight*4)
+			pdf.MultiCell(columnWidth, lineHeight, value, "", "C", false)
+			bottom = max(bottom, pdf.GetY())
+		}
+		pdf.SetY(bottom)
+	}
+
+	pdf.Ln(lineHeight)
+	return pdf.Error()
+}
+
+func sectionTemplate(section int, name string) string {
+	return strconv.Itoa(section) + "." + name
+}
+
+// columnWidths gives the columns without a width an equal share of the space left
+func columnWidths(columns []ReportCardColumn, total float64) []float64 {
+	widths := make([]float64, len(columns))
+	remaining, flexible := total, 0
+	for i, column := range columns {
+		widths[i] = column.Width
+		remaining -= column.Width
+		if column.Width == 0 {
+			flexible++
+		}
+	}
+	for i := range widths {
+		if widths[i] == 0 {
+			widths[i] = max(remaining, 0) / float64(flexible)
+		}
+	}
+	return widths
+}
+
+func alignOr(align, fallback string) string {
+	switch strings.ToLower(align) {
+	case "left":
+		return "L"
+	case "center":
+		return "C"
+	case "right":
+		return "R"
+	}
+	return fallback
+}
+
+// formatGrade prints a grade with at most one decimal, or "-" when there is none
+func formatGrade(grade any) string {
+	switch v := grade.(type) {
+	case *float64:
+		if v == nil {
+			return "-"
+		}
+		return strconv.FormatFloat(math.Round(*v*10)/10, 'f', -1, 64)
+	case float64:
+		return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64)
+	}
+	return fmt.Sprint(grade)
+}
+
+func sampleReportCard() models.ReportCard {
+	grade := 80.0
+	return models.ReportCard{
+		Student:  models.Student{ID: 1, Name: "Sample", NIS: "0001", Grade: 10},
+		Semester: "1",
+		Classes: []models.ReportCardClass{
+			{Class_ID: 1, Name: "Sample", Exercise_Average: &grade, Exam_Average: &grade, Final_Grade: &grade, KKM: models.DefaultKKM, Passed: true},
+			{Class_ID: 2, Name: "Sample", KKM: models.DefaultKKM},
+		},
+		Attendance:   &models.AttendanceSummary{},
+		Remarks:      "Sample",
+		Generated_At: time.Now(),
+	}
+}
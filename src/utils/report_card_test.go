@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"bytes"
+	"project-ppl-be/src/models"
+	"strings"
+	"testing"
+)
+
+func TestDefaultReportCardLayout(t *testing.T) {
+	layout, err := ParseReportCardLayout(defaultReportCardTemplate)
+	if err != nil {
+		t.Fatalf("default layout: %v", err)
+	}
+
+	// Kartu tanpa kelas dan tanpa data kehadiran tetap bisa dicetak
+	cards := []models.ReportCard{sampleReportCard(), {Student: models.Student{Name: "Siti Aisyah"}, Semester: "2"}}
+	var buf bytes.Buffer
+	if err := layout.Render(&buf, cards); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF")) {
+		t.Errorf("output is not a PDF")
+	}
+	if pages := bytes.Count(buf.Bytes(), []byte("/Type /Page\n")); pages != 2 {
+		t.Errorf("pages = %d, want one per report card", pages)
+	}
+}
+
+func TestParseReportCardLayoutRejectsInvalidLayouts(t *testing.T) {
+	tests := map[string]string{
+		"unknown section": `{"sections": [{"type": "chart"}]}`,
+		"unknown field":   `{"sections": [{"type": "text", "text": "{{.Student.Nickname}}"}]}`,
+		"bad template":    `{"sections": [{"type": "text", "text": "{{.Remarks"}]}`,
+		"empty table":     `{"sections": [{"type": "table"}]}`,
+		"no sections":     `{"page_size": "A4"}`,
+		"bad page size":   `{"page_size": "B99", "sections": [{"type": "text", "text": "x"}]}`,
+	}
+	for name, layout := range tests {
+		if _, err := ParseReportCardLayout([]byte(layout)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestFormatGrade(t *testing.T) {
+	grade := 83.333
+	got := strings.Join([]string{formatGrade(&grade), formatGrade((*float64)(nil)), formatGrade(75.0)}, " ")
+	if got != "83.3 - 75" {
+		t.Errorf("grades = %q, want %q", got, "83.3 - 75")
+	}
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"math"
+	"project-ppl-be/src/models"
+	"testing"
+)
+
+func TestScoreStatistics(t *testing.T) {
+	got := ScoreStatistics([]float64{100, 60, 80, 40}, 75)
+
+	if got.Count != 4 || got.Mean != 70 || got.Median != 70 || got.Min != 40 || got.Max != 100 {
+		t.Errorf("summary = %+v, want count 4, mean 70, median 70, min 40, max 100", got)
+	}
+	if math.Abs(got.Std_Dev-math.Sqrt(500)) > 1e-9 {
+		t.Errorf("std dev = %g, want %g", got.Std_Dev, math.Sqrt(500))
+	}
+	if got.Pass_Rate != 0.5 {
+		t.Errorf("pass rate = %g, want 0.5", got.Pass_Rate)
+	}
+
+	// 100 masuk ke kelompok terakhir
+	counts := map[float64]int{}
+	for _, bucket := range got.Histogram {
+		counts[bucket.From] = bucket.Count
+	}
+	if counts[40] != 1 || counts[60] != 1 || counts[80] != 1 || counts[90] != 1 {
+		t.Errorf("histogram = %+v", got.Histogram)
+	}
+
+	if empty := ScoreStatistics(nil, 75); empty.Count != 0 || len(empty.Histogram) != 10 {
+		t.Errorf("empty statistics = %+v", empty)
+	}
+}
+
+func TestCSVText(t *testing.T) {
+	tests := map[string]string{
+		"Budi":              "Budi",
+		"":                  "",
+		"=HYPERLINK(\"x\")": "'=HYPERLINK(\"x\")",
+		"+62812":            "'+62812",
+		"-1+1":              "'-1+1",
+		"@SUM(A1)":          "'@SUM(A1)",
+		"\t=1":              "'\t=1",
+		"a=b":               "a=b",
+	}
+	for in, want := range tests {
+		if got := CSVText(in); got != want {
+			t.Errorf("CSVText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestExamReportCSVEscapesText(t *testing.T) {
+	records := ExamReportCSV(models.ExamReport{Students: []models.StudentScore{{Student_ID: 1, NIS: "001", Name: "=cmd()"}}})
+	if got := records[1][2]; got != "'=cmd()" {
+		t.Errorf("name cell = %q, want it escaped", got)
+	}
+}
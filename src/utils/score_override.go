@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"project-ppl-be/src/models"
+)
+
+// Errors returned when a teacher overrides a question score
+var (
+	ErrQuestionNotInScore = errors.New("question is not part of this score")
+	ErrScoreAboveMax      = errors.New("score cannot be higher than the question's max score")
+)
+
+// OverrideQuestionScore applies a teacher's override to one question of a score detail and returns the
+// question's score before the change and the new total. The grader's score is kept in Auto_Score on the
+// first override. With floorAtZero the total never goes below 0, as with negative marking on exams.
+func OverrideQuestionScore(detail map[string]models.QuestionResult, req models.OverrideScoreRequest, floorAtZero bool) (previous, total float64, err error) {
+	result, ok := detail[req.Question_Key]
+	if !ok {
+		return 0, 0, ErrQuestionNotInScore
+	}
+
+	// Nilai otomatis disimpan sekali saja, pada override pertama
+	previous = result.Score
+	if result.Auto_Score == nil {
+		auto := previous
+		result.Auto_Score = &auto
+	}
+	if req.Score != nil {
+		if *req.Score > result.Max_Score {
+			return 0, 0, ErrScoreAboveMax
+		}
+		result.Score = *req.Score
+		result.Correct = result.Score == result.Max_Score
+		result.Pending = false
+	}
+	if req.Comment != "" {
+		result.Comment = req.Comment
+	}
+	detail[req.Question_Key] = result
+
+	for _, question := range detail {
+		total += question.Score
+	}
+	if floorAtZero {
+		total = math.Max(total, 0)
+	}
+	return previous, total, nil
+}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"errors"
+	"project-ppl-be/src/models"
+	"testing"
+)
+
+func overrideDetail() map[string]models.QuestionResult {
+	return map[string]models.QuestionResult{
+		"1": {Type: models.QuestionTypeMultipleChoice, Answer: "B", Score: 0, Max_Score: 2},
+		"2": {Type: models.QuestionTypeEssay, Answer: "Because", Score: 3, Max_Score: 5, Justification: "Partly right"},
+		"3": {Type: models.QuestionTypeEssay, Answer: "Later", Max_Score: 4, Pending: true},
+	}
+}
+
+func TestOverrideQuestionScore(t *testing.T) {
+	score := 2.0
+	detail := overrideDetail()
+
+	previous, total, err := OverrideQuestionScore(detail, models.OverrideScoreRequest{Question_Key: "1", Score: &score, Comment: "B is also right"}, false)
+	if err != nil {
+		t.Fatalf("OverrideQuestionScore() error = %v", err)
+	}
+	if previous != 0 || total != 5 {
+		t.Errorf("previous, total = %g, %g, want 0, 5", previous, total)
+	}
+	got := detail["1"]
+	if got.Score != 2 || !got.Correct || got.Comment != "B is also right" || got.Auto_Score == nil || *got.Auto_Score != 0 {
+		t.Errorf("overridden question = %+v", got)
+	}
+
+	// Override kedua tidak mengganti nilai otomatis
+	score = 1
+	previous, total, err = OverrideQuestionScore(detail, models.OverrideScoreRequest{Question_Key: "1", Score: &score}, false)
+	if err != nil {
+		t.Fatalf("second override error = %v", err)
+	}
+	got = detail["1"]
+	if previous != 2 || total != 4 || got.Score != 1 || got.Correct || *got.Auto_Score != 0 || got.Comment != "B is also right" {
+		t.Errorf("second override = %+v (previous %g, total %g)", got, previous, total)
+	}
+}
+
+func TestOverrideQuestionScoreGradesPendingEssay(t *testing.T) {
+	score := 4.0
+	detail := overrideDetail()
+	_, total, err := OverrideQuestionScore(detail, models.OverrideScoreRequest{Question_Key: "3", Score: &score}, false)
+	if err != nil {
+		t.Fatalf("OverrideQuestionScore() error = %v", err)
+	}
+	if got := detail["3"]; got.Pending || got.Score != 4 || !got.Correct {
+		t.Errorf("graded essay = %+v, want it no longer pending", got)
+	}
+	if total != 7 {
+		t.Errorf("total = %g, want 7", total)
+	}
+}
+
+func TestOverrideQuestionScoreCommentOnly(t *testing.T) {
+	detail := overrideDetail()
+	previous, total, err := OverrideQuestionScore(detail, models.OverrideScoreRequest{Question_Key: "2", Comment: "Cite a source"}, false)
+	if err != nil {
+		t.Fatalf("OverrideQuestionScore() error = %v", err)
+	}
+	got := detail["2"]
+	if previous != 3 || total != 3 || got.Score != 3 || got.Comment != "Cite a source" || got.Justification != "Partly right" {
+		t.Errorf("commented question = %+v (previous %g, total %g)", got, previous, total)
+	}
+}
+
+func TestOverrideQuestionScoreFloorsAtZero(t *testing.T) {
+	zero := 0.0
+	detail := map[string]models.QuestionResult{
+		"1": {Type: models.QuestionTypeMultipleChoice, Score: -1, Max_Score: 2},
+		"2": {Type: models.QuestionTypeEssay, Score: 3, Max_Score: 5},
+	}
+	_, total, err := OverrideQuestionScore(detail, models.OverrideScoreRequest{Question_Key: "2", Score: &zero}, true)
+	if err != nil || total != 0 {
+		t.Errorf("floored total = %g, %v, want 0", total, err)
+	}
+
+	detail["2"] = models.QuestionResult{Type: models.QuestionTypeEssay, Score: 3, Max_Score: 5}
+	_, total, _ = OverrideQuestionScore(detail, models.OverrideScoreRequest{Question_Key: "2", Score: &zero}, false)
+	if total != -1 {
+		t.Errorf("total without floor = %g, want -1", total)
+	}
+}
+
+func TestOverrideQuestionScoreRejects(t *testing.T) {
+	tooHigh := 6.0
+	tests := []struct {
+		name string
+		req  models.OverrideScoreRequest
+		want error
+	}{
+		{"unknown question", models.OverrideScoreRequest{Question_Key: "9", Comment: "?"}, ErrQuestionNotInScore},
+		{"above max score", models.OverrideScoreRequest{Question_Key: "2", Score: &tooHigh}, ErrScoreAboveMax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detail := overrideDetail()
+			if _, _, err := OverrideQuestionScore(detail, tt.req, false); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+			if got := detail["2"]; got.Score != 3 || got.Auto_Score != nil {
+				t.Errorf("rejected override changed the detail: %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"project-ppl-be/src/models"
+	"strconv"
+	"strings"
+)
+
+// IsAutoScored reports whether a question type can be scored without a grader
+func IsAutoScored(questionType string) bool {
+	switch questionType {
+	case models.QuestionTypeMultipleChoice, models.QuestionTypeMultiSelect, models.QuestionTypeNumeric,
+		models.QuestionTypeTrueFalse, models.QuestionTypeMatching, models.QuestionTypeFillBlank:
+		return true
+	}
+	return false
+}
+
+// ScoreQuestion scores an auto-scored question out of its weight.
+// Multi select, matching and fill blank questions give partial credit unless the question turns it off,
+// the other types are all or nothing. A wrong multiple choice or true/false answer loses
+// negativeMarking times the question weight; unanswered questions never lose points.
+func ScoreQuestion(q models.Question, answer any, negativeMarking float64) models.QuestionResult {
+	var credit float64
+
+	switch q.Type {
+	case models.QuestionTypeMultipleChoice:
+		credit = boolCredit(AnswerText(answer) == q.Correct_Answer)
+	case models.QuestionTypeTrueFalse:
+		credit = boolCredit(strings.EqualFold(strings.TrimSpace(AnswerText(answer)), q.Correct_Answer))
+	case models.QuestionTypeMultiSelect:
+		credit = multiSelectCredit(q, answerList(answer))
+	case models.QuestionTypeNumeric:
+		credit = numericCredit(q, answer)
+	case models.QuestionTypeMatching:
+		credit = matchingCredit(q, answer)
+	case models.QuestionTypeFillBlank:
+		credit = fillBlankCredit(q, answerList(answer))
+	}
+
+	if credit < 1 && !q.PartialCreditEnabled() {
+		credit = 0
+	}
+
+	score := credit * q.Weight
+	answered := strings.TrimSpace(AnswerText(answer)) != ""
+	if credit == 0 && answered && negativeMarking > 0 &&
+		(q.Type == models.QuestionTypeMultipleChoice || q.Type == models.QuestionTypeTrueFalse) {
+		score = -negativeMarking * q.Weight
+	}
+
+	return models.QuestionResult{
+		Type:      q.Type,
+		Score:     score,
+		Max_Score: q.Weight,
+		Correct:   credit == 1,
+		Answer:    answer,
+	}
+}
+
+// AnswerText converts a single answer value to text, e.g. for essays and multiple choice
+func AnswerText(answer any) string {
+	switch v := answer.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func boolCredit(correct bool) float64 {
+	if correct {
+		return 1
+	}
+	return 0
+}
+
+// multiSelectCredit gives one share per correct option chosen and takes one share per wrong option chosen
+func multiSelectCredit(q models.Question, chosen []string) float64 {
+	correct := make(map[string]bool, len(q.Correct_Answers))
+	for _, key := range q.Correct_Answers {
+		correct[key] = true
+	}
+
+	seen := make(map[string]bool, len(chosen))
+	var hits, misses int
+	for _, key := range chosen {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if correct[key] {
+			hits++
+		} else {
+			misses++
+		}
+	}
+
+	return math.Max(0, float64(hits-misses)/float64(len(correct)))
+}
+
+func numericCredit(q models.Question, answer any) float64 {
+	if q.Numeric_Answer == nil {
+		return 0
+	}
+
+	var value float64
+	switch v := answer.(type) {
+	case float64:
+		value = v
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(strings.ReplaceAll(v, ",", ".")), 64)
+		if err != nil {
+			return 0
+		}
+		value = parsed
+	default:
+		return 0
+	}
+
+	expected := *q.Numeric_Answer
+	allowed := q.Tolerance
+	if q.Tolerance_Type == models.ToleranceRelative {
+		allowed = q.Tolerance * math.Abs(expected)
+	}
+	return boolCredit(math.Abs(value-expected) <= allowed)
+}
+
+// matchingCredit gives one share per left item matched to its right item
+func matchingCredit(q models.Question, answer any) float64 {
+	matches, ok := answer.(map[string]any)
+	if !ok || len(q.Pairs) == 0 {
+		return 0
+	}
+
+	var hits int
+	for _, pair := range q.Pairs {
+		if AnswerText(matches[pair.Left]) == pair.Right {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(q.Pairs))
+}
+
+// fillBlankCredit gives one share per blank filled with an accepted answer
+func fillBlankCredit(q models.Question, filled []string) float64 {
+	if len(q.Blanks) == 0 {
+		return 0
+	}
+
+	var hits int
+	for i, blank := range q.Blanks {
+		if i >= len(filled) {
+			break
+		}
+		given := strings.TrimSpace(filled[i])
+		for _, accepted := range blank.Accepted {
+			if given == accepted || (!blank.Case_Sensitive && strings.EqualFold(given, accepted)) {
+				hits++
+				break
+			}
+		}
+	}
+	return float64(hits) / float64(len(q.Blanks))
+}
+
+// answerList reads a list answer sent either as a JSON array or as a single value
+func answerList(answer any) []string {
+	switch v := answer.(type) {
+	case []any:
+		list := make([]string, len(v))
+		for i, item := range v {
+			list[i] = AnswerText(item)
+		}
+		return list
+	case nil:
+		return nil
+	default:
+		return []string{AnswerText(v)}
+	}
+}
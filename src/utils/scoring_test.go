@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"project-ppl-be/src/models"
+	"testing"
+)
+
+func TestScoreQuestion(t *testing.T) {
+	off := false
+	answer := 9.81
+	zero := 0.0
+	matching := models.Question{Type: models.QuestionTypeMatching, Weight: 4, Pairs: []models.MatchingPair{
+		{Left: "Jakarta", Right: "Indonesia"},
+		{Left: "Tokyo", Right: "Jepang"},
+		{Left: "Bangkok", Right: "Thailand"},
+		{Left: "Manila", Right: "Filipina"},
+	}}
+	fillBlank := models.Question{Type: models.QuestionTypeFillBlank, Weight: 2, Blanks: []models.Blank{
+		{Accepted: []string{"Soekarno", "Sukarno"}},
+		{Accepted: []string{"Hatta"}, Case_Sensitive: true},
+	}}
+	multiSelect := models.Question{Type: models.QuestionTypeMultiSelect, Weight: 3, Correct_Answers: []string{"A", "B", "C"}}
+
+	tests := []struct {
+		name     string
+		question models.Question
+		answer   any
+		negative float64
+		score    float64
+		correct  bool
+	}{
+		{"multiple choice correct", models.Question{Type: models.QuestionTypeMultipleChoice, Weight: 2, Correct_Answer: "B"}, "B", 0, 2, true},
+		{"multiple choice wrong", models.Question{Type: models.QuestionTypeMultipleChoice, Weight: 2, Correct_Answer: "B"}, "C", 0, 0, false},
+		{"true false ignores case and spaces", models.Question{Type: models.QuestionTypeTrueFalse, Weight: 1, Correct_Answer: "true"}, " TRUE ", 0, 1, true},
+		{"true false wrong", models.Question{Type: models.QuestionTypeTrueFalse, Weight: 1, Correct_Answer: "true"}, "false", 0, 0, false},
+
+		{"multi select all correct", multiSelect, []any{"C", "A", "B"}, 0, 3, true},
+		{"multi select partial", multiSelect, []any{"A", "B"}, 0, 2, false},
+		{"multi select wrong option takes a share", multiSelect, []any{"A", "B", "D"}, 0, 1, false},
+		{"multi select duplicates count once", multiSelect, []any{"A", "A", "A"}, 0, 1, false},
+		{"multi select floors at zero", multiSelect, []any{"A", "D", "E"}, 0, 0, false},
+		{"multi select without partial credit", models.Question{Type: models.QuestionTypeMultiSelect, Weight: 3, Correct_Answers: []string{"A", "B"}, Partial_Credit: &off}, []any{"A"}, 0, 0, false},
+
+		{"numeric exact", models.Question{Type: models.QuestionTypeNumeric, Weight: 2, Numeric_Answer: &answer}, 9.81, 0, 2, true},
+		{"numeric within absolute tolerance", models.Question{Type: models.QuestionTypeNumeric, Weight: 2, Numeric_Answer: &answer, Tolerance: 0.1, Tolerance_Type: models.ToleranceAbsolute}, 9.9, 0, 2, true},
+		{"numeric outside absolute tolerance", models.Question{Type: models.QuestionTypeNumeric, Weight: 2, Numeric_Answer: &answer, Tolerance: 0.05, Tolerance_Type: models.ToleranceAbsolute}, 9.9, 0, 0, false},
+		{"numeric within relative tolerance", models.Question{Type: models.QuestionTypeNumeric, Weight: 2, Numeric_Answer: &answer, Tolerance: 0.02, Tolerance_Type: models.ToleranceRelative}, 10.0, 0, 2, true},
+		{"numeric outside relative tolerance", models.Question{Type: models.QuestionTypeNumeric, Weight: 2, Numeric_Answer: &answer, Tolerance: 0.01, Tolerance_Type: models.ToleranceRelative}, 10.0, 0, 0, false},
+		{"numeric relative tolerance around zero", models.Question{Type: models.QuestionTypeNumeric, Weight: 1, Numeric_Answer: &zero, Tolerance: 0.5, Tolerance_Type: models.ToleranceRelative}, 0.1, 0, 0, false},
+		{"numeric text with decimal comma", models.Question{Type: models.QuestionTypeNumeric, Weight: 2, Numeric_Answer: &answer}, " 9,81 ", 0, 2, true},
+		{"numeric text that is not a number", models.Question{Type: models.QuestionTypeNumeric, Weight: 2, Numeric_Answer: &answer}, "sembilan", 0, 0, false},
+		{"numeric without an answer key", models.Question{Type: models.QuestionTypeNumeric, Weight: 2}, 9.81, 0, 0, false},
+
+		{"matching all pairs", matching, map[string]any{"Jakarta": "Indonesia", "Tokyo": "Jepang", "Bangkok": "Thailand", "Manila": "Filipina"}, 0, 4, true},
+		{"matching partial", matching, map[string]any{"Jakarta": "Indonesia", "Tokyo": "Thailand", "Bangkok": "Jepang", "Manila": "Filipina"}, 0, 2, false},
+		{"matching without partial credit", func() models.Question { q := matching; q.Partial_Credit = &off; return q }(), map[string]any{"Jakarta": "Indonesia"}, 0, 0, false},
+		{"matching answer that is not an object", matching, []any{"Indonesia"}, 0, 0, false},
+
+		{"fill blank all accepted", fillBlank, []any{"sukarno", " Hatta "}, 0, 2, true},
+		{"fill blank case sensitive blank", fillBlank, []any{"Soekarno", "hatta"}, 0, 1, false},
+		{"fill blank missing blanks", fillBlank, []any{"Soekarno"}, 0, 1, false},
+		{"fill blank single value", fillBlank, "Sukarno", 0, 1, false},
+
+		{"negative marking on a wrong multiple choice", models.Question{Type: models.QuestionTypeMultipleChoice, Weight: 2, Correct_Answer: "B"}, "C", 0.25, -0.5, false},
+		{"negative marking on a wrong true false", models.Question{Type: models.QuestionTypeTrueFalse, Weight: 1, Correct_Answer: "true"}, "false", 0.5, -0.5, false},
+		{"negative marking skips unanswered questions", models.Question{Type: models.QuestionTypeMultipleChoice, Weight: 2, Correct_Answer: "B"}, "  ", 0.25, 0, false},
+		{"negative marking skips correct answers", models.Question{Type: models.QuestionTypeMultipleChoice, Weight: 2, Correct_Answer: "B"}, "B", 0.25, 2, true},
+		{"negative marking keeps multi select at zero", multiSelect, []any{"D", "E"}, 0.25, 0, false},
+		{"negative marking keeps matching at zero", matching, map[string]any{"Jakarta": "Jepang"}, 0.25, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ScoreQuestion(tt.question, tt.answer, tt.negative)
+			if !approxEqual(result.Score, tt.score) || result.Correct != tt.correct {
+				t.Errorf("ScoreQuestion() = %g (correct %v), want %g (correct %v)", result.Score, result.Correct, tt.score, tt.correct)
+			}
+			if result.Max_Score != tt.question.Weight || result.Type != tt.question.Type {
+				t.Errorf("ScoreQuestion() max score %g type %q, want %g %q", result.Max_Score, result.Type, tt.question.Weight, tt.question.Type)
+			}
+		})
+	}
+}
+
+func TestScoreQuestionLeavesEssaysToGraders(t *testing.T) {
+	essay := models.Question{Type: models.QuestionTypeEssay, Weight: 5, Correct_Answer: "Fotosintesis"}
+	if IsAutoScored(essay.Type) {
+		t.Error("IsAutoScored(essay) = true, want false")
+	}
+	if result := ScoreQuestion(essay, "Fotosintesis", 0.25); result.Score != 0 || result.Correct {
+		t.Errorf("ScoreQuestion(essay) = %g (correct %v), want 0", result.Score, result.Correct)
+	}
+}
+
+func approxEqual(a, b float64) bool {
+	const epsilon = 1e-9
+	return a-b < epsilon && b-a < epsilon
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"github.com/jackc/pgx/v5/pgxpool"
+	"errors"
 	"fmt"
 	"time"
 	"context"
@@ -19,6 +20,27 @@ func CheckExamStatus(startTime, endTime time.Time) string {
 	}
 }
 
+// Errors returned by CheckSubmissionWindow
+var (
+	ErrExamNotStarted = errors.New("exam has not started yet")
+	ErrExamClosed     = errors.New("exam submission window has closed")
+)
+
+// CheckSubmissionWindow reports whether an answer submitted at now is accepted.
+// Answers after endTime are accepted during the grace period and returned with their lateness.
+func CheckSubmissionWindow(now, startTime, endTime time.Time, grace time.Duration) (time.Duration, error) {
+	switch {
+	case now.Before(startTime):
+		return 0, ErrExamNotStarted
+	case !now.After(endTime):
+		return 0, nil
+	case now.After(endTime.Add(grace)):
+		return 0, ErrExamClosed
+	default:
+		return now.Sub(endTime), nil
+	}
+}
+
 func UpdateAllExamStatus(db *pgxpool.Pool) error {
 	ctx := context.Background()
 
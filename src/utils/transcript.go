@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"project-ppl-be/src/models"
+	"strconv"
+	"time"
+)
+
+// TranscriptCSV lays out a transcript as CSV records: the student, one row per class with its
+// final grade, then one row per exercise attempt and exam. Empty grades are left blank and text is
+// escaped with CSVText.
+func TranscriptCSV(t models.Transcript) [][]string {
+	records := [][]string{
+		{"schema_version", t.Schema_Version},
+		{"generated_at", t.Generated_At.Format(time.RFC3339)},
+		{"student_id", strconv.Itoa(t.Student.ID)},
+		{"nis", CSVText(t.Student.NIS)},
+		{"name", CSVText(t.Student.Name)},
+		{"grade", strconv.Itoa(t.Student.Grade)},
+		{"status", t.Student.Status},
+		{"cumulative_average", formatOptional(t.Cumulative_Average)},
+		{},
+		{"class_id", "class", "teacher", "exercise_average", "exam_average", "final_grade", "kkm", "passed"},
+	}
+	for _, c := range t.Classes {
+		records = append(records, []string{strconv.Itoa(c.Class_ID), CSVText(c.Name), CSVText(c.Teacher_Name), formatOptional(c.Exercise_Average),
+			formatOptional(c.Exam_Average), formatOptional(c.Final_Grade), formatFloat(c.KKM), strconv.FormatBool(c.Passed)})
+	}
+
+	records = append(records, []string{}, []string{"class_id", "type", "score_id", "item_id", "title", "attempt", "remedial_of", "score", "total_marks", "percent", "recorded_at", "pre_remedial_score"})
+	for _, c := range t.Classes {
+		for _, kind := range []struct {
+			name   string
+			scores []models.TranscriptScore
+		}{{"exercise", c.Exercises}, {"exam", c.Exams}} {
+			for _, s := range kind.scores {
+				recordedAt := ""
+				if s.Recorded_At != nil {
+					recordedAt = s.Recorded_At.Format(time.RFC3339)
+				}
+				records = append(records, []string{strconv.Itoa(c.Class_ID), kind.name, strconv.Itoa(s.Score_ID), strconv.Itoa(s.Item_ID), CSVText(s.Title),
+					formatOptionalInt(s.Attempt), formatOptionalInt(s.Remedial_Of), formatOptional(s.Score), strconv.Itoa(s.Total_Marks),
+					formatOptional(s.Percent), recordedAt, formatOptional(s.Pre_Remedial_Score)})
+			}
+		}
+	}
+	return records
+}
+
+func formatOptional(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return formatFloat(*v)
+}
+
+func formatOptionalInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
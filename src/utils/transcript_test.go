@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"project-ppl-be/src/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTranscriptCSV(t *testing.T) {
+	score, percent, final, preRemedial := 8.0, 80.0, 82.5, 60.0
+	attempt := 2
+	recorded := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	transcript := models.Transcript{
+		Schema_Version: models.TranscriptSchemaVersion,
+		Student:        models.TranscriptStudent{ID: 7, Name: "Budi", NIS: "123", Grade: 10},
+		Classes: []models.TranscriptClass{{
+			Class_ID: 3, Name: "Matematika", Teacher_Name: "Bu Sari", Final_Grade: &final, KKM: 75, Passed: true,
+			Exercises: []models.TranscriptScore{{Score_ID: 11, Item_ID: 4, Title: "Latihan 1", Attempt: &attempt, Score: &score, Total_Marks: 10, Percent: &percent, Recorded_At: &recorded}},
+			Exams: []models.TranscriptScore{
+				{Score_ID: 12, Item_ID: 5, Title: "UTS", Total_Marks: 100},
+				{Score_ID: 13, Item_ID: 6, Title: "=UAS", Score: &final, Pre_Remedial_Score: &preRemedial, Total_Marks: 100},
+			},
+		}},
+	}
+
+	var rows []string
+	for _, record := range TranscriptCSV(transcript) {
+		rows = append(rows, strings.Join(record, ","))
+	}
+	got := strings.Join(rows, "\n")
+
+	for _, want := range []string{
+		"cumulative_average,",
+		"3,Matematika,Bu Sari,,,82.50,75.00,true",
+		"3,exercise,11,4,Latihan 1,2,,8.00,10,80.00,2025-03-01T08:00:00Z,",
+		// Ujian yang esainya belum dinilai tidak punya nilai
+		"3,exam,12,5,UTS,,,,100,,,",
+		// Judul tidak dijalankan sebagai rumus, nilai sebelum remedial ikut dicetak
+		"3,exam,13,6,'=UAS,,,82.50,100,,,60.00",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("CSV is missing %q:\n%s", want, got)
+		}
+	}
+}